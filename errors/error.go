@@ -6,6 +6,10 @@ const (
 	NotFound      ServiceError = "NotFound"
 	Internal      ServiceError = "Internal"
 	RaceCondition ServiceError = "RaceCondition"
+	ImageTooLarge ServiceError = "ImageTooLarge"
+
+	InvalidSignature ServiceError = "InvalidSignature"
+	SignatureExpired ServiceError = "SignatureExpired"
 )
 
 type (
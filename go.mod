@@ -13,5 +13,6 @@ require (
 	github.com/spf13/viper v1.6.3
 	github.com/stretchr/testify v1.5.1
 	github.com/vektah/gqlparser/v2 v2.0.1
+	go.etcd.io/bbolt v1.3.5
 	go.mongodb.org/mongo-driver v1.3.3
 )
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -36,46 +37,193 @@ type Config struct {
 }
 
 type ResolverRoot interface {
+	ImageConnection() ImageConnectionResolver
 	Mutation() MutationResolver
 	Query() QueryResolver
+	Size() SizeResolver
+	Subscription() SubscriptionResolver
 }
 
 type DirectiveRoot struct {
 }
 
 type ComplexityRoot struct {
+	Exif struct {
+		DateTimeOriginal func(childComplexity int) int
+		ExposureTime     func(childComplexity int) int
+		FNumber          func(childComplexity int) int
+		FocalLength      func(childComplexity int) int
+		HasGps           func(childComplexity int) int
+		Iso              func(childComplexity int) int
+		LensModel        func(childComplexity int) int
+		Make             func(childComplexity int) int
+		Model            func(childComplexity int) int
+		Software         func(childComplexity int) int
+	}
+
+	FocalPoint struct {
+		X func(childComplexity int) int
+		Y func(childComplexity int) int
+	}
+
 	Image struct {
-		ClientName func(childComplexity int) int
-		ID         func(childComplexity int) int
-		MimeType   func(childComplexity int) int
-		Path       func(childComplexity int) int
-		Size       func(childComplexity int) int
-		Sizes      func(childComplexity int) int
-		UploadAt   func(childComplexity int) int
+		ClientName     func(childComplexity int) int
+		ColorModel     func(childComplexity int) int
+		Exif           func(childComplexity int) int
+		FocalPoint     func(childComplexity int) int
+		Format         func(childComplexity int) int
+		HasAlpha       func(childComplexity int) int
+		Hash           func(childComplexity int) int
+		Height         func(childComplexity int) int
+		ID             func(childComplexity int) int
+		JobID          func(childComplexity int) int
+		MetadataPolicy func(childComplexity int) int
+		MimeType       func(childComplexity int) int
+		Orientation    func(childComplexity int) int
+		Path           func(childComplexity int) int
+		Size           func(childComplexity int) int
+		Sizes          func(childComplexity int) int
+		UploadAt       func(childComplexity int) int
+		Width          func(childComplexity int) int
+	}
+
+	ImageConnection struct {
+		Edges      func(childComplexity int) int
+		PageInfo   func(childComplexity int) int
+		TotalCount func(childComplexity int) int
+	}
+
+	ImageEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
+	Job struct {
+		Attempts  func(childComplexity int) int
+		CreatedAt func(childComplexity int) int
+		Error     func(childComplexity int) int
+		ID        func(childComplexity int) int
+		ImageID   func(childComplexity int) int
+		Status    func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
 	}
 
 	Mutation struct {
-		ResizeImage func(childComplexity int, imageID string, sizes []*model.SizeInput) int
-		UploadImage func(childComplexity int, image graphql.Upload, sizes []*model.SizeInput) int
+		CropImage       func(childComplexity int, id string, rect model.RectInput) int
+		DeleteImage     func(childComplexity int, id string) int
+		DeletePreset    func(childComplexity int, name string) int
+		DeleteSize      func(childComplexity int, imageID string, width int, height int) int
+		DeleteWatermark func(childComplexity int, id string) int
+		FlipImage       func(childComplexity int, id string, axis model.FlipAxis) int
+		ResizeImage     func(childComplexity int, imageID string, sizes []*model.SizeInput, presets []string) int
+		RotateImage     func(childComplexity int, id string, degrees int) int
+		SavePreset      func(childComplexity int, name string, size model.SizeInput, rerender *bool) int
+		SaveWatermark   func(childComplexity int, id string, overlay *graphql.Upload, position *model.Anchor, opacity *float64, scale *float64, tile *bool) int
+		SetFocalPoint   func(childComplexity int, imageID string, x float64, y float64) int
+		UploadImage     func(childComplexity int, image graphql.Upload, sizes []*model.SizeInput, presets []string, async *bool, metadataPolicy *model.MetadataPolicy, dedupe *bool) int
+	}
+
+	Operation struct {
+		Name  func(childComplexity int) int
+		Value func(childComplexity int) int
+	}
+
+	PageInfo struct {
+		EndCursor       func(childComplexity int) int
+		HasNextPage     func(childComplexity int) int
+		HasPreviousPage func(childComplexity int) int
+		StartCursor     func(childComplexity int) int
+	}
+
+	Preset struct {
+		Anchor      func(childComplexity int) int
+		Background  func(childComplexity int) int
+		Compression func(childComplexity int) int
+		Format      func(childComplexity int) int
+		Frame       func(childComplexity int) int
+		Height      func(childComplexity int) int
+		Mode        func(childComplexity int) int
+		Name        func(childComplexity int) int
+		Operations  func(childComplexity int) int
+		Quality     func(childComplexity int) int
+		UpdatedAt   func(childComplexity int) int
+		Version     func(childComplexity int) int
+		Watermark   func(childComplexity int) int
+		Width       func(childComplexity int) int
 	}
 
 	Query struct {
-		Images func(childComplexity int, limit int, offset int) int
+		Image            func(childComplexity int, id string) int
+		Images           func(childComplexity int, limit int, offset int, filter *model.ImageFilter, sort *model.ImageSort) int
+		ImagesConnection func(childComplexity int, first int, after *string, filter *model.ImageFilter) int
+		Job              func(childComplexity int, id string) int
+		Presets          func(childComplexity int) int
+		Watermarks       func(childComplexity int) int
 	}
 
 	Size struct {
-		Height func(childComplexity int) int
-		Path   func(childComplexity int) int
-		Width  func(childComplexity int) int
+		Anchor      func(childComplexity int) int
+		Background  func(childComplexity int) int
+		Compression func(childComplexity int) int
+		Format      func(childComplexity int) int
+		Frame       func(childComplexity int) int
+		Height      func(childComplexity int) int
+		Mode        func(childComplexity int) int
+		Operations  func(childComplexity int) int
+		Path        func(childComplexity int) int
+		Preset      func(childComplexity int) int
+		Quality     func(childComplexity int) int
+		Status      func(childComplexity int) int
+		URL         func(childComplexity int, expiresIn *int) int
+		Watermark   func(childComplexity int) int
+		Width       func(childComplexity int) int
+	}
+
+	Subscription struct {
+		ImageProcessed func(childComplexity int, imageID string) int
+	}
+
+	Watermark struct {
+		ID        func(childComplexity int) int
+		Opacity   func(childComplexity int) int
+		Position  func(childComplexity int) int
+		Scale     func(childComplexity int) int
+		Tile      func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+		Version   func(childComplexity int) int
 	}
 }
 
+type ImageConnectionResolver interface {
+	TotalCount(ctx context.Context, obj *model.ImageConnection) (int, error)
+}
 type MutationResolver interface {
-	UploadImage(ctx context.Context, image graphql.Upload, sizes []*model.SizeInput) (*model.Image, error)
-	ResizeImage(ctx context.Context, imageID string, sizes []*model.SizeInput) (*model.Image, error)
+	UploadImage(ctx context.Context, image graphql.Upload, sizes []*model.SizeInput, presets []string, async *bool, metadataPolicy *model.MetadataPolicy, dedupe *bool) (*model.Image, error)
+	ResizeImage(ctx context.Context, imageID string, sizes []*model.SizeInput, presets []string) (*model.Image, error)
+	DeleteImage(ctx context.Context, id string) (bool, error)
+	DeleteSize(ctx context.Context, imageID string, width int, height int) (*model.Image, error)
+	SetFocalPoint(ctx context.Context, imageID string, x float64, y float64) (*model.Image, error)
+	RotateImage(ctx context.Context, id string, degrees int) (*model.Image, error)
+	FlipImage(ctx context.Context, id string, axis model.FlipAxis) (*model.Image, error)
+	CropImage(ctx context.Context, id string, rect model.RectInput) (*model.Image, error)
+	SavePreset(ctx context.Context, name string, size model.SizeInput, rerender *bool) (*model.Preset, error)
+	DeletePreset(ctx context.Context, name string) (bool, error)
+	SaveWatermark(ctx context.Context, id string, overlay *graphql.Upload, position *model.Anchor, opacity *float64, scale *float64, tile *bool) (*model.Watermark, error)
+	DeleteWatermark(ctx context.Context, id string) (bool, error)
 }
 type QueryResolver interface {
-	Images(ctx context.Context, limit int, offset int) ([]*model.Image, error)
+	Image(ctx context.Context, id string) (*model.Image, error)
+	Images(ctx context.Context, limit int, offset int, filter *model.ImageFilter, sort *model.ImageSort) ([]*model.Image, error)
+	ImagesConnection(ctx context.Context, first int, after *string, filter *model.ImageFilter) (*model.ImageConnection, error)
+	Job(ctx context.Context, id string) (*model.Job, error)
+	Presets(ctx context.Context) ([]*model.Preset, error)
+	Watermarks(ctx context.Context) ([]*model.Watermark, error)
+}
+type SizeResolver interface {
+	URL(ctx context.Context, obj *model.Size, expiresIn *int) (string, error)
+}
+type SubscriptionResolver interface {
+	ImageProcessed(ctx context.Context, imageID string) (<-chan *model.Image, error)
 }
 
 type executableSchema struct {
@@ -93,6 +241,90 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 	_ = ec
 	switch typeName + "." + field {
 
+	case "Exif.dateTimeOriginal":
+		if e.complexity.Exif.DateTimeOriginal == nil {
+			break
+		}
+
+		return e.complexity.Exif.DateTimeOriginal(childComplexity), true
+
+	case "Exif.exposureTime":
+		if e.complexity.Exif.ExposureTime == nil {
+			break
+		}
+
+		return e.complexity.Exif.ExposureTime(childComplexity), true
+
+	case "Exif.fNumber":
+		if e.complexity.Exif.FNumber == nil {
+			break
+		}
+
+		return e.complexity.Exif.FNumber(childComplexity), true
+
+	case "Exif.focalLength":
+		if e.complexity.Exif.FocalLength == nil {
+			break
+		}
+
+		return e.complexity.Exif.FocalLength(childComplexity), true
+
+	case "Exif.hasGps":
+		if e.complexity.Exif.HasGps == nil {
+			break
+		}
+
+		return e.complexity.Exif.HasGps(childComplexity), true
+
+	case "Exif.iso":
+		if e.complexity.Exif.Iso == nil {
+			break
+		}
+
+		return e.complexity.Exif.Iso(childComplexity), true
+
+	case "Exif.lensModel":
+		if e.complexity.Exif.LensModel == nil {
+			break
+		}
+
+		return e.complexity.Exif.LensModel(childComplexity), true
+
+	case "Exif.make":
+		if e.complexity.Exif.Make == nil {
+			break
+		}
+
+		return e.complexity.Exif.Make(childComplexity), true
+
+	case "Exif.model":
+		if e.complexity.Exif.Model == nil {
+			break
+		}
+
+		return e.complexity.Exif.Model(childComplexity), true
+
+	case "Exif.software":
+		if e.complexity.Exif.Software == nil {
+			break
+		}
+
+		return e.complexity.Exif.Software(childComplexity), true
+
+	case "FocalPoint.x":
+		if e.complexity.FocalPoint.X == nil {
+			break
+		}
+
+		return e.complexity.FocalPoint.X(childComplexity), true
+
+	case "FocalPoint.y":
+		if e.complexity.FocalPoint.Y == nil {
+			break
+		}
+
+		return e.complexity.FocalPoint.Y(childComplexity), true
+
 	case "Image.clientName":
 		if e.complexity.Image.ClientName == nil {
 			break
@@ -100,6 +332,55 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Image.ClientName(childComplexity), true
 
+	case "Image.colorModel":
+		if e.complexity.Image.ColorModel == nil {
+			break
+		}
+
+		return e.complexity.Image.ColorModel(childComplexity), true
+
+	case "Image.exif":
+		if e.complexity.Image.Exif == nil {
+			break
+		}
+
+		return e.complexity.Image.Exif(childComplexity), true
+
+	case "Image.focalPoint":
+		if e.complexity.Image.FocalPoint == nil {
+			break
+		}
+
+		return e.complexity.Image.FocalPoint(childComplexity), true
+
+	case "Image.format":
+		if e.complexity.Image.Format == nil {
+			break
+		}
+
+		return e.complexity.Image.Format(childComplexity), true
+
+	case "Image.hasAlpha":
+		if e.complexity.Image.HasAlpha == nil {
+			break
+		}
+
+		return e.complexity.Image.HasAlpha(childComplexity), true
+
+	case "Image.hash":
+		if e.complexity.Image.Hash == nil {
+			break
+		}
+
+		return e.complexity.Image.Hash(childComplexity), true
+
+	case "Image.height":
+		if e.complexity.Image.Height == nil {
+			break
+		}
+
+		return e.complexity.Image.Height(childComplexity), true
+
 	case "Image.id":
 		if e.complexity.Image.ID == nil {
 			break
@@ -107,6 +388,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Image.ID(childComplexity), true
 
+	case "Image.jobId":
+		if e.complexity.Image.JobID == nil {
+			break
+		}
+
+		return e.complexity.Image.JobID(childComplexity), true
+
+	case "Image.metadataPolicy":
+		if e.complexity.Image.MetadataPolicy == nil {
+			break
+		}
+
+		return e.complexity.Image.MetadataPolicy(childComplexity), true
+
 	case "Image.mimeType":
 		if e.complexity.Image.MimeType == nil {
 			break
@@ -114,6 +409,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Image.MimeType(childComplexity), true
 
+	case "Image.orientation":
+		if e.complexity.Image.Orientation == nil {
+			break
+		}
+
+		return e.complexity.Image.Orientation(childComplexity), true
+
 	case "Image.path":
 		if e.complexity.Image.Path == nil {
 			break
@@ -142,456 +444,4750 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Image.UploadAt(childComplexity), true
 
-	case "Mutation.resizeImage":
-		if e.complexity.Mutation.ResizeImage == nil {
+	case "Image.width":
+		if e.complexity.Image.Width == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_resizeImage_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Image.Width(childComplexity), true
+
+	case "ImageConnection.edges":
+		if e.complexity.ImageConnection.Edges == nil {
+			break
 		}
 
-		return e.complexity.Mutation.ResizeImage(childComplexity, args["imageId"].(string), args["sizes"].([]*model.SizeInput)), true
+		return e.complexity.ImageConnection.Edges(childComplexity), true
 
-	case "Mutation.uploadImage":
-		if e.complexity.Mutation.UploadImage == nil {
+	case "ImageConnection.pageInfo":
+		if e.complexity.ImageConnection.PageInfo == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_uploadImage_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.ImageConnection.PageInfo(childComplexity), true
+
+	case "ImageConnection.totalCount":
+		if e.complexity.ImageConnection.TotalCount == nil {
+			break
 		}
 
-		return e.complexity.Mutation.UploadImage(childComplexity, args["image"].(graphql.Upload), args["sizes"].([]*model.SizeInput)), true
+		return e.complexity.ImageConnection.TotalCount(childComplexity), true
 
-	case "Query.images":
-		if e.complexity.Query.Images == nil {
+	case "ImageEdge.cursor":
+		if e.complexity.ImageEdge.Cursor == nil {
 			break
 		}
 
-		args, err := ec.field_Query_images_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.ImageEdge.Cursor(childComplexity), true
+
+	case "ImageEdge.node":
+		if e.complexity.ImageEdge.Node == nil {
+			break
 		}
 
-		return e.complexity.Query.Images(childComplexity, args["limit"].(int), args["offset"].(int)), true
+		return e.complexity.ImageEdge.Node(childComplexity), true
 
-	case "Size.height":
-		if e.complexity.Size.Height == nil {
+	case "Job.attempts":
+		if e.complexity.Job.Attempts == nil {
 			break
 		}
 
-		return e.complexity.Size.Height(childComplexity), true
+		return e.complexity.Job.Attempts(childComplexity), true
 
-	case "Size.path":
-		if e.complexity.Size.Path == nil {
+	case "Job.createdAt":
+		if e.complexity.Job.CreatedAt == nil {
 			break
 		}
 
-		return e.complexity.Size.Path(childComplexity), true
+		return e.complexity.Job.CreatedAt(childComplexity), true
 
-	case "Size.width":
-		if e.complexity.Size.Width == nil {
+	case "Job.error":
+		if e.complexity.Job.Error == nil {
 			break
 		}
 
-		return e.complexity.Size.Width(childComplexity), true
-
-	}
-	return 0, false
-}
+		return e.complexity.Job.Error(childComplexity), true
 
-func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
-	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e}
-	first := true
+	case "Job.id":
+		if e.complexity.Job.ID == nil {
+			break
+		}
 
-	switch rc.Operation.Operation {
-	case ast.Query:
-		return func(ctx context.Context) *graphql.Response {
-			if !first {
-				return nil
-			}
-			first = false
-			data := ec._Query(ctx, rc.Operation.SelectionSet)
-			var buf bytes.Buffer
-			data.MarshalGQL(&buf)
+		return e.complexity.Job.ID(childComplexity), true
 
-			return &graphql.Response{
-				Data: buf.Bytes(),
-			}
+	case "Job.imageId":
+		if e.complexity.Job.ImageID == nil {
+			break
 		}
-	case ast.Mutation:
-		return func(ctx context.Context) *graphql.Response {
-			if !first {
-				return nil
-			}
-			first = false
-			data := ec._Mutation(ctx, rc.Operation.SelectionSet)
-			var buf bytes.Buffer
-			data.MarshalGQL(&buf)
 
-			return &graphql.Response{
-				Data: buf.Bytes(),
-			}
+		return e.complexity.Job.ImageID(childComplexity), true
+
+	case "Job.status":
+		if e.complexity.Job.Status == nil {
+			break
 		}
 
-	default:
-		return graphql.OneShot(graphql.ErrorResponse(ctx, "unsupported GraphQL operation"))
-	}
-}
+		return e.complexity.Job.Status(childComplexity), true
 
-type executionContext struct {
-	*graphql.OperationContext
-	*executableSchema
-}
+	case "Job.updatedAt":
+		if e.complexity.Job.UpdatedAt == nil {
+			break
+		}
 
-func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
-	if ec.DisableIntrospection {
-		return nil, errors.New("introspection disabled")
-	}
-	return introspection.WrapSchema(parsedSchema), nil
-}
+		return e.complexity.Job.UpdatedAt(childComplexity), true
 
-func (ec *executionContext) introspectType(name string) (*introspection.Type, error) {
-	if ec.DisableIntrospection {
-		return nil, errors.New("introspection disabled")
-	}
-	return introspection.WrapTypeFromDef(parsedSchema, parsedSchema.Types[name]), nil
-}
+	case "Mutation.cropImage":
+		if e.complexity.Mutation.CropImage == nil {
+			break
+		}
 
-var sources = []*ast.Source{
-	&ast.Source{Name: "graph/schema.graphqls", Input: `scalar Time
-scalar Upload
+		args, err := ec.field_Mutation_cropImage_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
 
-type Image {
-    id: ID!
-    path: String!
-    clientName: String!
-    mimeType: String!
-    size: Int!
-    uploadAt: Time
-    sizes: [Size!]!
-}
+		return e.complexity.Mutation.CropImage(childComplexity, args["id"].(string), args["rect"].(model.RectInput)), true
 
-type Size {
-    path: String!
-    width: Int!
-    height: Int!
-}
+	case "Mutation.deleteImage":
+		if e.complexity.Mutation.DeleteImage == nil {
+			break
+		}
 
-input SizeInput {
-    width: Int!
-    height: Int!
-}
+		args, err := ec.field_Mutation_deleteImage_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
 
-type Mutation {
-    # upload image and resize
-    uploadImage(image: Upload!, sizes: [SizeInput!]!): Image!
-    # resize existance image
-    resizeImage(imageId: ID!, sizes: [SizeInput!]!): Image!
-}
+		return e.complexity.Mutation.DeleteImage(childComplexity, args["id"].(string)), true
 
-type Query {
-    # list all images with pagination
-    images(limit: Int! = 20, offset: Int! = 0): [Image!]!
-}`, BuiltIn: false},
-}
-var parsedSchema = gqlparser.MustLoadSchema(sources...)
+	case "Mutation.deletePreset":
+		if e.complexity.Mutation.DeletePreset == nil {
+			break
+		}
 
-// endregion ************************** generated!.gotpl **************************
+		args, err := ec.field_Mutation_deletePreset_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
 
-// region    ***************************** args.gotpl *****************************
+		return e.complexity.Mutation.DeletePreset(childComplexity, args["name"].(string)), true
 
-func (ec *executionContext) field_Mutation_resizeImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["imageId"]; ok {
-		arg0, err = ec.unmarshalNID2string(ctx, tmp)
-		if err != nil {
-			return nil, err
+	case "Mutation.deleteSize":
+		if e.complexity.Mutation.DeleteSize == nil {
+			break
 		}
-	}
-	args["imageId"] = arg0
-	var arg1 []*model.SizeInput
-	if tmp, ok := rawArgs["sizes"]; ok {
-		arg1, err = ec.unmarshalNSizeInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInputᚄ(ctx, tmp)
+
+		args, err := ec.field_Mutation_deleteSize_args(context.TODO(), rawArgs)
 		if err != nil {
-			return nil, err
+			return 0, false
 		}
-	}
-	args["sizes"] = arg1
-	return args, nil
-}
 
-func (ec *executionContext) field_Mutation_uploadImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 graphql.Upload
-	if tmp, ok := rawArgs["image"]; ok {
-		arg0, err = ec.unmarshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx, tmp)
-		if err != nil {
-			return nil, err
+		return e.complexity.Mutation.DeleteSize(childComplexity, args["imageId"].(string), args["width"].(int), args["height"].(int)), true
+
+	case "Mutation.deleteWatermark":
+		if e.complexity.Mutation.DeleteWatermark == nil {
+			break
 		}
-	}
-	args["image"] = arg0
-	var arg1 []*model.SizeInput
-	if tmp, ok := rawArgs["sizes"]; ok {
-		arg1, err = ec.unmarshalNSizeInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInputᚄ(ctx, tmp)
+
+		args, err := ec.field_Mutation_deleteWatermark_args(context.TODO(), rawArgs)
 		if err != nil {
-			return nil, err
+			return 0, false
 		}
-	}
-	args["sizes"] = arg1
-	return args, nil
-}
 
-func (ec *executionContext) field_Query___type_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["name"]; ok {
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
-		if err != nil {
-			return nil, err
+		return e.complexity.Mutation.DeleteWatermark(childComplexity, args["id"].(string)), true
+
+	case "Mutation.flipImage":
+		if e.complexity.Mutation.FlipImage == nil {
+			break
 		}
-	}
-	args["name"] = arg0
-	return args, nil
-}
 
-func (ec *executionContext) field_Query_images_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 int
-	if tmp, ok := rawArgs["limit"]; ok {
-		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
+		args, err := ec.field_Mutation_flipImage_args(context.TODO(), rawArgs)
 		if err != nil {
-			return nil, err
+			return 0, false
 		}
-	}
-	args["limit"] = arg0
-	var arg1 int
-	if tmp, ok := rawArgs["offset"]; ok {
-		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+
+		return e.complexity.Mutation.FlipImage(childComplexity, args["id"].(string), args["axis"].(model.FlipAxis)), true
+
+	case "Mutation.resizeImage":
+		if e.complexity.Mutation.ResizeImage == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_resizeImage_args(context.TODO(), rawArgs)
 		if err != nil {
-			return nil, err
+			return 0, false
 		}
-	}
-	args["offset"] = arg1
-	return args, nil
-}
 
-func (ec *executionContext) field___Type_enumValues_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 bool
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		arg0, err = ec.unmarshalOBoolean2bool(ctx, tmp)
+		return e.complexity.Mutation.ResizeImage(childComplexity, args["imageId"].(string), args["sizes"].([]*model.SizeInput), args["presets"].([]string)), true
+
+	case "Mutation.rotateImage":
+		if e.complexity.Mutation.RotateImage == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_rotateImage_args(context.TODO(), rawArgs)
 		if err != nil {
-			return nil, err
+			return 0, false
 		}
-	}
-	args["includeDeprecated"] = arg0
-	return args, nil
-}
 
-func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
-	args := map[string]interface{}{}
-	var arg0 bool
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		arg0, err = ec.unmarshalOBoolean2bool(ctx, tmp)
+		return e.complexity.Mutation.RotateImage(childComplexity, args["id"].(string), args["degrees"].(int)), true
+
+	case "Mutation.savePreset":
+		if e.complexity.Mutation.SavePreset == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_savePreset_args(context.TODO(), rawArgs)
 		if err != nil {
-			return nil, err
+			return 0, false
 		}
-	}
-	args["includeDeprecated"] = arg0
-	return args, nil
-}
 
-// endregion ***************************** args.gotpl *****************************
+		return e.complexity.Mutation.SavePreset(childComplexity, args["name"].(string), args["size"].(model.SizeInput), args["rerender"].(*bool)), true
 
-// region    ************************** directives.gotpl **************************
+	case "Mutation.saveWatermark":
+		if e.complexity.Mutation.SaveWatermark == nil {
+			break
+		}
 
-// endregion ************************** directives.gotpl **************************
+		args, err := ec.field_Mutation_saveWatermark_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
 
-// region    **************************** field.gotpl *****************************
+		return e.complexity.Mutation.SaveWatermark(childComplexity, args["id"].(string), args["overlay"].(*graphql.Upload), args["position"].(*model.Anchor), args["opacity"].(*float64), args["scale"].(*float64), args["tile"].(*bool)), true
 
-func (ec *executionContext) _Image_id(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
-	defer func() {
-		if r := recover(); r != nil {
-			ec.Error(ctx, ec.Recover(ctx, r))
-			ret = graphql.Null
+	case "Mutation.setFocalPoint":
+		if e.complexity.Mutation.SetFocalPoint == nil {
+			break
 		}
-	}()
-	fc := &graphql.FieldContext{
-		Object:   "Image",
-		Field:    field,
-		Args:     nil,
-		IsMethod: false,
-	}
 
-	ctx = graphql.WithFieldContext(ctx, fc)
-	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
-		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
+		args, err := ec.field_Mutation_setFocalPoint_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
-		return graphql.Null
-	}
-	res := resTmp.(string)
-	fc.Result = res
-	return ec.marshalNID2string(ctx, field.Selections, res)
-}
 
-func (ec *executionContext) _Image_path(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
-	defer func() {
-		if r := recover(); r != nil {
-			ec.Error(ctx, ec.Recover(ctx, r))
-			ret = graphql.Null
+		return e.complexity.Mutation.SetFocalPoint(childComplexity, args["imageId"].(string), args["x"].(float64), args["y"].(float64)), true
+
+	case "Mutation.uploadImage":
+		if e.complexity.Mutation.UploadImage == nil {
+			break
 		}
-	}()
-	fc := &graphql.FieldContext{
-		Object:   "Image",
-		Field:    field,
-		Args:     nil,
-		IsMethod: false,
-	}
 
-	ctx = graphql.WithFieldContext(ctx, fc)
-	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
-		ctx = rctx // use context from middleware stack in children
-		return obj.Path, nil
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
+		args, err := ec.field_Mutation_uploadImage_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
 		}
-		return graphql.Null
-	}
-	res := resTmp.(string)
-	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
-}
 
-func (ec *executionContext) _Image_clientName(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
-	defer func() {
-		if r := recover(); r != nil {
-			ec.Error(ctx, ec.Recover(ctx, r))
-			ret = graphql.Null
+		return e.complexity.Mutation.UploadImage(childComplexity, args["image"].(graphql.Upload), args["sizes"].([]*model.SizeInput), args["presets"].([]string), args["async"].(*bool), args["metadataPolicy"].(*model.MetadataPolicy), args["dedupe"].(*bool)), true
+
+	case "Operation.name":
+		if e.complexity.Operation.Name == nil {
+			break
 		}
-	}()
-	fc := &graphql.FieldContext{
-		Object:   "Image",
-		Field:    field,
-		Args:     nil,
-		IsMethod: false,
-	}
 
-	ctx = graphql.WithFieldContext(ctx, fc)
-	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
-		ctx = rctx // use context from middleware stack in children
-		return obj.ClientName, nil
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
+		return e.complexity.Operation.Name(childComplexity), true
+
+	case "Operation.value":
+		if e.complexity.Operation.Value == nil {
+			break
 		}
-		return graphql.Null
-	}
-	res := resTmp.(string)
-	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
-}
 
-func (ec *executionContext) _Image_mimeType(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
-	defer func() {
-		if r := recover(); r != nil {
-			ec.Error(ctx, ec.Recover(ctx, r))
-			ret = graphql.Null
+		return e.complexity.Operation.Value(childComplexity), true
+
+	case "PageInfo.endCursor":
+		if e.complexity.PageInfo.EndCursor == nil {
+			break
 		}
-	}()
-	fc := &graphql.FieldContext{
-		Object:   "Image",
-		Field:    field,
-		Args:     nil,
-		IsMethod: false,
-	}
 
-	ctx = graphql.WithFieldContext(ctx, fc)
-	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
-		ctx = rctx // use context from middleware stack in children
-		return obj.MimeType, nil
-	})
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
+		return e.complexity.PageInfo.EndCursor(childComplexity), true
+
+	case "PageInfo.hasNextPage":
+		if e.complexity.PageInfo.HasNextPage == nil {
+			break
 		}
-		return graphql.Null
-	}
-	res := resTmp.(string)
-	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
-}
 
-func (ec *executionContext) _Image_size(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
-	defer func() {
-		if r := recover(); r != nil {
-			ec.Error(ctx, ec.Recover(ctx, r))
-			ret = graphql.Null
+		return e.complexity.PageInfo.HasNextPage(childComplexity), true
+
+	case "PageInfo.hasPreviousPage":
+		if e.complexity.PageInfo.HasPreviousPage == nil {
+			break
 		}
-	}()
-	fc := &graphql.FieldContext{
-		Object:   "Image",
-		Field:    field,
-		Args:     nil,
-		IsMethod: false,
-	}
+
+		return e.complexity.PageInfo.HasPreviousPage(childComplexity), true
+
+	case "PageInfo.startCursor":
+		if e.complexity.PageInfo.StartCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.StartCursor(childComplexity), true
+
+	case "Preset.anchor":
+		if e.complexity.Preset.Anchor == nil {
+			break
+		}
+
+		return e.complexity.Preset.Anchor(childComplexity), true
+
+	case "Preset.background":
+		if e.complexity.Preset.Background == nil {
+			break
+		}
+
+		return e.complexity.Preset.Background(childComplexity), true
+
+	case "Preset.compression":
+		if e.complexity.Preset.Compression == nil {
+			break
+		}
+
+		return e.complexity.Preset.Compression(childComplexity), true
+
+	case "Preset.format":
+		if e.complexity.Preset.Format == nil {
+			break
+		}
+
+		return e.complexity.Preset.Format(childComplexity), true
+
+	case "Preset.frame":
+		if e.complexity.Preset.Frame == nil {
+			break
+		}
+
+		return e.complexity.Preset.Frame(childComplexity), true
+
+	case "Preset.height":
+		if e.complexity.Preset.Height == nil {
+			break
+		}
+
+		return e.complexity.Preset.Height(childComplexity), true
+
+	case "Preset.mode":
+		if e.complexity.Preset.Mode == nil {
+			break
+		}
+
+		return e.complexity.Preset.Mode(childComplexity), true
+
+	case "Preset.name":
+		if e.complexity.Preset.Name == nil {
+			break
+		}
+
+		return e.complexity.Preset.Name(childComplexity), true
+
+	case "Preset.operations":
+		if e.complexity.Preset.Operations == nil {
+			break
+		}
+
+		return e.complexity.Preset.Operations(childComplexity), true
+
+	case "Preset.quality":
+		if e.complexity.Preset.Quality == nil {
+			break
+		}
+
+		return e.complexity.Preset.Quality(childComplexity), true
+
+	case "Preset.updatedAt":
+		if e.complexity.Preset.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Preset.UpdatedAt(childComplexity), true
+
+	case "Preset.version":
+		if e.complexity.Preset.Version == nil {
+			break
+		}
+
+		return e.complexity.Preset.Version(childComplexity), true
+
+	case "Preset.watermark":
+		if e.complexity.Preset.Watermark == nil {
+			break
+		}
+
+		return e.complexity.Preset.Watermark(childComplexity), true
+
+	case "Preset.width":
+		if e.complexity.Preset.Width == nil {
+			break
+		}
+
+		return e.complexity.Preset.Width(childComplexity), true
+
+	case "Query.image":
+		if e.complexity.Query.Image == nil {
+			break
+		}
+
+		args, err := ec.field_Query_image_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Image(childComplexity, args["id"].(string)), true
+
+	case "Query.images":
+		if e.complexity.Query.Images == nil {
+			break
+		}
+
+		args, err := ec.field_Query_images_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Images(childComplexity, args["limit"].(int), args["offset"].(int), args["filter"].(*model.ImageFilter), args["sort"].(*model.ImageSort)), true
+
+	case "Query.imagesConnection":
+		if e.complexity.Query.ImagesConnection == nil {
+			break
+		}
+
+		args, err := ec.field_Query_imagesConnection_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.ImagesConnection(childComplexity, args["first"].(int), args["after"].(*string), args["filter"].(*model.ImageFilter)), true
+
+	case "Query.job":
+		if e.complexity.Query.Job == nil {
+			break
+		}
+
+		args, err := ec.field_Query_job_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Job(childComplexity, args["id"].(string)), true
+
+	case "Query.presets":
+		if e.complexity.Query.Presets == nil {
+			break
+		}
+
+		return e.complexity.Query.Presets(childComplexity), true
+
+	case "Query.watermarks":
+		if e.complexity.Query.Watermarks == nil {
+			break
+		}
+
+		return e.complexity.Query.Watermarks(childComplexity), true
+
+	case "Size.anchor":
+		if e.complexity.Size.Anchor == nil {
+			break
+		}
+
+		return e.complexity.Size.Anchor(childComplexity), true
+
+	case "Size.background":
+		if e.complexity.Size.Background == nil {
+			break
+		}
+
+		return e.complexity.Size.Background(childComplexity), true
+
+	case "Size.compression":
+		if e.complexity.Size.Compression == nil {
+			break
+		}
+
+		return e.complexity.Size.Compression(childComplexity), true
+
+	case "Size.format":
+		if e.complexity.Size.Format == nil {
+			break
+		}
+
+		return e.complexity.Size.Format(childComplexity), true
+
+	case "Size.frame":
+		if e.complexity.Size.Frame == nil {
+			break
+		}
+
+		return e.complexity.Size.Frame(childComplexity), true
+
+	case "Size.height":
+		if e.complexity.Size.Height == nil {
+			break
+		}
+
+		return e.complexity.Size.Height(childComplexity), true
+
+	case "Size.mode":
+		if e.complexity.Size.Mode == nil {
+			break
+		}
+
+		return e.complexity.Size.Mode(childComplexity), true
+
+	case "Size.operations":
+		if e.complexity.Size.Operations == nil {
+			break
+		}
+
+		return e.complexity.Size.Operations(childComplexity), true
+
+	case "Size.path":
+		if e.complexity.Size.Path == nil {
+			break
+		}
+
+		return e.complexity.Size.Path(childComplexity), true
+
+	case "Size.preset":
+		if e.complexity.Size.Preset == nil {
+			break
+		}
+
+		return e.complexity.Size.Preset(childComplexity), true
+
+	case "Size.quality":
+		if e.complexity.Size.Quality == nil {
+			break
+		}
+
+		return e.complexity.Size.Quality(childComplexity), true
+
+	case "Size.status":
+		if e.complexity.Size.Status == nil {
+			break
+		}
+
+		return e.complexity.Size.Status(childComplexity), true
+
+	case "Size.url":
+		if e.complexity.Size.URL == nil {
+			break
+		}
+
+		args, err := ec.field_Size_url_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Size.URL(childComplexity, args["expiresIn"].(*int)), true
+
+	case "Size.watermark":
+		if e.complexity.Size.Watermark == nil {
+			break
+		}
+
+		return e.complexity.Size.Watermark(childComplexity), true
+
+	case "Size.width":
+		if e.complexity.Size.Width == nil {
+			break
+		}
+
+		return e.complexity.Size.Width(childComplexity), true
+
+	case "Subscription.imageProcessed":
+		if e.complexity.Subscription.ImageProcessed == nil {
+			break
+		}
+
+		args, err := ec.field_Subscription_imageProcessed_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Subscription.ImageProcessed(childComplexity, args["imageId"].(string)), true
+
+	case "Watermark.id":
+		if e.complexity.Watermark.ID == nil {
+			break
+		}
+
+		return e.complexity.Watermark.ID(childComplexity), true
+
+	case "Watermark.opacity":
+		if e.complexity.Watermark.Opacity == nil {
+			break
+		}
+
+		return e.complexity.Watermark.Opacity(childComplexity), true
+
+	case "Watermark.position":
+		if e.complexity.Watermark.Position == nil {
+			break
+		}
+
+		return e.complexity.Watermark.Position(childComplexity), true
+
+	case "Watermark.scale":
+		if e.complexity.Watermark.Scale == nil {
+			break
+		}
+
+		return e.complexity.Watermark.Scale(childComplexity), true
+
+	case "Watermark.tile":
+		if e.complexity.Watermark.Tile == nil {
+			break
+		}
+
+		return e.complexity.Watermark.Tile(childComplexity), true
+
+	case "Watermark.updatedAt":
+		if e.complexity.Watermark.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Watermark.UpdatedAt(childComplexity), true
+
+	case "Watermark.version":
+		if e.complexity.Watermark.Version == nil {
+			break
+		}
+
+		return e.complexity.Watermark.Version(childComplexity), true
+
+	}
+	return 0, false
+}
+
+func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
+	rc := graphql.GetOperationContext(ctx)
+	ec := executionContext{rc, e}
+	first := true
+
+	switch rc.Operation.Operation {
+	case ast.Query:
+		return func(ctx context.Context) *graphql.Response {
+			if !first {
+				return nil
+			}
+			first = false
+			data := ec._Query(ctx, rc.Operation.SelectionSet)
+			var buf bytes.Buffer
+			data.MarshalGQL(&buf)
+
+			return &graphql.Response{
+				Data: buf.Bytes(),
+			}
+		}
+	case ast.Mutation:
+		return func(ctx context.Context) *graphql.Response {
+			if !first {
+				return nil
+			}
+			first = false
+			data := ec._Mutation(ctx, rc.Operation.SelectionSet)
+			var buf bytes.Buffer
+			data.MarshalGQL(&buf)
+
+			return &graphql.Response{
+				Data: buf.Bytes(),
+			}
+		}
+	case ast.Subscription:
+		next := ec._Subscription(ctx, rc.Operation.SelectionSet)
+
+		var buf bytes.Buffer
+		return func(ctx context.Context) *graphql.Response {
+			buf.Reset()
+			data := next()
+
+			if data == nil {
+				return nil
+			}
+			data.MarshalGQL(&buf)
+
+			return &graphql.Response{
+				Data: buf.Bytes(),
+			}
+		}
+
+	default:
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "unsupported GraphQL operation"))
+	}
+}
+
+type executionContext struct {
+	*graphql.OperationContext
+	*executableSchema
+}
+
+func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
+	if ec.DisableIntrospection {
+		return nil, errors.New("introspection disabled")
+	}
+	return introspection.WrapSchema(parsedSchema), nil
+}
+
+func (ec *executionContext) introspectType(name string) (*introspection.Type, error) {
+	if ec.DisableIntrospection {
+		return nil, errors.New("introspection disabled")
+	}
+	return introspection.WrapTypeFromDef(parsedSchema, parsedSchema.Types[name]), nil
+}
+
+var sources = []*ast.Source{
+	&ast.Source{Name: "graph/schema.graphqls", Input: `scalar Time
+scalar Upload
+
+type Image {
+    id: ID!
+    path: String!
+    clientName: String!
+    mimeType: String!
+    # format detected by the content of the original
+    format: ImageFormat
+    size: Int!
+    # hex encoded SHA-256 of the stored original
+    hash: String
+    # dimensions of the original as displayed, i.e. with the EXIF orientation applied
+    width: Int
+    height: Int
+    # EXIF orientation (1-8)
+    orientation: Int
+    colorModel: ColorModel
+    # whether the colour model of the original has an alpha channel
+    hasAlpha: Boolean!
+    exif: Exif
+    # metadata policy applied to the stored original
+    metadataPolicy: MetadataPolicy
+    # point the SMART anchor crops around
+    focalPoint: FocalPoint
+    uploadAt: Time
+    sizes: [Size!]!
+    # background job which resizes pending sizes
+    jobId: ID
+}
+
+enum ColorModel {
+    GRAY
+    GRAY_ALPHA
+    RGB
+    RGBA
+    PALETTED
+    YCBCR
+    CMYK
+}
+
+enum MetadataPolicy {
+    # the original is stored as uploaded
+    KEEP
+    # GPS coordinates are removed from the original
+    STRIP_GPS
+    # all metadata except the orientation and colour profile is removed from the original
+    STRIP_ALL
+}
+
+# camera details of the original, GPS coordinates aren't kept
+type Exif {
+    make: String
+    model: String
+    lensModel: String
+    software: String
+    # local time of the camera, e.g. 2020:05:01 10:00:00
+    dateTimeOriginal: String
+    # e.g. 1/125
+    exposureTime: String
+    fNumber: Float
+    iso: Int
+    focalLength: Float
+    # whether the original has GPS coordinates
+    hasGps: Boolean!
+}
+
+# point of the original as displayed, coordinates are relative to the dimensions (0-1)
+type FocalPoint {
+    x: Float!
+    y: Float!
+}
+
+enum ResizeMode {
+    # resize to exact dimensions ignoring the aspect ratio
+    STRETCH
+    # resize to fit into the box keeping the aspect ratio
+    FIT
+    # resize to cover the box keeping the aspect ratio and crop the rest
+    FILL
+    # fit into the box and fill the rest with the background colour
+    PAD
+    # same as fit, but never upscale
+    LIMIT
+}
+
+enum Anchor {
+    CENTER
+    TOP_LEFT
+    TOP
+    TOP_RIGHT
+    LEFT
+    RIGHT
+    BOTTOM_LEFT
+    BOTTOM
+    BOTTOM_RIGHT
+    # around the focal point of the image, around the most detailed part if it isn't set
+    SMART
+}
+
+enum ImageFormat {
+    JPEG
+    PNG
+    GIF
+    BMP
+    TIFF
+}
+
+enum PNGCompression {
+    DEFAULT
+    NONE
+    BEST_SPEED
+    BEST_COMPRESSION
+}
+
+enum OperationName {
+    # resize by the mode, applied first unless it's placed in the list
+    RESIZE
+    # gaussian blur, value is the sigma (0.1-50)
+    BLUR
+    # value is the sigma (0.1-50)
+    SHARPEN
+    # value is the gamma (0.1-10), less than 1 darkens
+    GAMMA
+    # value is the percentage (-100-100)
+    CONTRAST
+    # value is the percentage (-100-100)
+    BRIGHTNESS
+    # value is the percentage (-100-500)
+    SATURATION
+    GRAYSCALE
+    INVERT
+}
+
+# step of the transform pipeline of a size
+type Operation {
+    name: OperationName!
+    value: Float
+}
+
+input OperationInput {
+    name: OperationName!
+    value: Float
+}
+
+enum SizeStatus {
+    READY
+    # the size is being resized in background
+    PENDING
+    FAILED
+}
+
+type Size {
+    path: String!
+    width: Int!
+    height: Int!
+    mode: ResizeMode!
+    anchor: Anchor
+    background: String
+    format: ImageFormat!
+    quality: Int
+    compression: PNGCompression
+    # transform pipeline in the order it's applied, empty if the size is only resized
+    operations: [Operation!]!
+    # ID of the watermark profile composited with the size
+    watermark: ID
+    # frame of an animated original the still size is rendered from
+    frame: Int
+    status: SizeStatus!
+    # name of the preset the size was created by
+    preset: String
+    # signed url of the size, valid for expiresIn seconds, from 1 second to the configured maximum
+    url(expiresIn: Int = 3600): String!
+}
+
+type Preset {
+    name: String!
+    width: Int!
+    height: Int!
+    mode: ResizeMode!
+    anchor: Anchor
+    background: String
+    # output format, the format of the original if empty
+    format: ImageFormat
+    quality: Int
+    compression: PNGCompression
+    operations: [Operation!]!
+    watermark: ID
+    frame: Int
+    version: Int!
+    updatedAt: Time!
+}
+
+input SizeInput {
+    width: Int!
+    height: Int!
+    mode: ResizeMode = STRETCH
+    # crop anchor for the FILL mode
+    anchor: Anchor = CENTER
+    # hex colour (#rrggbb) for the PAD mode
+    background: String = "#ffffff"
+    # output format, the format of the original by default
+    format: ImageFormat
+    # JPEG quality (1-100)
+    quality: Int
+    # PNG compression level
+    compression: PNGCompression
+    # adjustments and effects applied in order, e.g. [RESIZE, SHARPEN(0.5), GRAYSCALE]
+    operations: [OperationInput!]! = []
+    # ID of the watermark profile composited after the operations
+    watermark: ID
+    # index of the poster frame of an animated original rendered by formats other than GIF,
+    # GIF sizes keep all frames
+    frame: Int = 0
+}
+
+# profile of an overlay composited with sizes which refer to it
+type Watermark {
+    id: ID!
+    # anchor the overlay is placed at, ignored by tiled watermarks
+    position: Anchor!
+    # opacity of the overlay (0-1)
+    opacity: Float!
+    # width of the overlay relative to the width of the size (0-1)
+    scale: Float!
+    # whether the overlay is repeated over the whole size
+    tile: Boolean!
+    version: Int!
+    updatedAt: Time!
+}
+
+enum JobStatus {
+    PENDING
+    RUNNING
+    DONE
+    FAILED
+}
+
+type Job {
+    id: ID!
+    imageId: ID!
+    status: JobStatus!
+    error: String
+    attempts: Int!
+    createdAt: Time!
+    updatedAt: Time!
+}
+
+type Mutation {
+    # upload image and resize, async uploads return pending sizes which are resized in background,
+    # dedupe uploads return the image uploaded with the same content with missing sizes added
+    uploadImage(
+        image: Upload!
+        sizes: [SizeInput!]! = []
+        presets: [String!]! = []
+        async: Boolean = false
+        metadataPolicy: MetadataPolicy
+        dedupe: Boolean = false
+    ): Image!
+    # resize existance image
+    resizeImage(imageId: ID!, sizes: [SizeInput!]! = [], presets: [String!]! = []): Image!
+    # delete image with the original and all sizes, stored files are removed in background
+    deleteImage(id: ID!): Boolean!
+    # delete all sizes of the image with the dimensions, sizes pending in a background job can't be deleted until it finishes
+    deleteSize(imageId: ID!, width: Int!, height: Int!): Image!
+    # set the focal point of the image, sizes cropped by the SMART anchor are re-rendered around it
+    setFocalPoint(imageId: ID!, x: Float!, y: Float!): Image!
+    # rotate the original clockwise by 90, 180 or 270 degrees, the edits below replace the original by a derived one,
+    # sizes are re-rendered from it and the focal point moves with the content
+    rotateImage(id: ID!, degrees: Int!): Image!
+    # mirror the original across the axis
+    flipImage(id: ID!, axis: FlipAxis!): Image!
+    # crop the original as displayed to the rectangle, the focal point is reset if it's cropped out
+    cropImage(id: ID!, rect: RectInput!): Image!
+    # create or update the preset, existing sizes of the preset are re-rendered in background if rerender is set
+    savePreset(name: String!, size: SizeInput!, rerender: Boolean = false): Preset!
+    # delete the preset, sizes created by the preset are kept
+    deletePreset(name: String!): Boolean!
+    # create or update the watermark profile, the overlay is required by new profiles,
+    # sizes composited with the profile already are kept as is
+    saveWatermark(
+        id: ID!
+        overlay: Upload
+        position: Anchor = BOTTOM_RIGHT
+        opacity: Float = 0.5
+        scale: Float = 0.25
+        tile: Boolean = false
+    ): Watermark!
+    # delete the watermark profile which isn't used by presets, sizes composited with it are kept and re-rendered by edits
+    deleteWatermark(id: ID!): Boolean!
+}
+
+enum FlipAxis {
+    # swap left and right
+    HORIZONTAL
+    # swap top and bottom
+    VERTICAL
+}
+
+# rectangle of the original as displayed in pixels
+input RectInput {
+    x: Int!
+    y: Int!
+    width: Int!
+    height: Int!
+}
+
+input ImageFilter {
+    mimeType: String
+    # inclusive lower bound of the upload time
+    uploadedAfter: Time
+    # exclusive upper bound of the upload time
+    uploadedBefore: Time
+    clientNamePrefix: String
+}
+
+enum ImageSortField {
+    UPLOAD_AT
+    SIZE
+}
+
+enum SortDirection {
+    ASC
+    DESC
+}
+
+input ImageSort {
+    field: ImageSortField! = UPLOAD_AT
+    direction: SortDirection! = ASC
+}
+
+type PageInfo {
+    hasNextPage: Boolean!
+    hasPreviousPage: Boolean!
+    startCursor: String
+    endCursor: String
+}
+
+type ImageEdge {
+    cursor: String!
+    node: Image!
+}
+
+type ImageConnection {
+    edges: [ImageEdge!]!
+    pageInfo: PageInfo!
+    # number of images matching the filter, counted only if requested
+    totalCount: Int!
+}
+
+type Query {
+    # single image
+    image(id: ID!): Image!
+    # list all images with pagination
+    images(limit: Int! = 20, offset: Int! = 0, filter: ImageFilter, sort: ImageSort): [Image!]!
+    # list images ordered by the upload time with cursor pagination
+    imagesConnection(first: Int! = 20, after: String, filter: ImageFilter): ImageConnection!
+    # background resize job
+    job(id: ID!): Job!
+    # all presets ordered by name
+    presets: [Preset!]!
+    # watermark profiles which aren't deleted ordered by ID
+    watermarks: [Watermark!]!
+}
+
+type Subscription {
+    # receives the image each time its background job finishes
+    imageProcessed(imageId: ID!): Image!
+}`, BuiltIn: false},
+}
+var parsedSchema = gqlparser.MustLoadSchema(sources...)
+
+// endregion ************************** generated!.gotpl **************************
+
+// region    ***************************** args.gotpl *****************************
+
+func (ec *executionContext) field_Mutation_cropImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	var arg1 model.RectInput
+	if tmp, ok := rawArgs["rect"]; ok {
+		arg1, err = ec.unmarshalNRectInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐRectInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["rect"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_deleteImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_deletePreset_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_deleteSize_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["imageId"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["imageId"] = arg0
+	var arg1 int
+	if tmp, ok := rawArgs["width"]; ok {
+		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["width"] = arg1
+	var arg2 int
+	if tmp, ok := rawArgs["height"]; ok {
+		arg2, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["height"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_deleteWatermark_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_flipImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	var arg1 model.FlipAxis
+	if tmp, ok := rawArgs["axis"]; ok {
+		arg1, err = ec.unmarshalNFlipAxis2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐFlipAxis(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["axis"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_resizeImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["imageId"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["imageId"] = arg0
+	var arg1 []*model.SizeInput
+	if tmp, ok := rawArgs["sizes"]; ok {
+		arg1, err = ec.unmarshalNSizeInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInputᚄ(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["sizes"] = arg1
+	var arg2 []string
+	if tmp, ok := rawArgs["presets"]; ok {
+		arg2, err = ec.unmarshalNString2ᚕstringᚄ(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["presets"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_rotateImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	var arg1 int
+	if tmp, ok := rawArgs["degrees"]; ok {
+		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["degrees"] = arg1
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_savePreset_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	var arg1 model.SizeInput
+	if tmp, ok := rawArgs["size"]; ok {
+		arg1, err = ec.unmarshalNSizeInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["size"] = arg1
+	var arg2 *bool
+	if tmp, ok := rawArgs["rerender"]; ok {
+		arg2, err = ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["rerender"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_saveWatermark_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	var arg1 *graphql.Upload
+	if tmp, ok := rawArgs["overlay"]; ok {
+		arg1, err = ec.unmarshalOUpload2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["overlay"] = arg1
+	var arg2 *model.Anchor
+	if tmp, ok := rawArgs["position"]; ok {
+		arg2, err = ec.unmarshalOAnchor2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["position"] = arg2
+	var arg3 *float64
+	if tmp, ok := rawArgs["opacity"]; ok {
+		arg3, err = ec.unmarshalOFloat2ᚖfloat64(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["opacity"] = arg3
+	var arg4 *float64
+	if tmp, ok := rawArgs["scale"]; ok {
+		arg4, err = ec.unmarshalOFloat2ᚖfloat64(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["scale"] = arg4
+	var arg5 *bool
+	if tmp, ok := rawArgs["tile"]; ok {
+		arg5, err = ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["tile"] = arg5
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_setFocalPoint_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["imageId"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["imageId"] = arg0
+	var arg1 float64
+	if tmp, ok := rawArgs["x"]; ok {
+		arg1, err = ec.unmarshalNFloat2float64(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["x"] = arg1
+	var arg2 float64
+	if tmp, ok := rawArgs["y"]; ok {
+		arg2, err = ec.unmarshalNFloat2float64(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["y"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_uploadImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 graphql.Upload
+	if tmp, ok := rawArgs["image"]; ok {
+		arg0, err = ec.unmarshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["image"] = arg0
+	var arg1 []*model.SizeInput
+	if tmp, ok := rawArgs["sizes"]; ok {
+		arg1, err = ec.unmarshalNSizeInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInputᚄ(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["sizes"] = arg1
+	var arg2 []string
+	if tmp, ok := rawArgs["presets"]; ok {
+		arg2, err = ec.unmarshalNString2ᚕstringᚄ(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["presets"] = arg2
+	var arg3 *bool
+	if tmp, ok := rawArgs["async"]; ok {
+		arg3, err = ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["async"] = arg3
+	var arg4 *model.MetadataPolicy
+	if tmp, ok := rawArgs["metadataPolicy"]; ok {
+		arg4, err = ec.unmarshalOMetadataPolicy2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["metadataPolicy"] = arg4
+	var arg5 *bool
+	if tmp, ok := rawArgs["dedupe"]; ok {
+		arg5, err = ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["dedupe"] = arg5
+	return args, nil
+}
+
+func (ec *executionContext) field_Query___type_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_image_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_imagesConnection_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 int
+	if tmp, ok := rawArgs["first"]; ok {
+		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["first"] = arg0
+	var arg1 *string
+	if tmp, ok := rawArgs["after"]; ok {
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["after"] = arg1
+	var arg2 *model.ImageFilter
+	if tmp, ok := rawArgs["filter"]; ok {
+		arg2, err = ec.unmarshalOImageFilter2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFilter(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["filter"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_images_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 int
+	if tmp, ok := rawArgs["limit"]; ok {
+		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["limit"] = arg0
+	var arg1 int
+	if tmp, ok := rawArgs["offset"]; ok {
+		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["offset"] = arg1
+	var arg2 *model.ImageFilter
+	if tmp, ok := rawArgs["filter"]; ok {
+		arg2, err = ec.unmarshalOImageFilter2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFilter(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["filter"] = arg2
+	var arg3 *model.ImageSort
+	if tmp, ok := rawArgs["sort"]; ok {
+		arg3, err = ec.unmarshalOImageSort2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSort(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["sort"] = arg3
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_job_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Size_url_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *int
+	if tmp, ok := rawArgs["expiresIn"]; ok {
+		arg0, err = ec.unmarshalOInt2ᚖint(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["expiresIn"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Subscription_imageProcessed_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["imageId"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["imageId"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field___Type_enumValues_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 bool
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		arg0, err = ec.unmarshalOBoolean2bool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 bool
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		arg0, err = ec.unmarshalOBoolean2bool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+
+// endregion ***************************** args.gotpl *****************************
+
+// region    ************************** directives.gotpl **************************
+
+// endregion ************************** directives.gotpl **************************
+
+// region    **************************** field.gotpl *****************************
+
+func (ec *executionContext) _Exif_make(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Make, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_model(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Model, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_lensModel(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.LensModel, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_software(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Software, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_dateTimeOriginal(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.DateTimeOriginal, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_exposureTime(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ExposureTime, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_fNumber(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.FNumber, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*float64)
+	fc.Result = res
+	return ec.marshalOFloat2ᚖfloat64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_iso(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Iso, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_focalLength(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.FocalLength, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*float64)
+	fc.Result = res
+	return ec.marshalOFloat2ᚖfloat64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Exif_hasGps(ctx context.Context, field graphql.CollectedField, obj *model.Exif) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Exif",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasGps, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _FocalPoint_x(ctx context.Context, field graphql.CollectedField, obj *model.FocalPoint) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "FocalPoint",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.X, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(float64)
+	fc.Result = res
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _FocalPoint_y(ctx context.Context, field graphql.CollectedField, obj *model.FocalPoint) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "FocalPoint",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Y, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(float64)
+	fc.Result = res
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_id(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_path(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Path, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_clientName(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ClientName, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_mimeType(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.MimeType, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_format(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Format, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.ImageFormat)
+	fc.Result = res
+	return ec.marshalOImageFormat2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_size(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Size, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_hash(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Hash, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_width(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Width, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_height(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Height, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_orientation(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Orientation, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_colorModel(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ColorModel, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.ColorModel)
+	fc.Result = res
+	return ec.marshalOColorModel2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐColorModel(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_hasAlpha(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasAlpha, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_exif(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Exif, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.Exif)
+	fc.Result = res
+	return ec.marshalOExif2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐExif(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_metadataPolicy(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.MetadataPolicy, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.MetadataPolicy)
+	fc.Result = res
+	return ec.marshalOMetadataPolicy2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_focalPoint(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.FocalPoint, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.FocalPoint)
+	fc.Result = res
+	return ec.marshalOFocalPoint2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐFocalPoint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_uploadAt(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UploadAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*time.Time)
+	fc.Result = res
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_sizes(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Sizes, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Size)
+	fc.Result = res
+	return ec.marshalNSize2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Image_jobId(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Image",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.JobID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _ImageConnection_edges(ctx context.Context, field graphql.CollectedField, obj *model.ImageConnection) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "ImageConnection",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Edges, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.ImageEdge)
+	fc.Result = res
+	return ec.marshalNImageEdge2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageEdgeᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _ImageConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *model.ImageConnection) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "ImageConnection",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.PageInfo, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.PageInfo)
+	fc.Result = res
+	return ec.marshalNPageInfo2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPageInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _ImageConnection_totalCount(ctx context.Context, field graphql.CollectedField, obj *model.ImageConnection) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "ImageConnection",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.ImageConnection().TotalCount(rctx, obj)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _ImageEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *model.ImageEdge) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "ImageEdge",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Cursor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _ImageEdge_node(ctx context.Context, field graphql.CollectedField, obj *model.ImageEdge) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "ImageEdge",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Node, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_id(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_imageId(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ImageID, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_status(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Status, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(model.JobStatus)
+	fc.Result = res
+	return ec.marshalNJobStatus2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐJobStatus(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_error(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Error, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_attempts(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Attempts, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.CreatedAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(time.Time)
+	fc.Result = res
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Job_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.Job) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Job",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UpdatedAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(time.Time)
+	fc.Result = res
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_uploadImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_uploadImage_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().UploadImage(rctx, args["image"].(graphql.Upload), args["sizes"].([]*model.SizeInput), args["presets"].([]string), args["async"].(*bool), args["metadataPolicy"].(*model.MetadataPolicy), args["dedupe"].(*bool))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_resizeImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_resizeImage_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().ResizeImage(rctx, args["imageId"].(string), args["sizes"].([]*model.SizeInput), args["presets"].([]string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_deleteImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_deleteImage_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().DeleteImage(rctx, args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_deleteSize(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_deleteSize_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().DeleteSize(rctx, args["imageId"].(string), args["width"].(int), args["height"].(int))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_setFocalPoint(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_setFocalPoint_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().SetFocalPoint(rctx, args["imageId"].(string), args["x"].(float64), args["y"].(float64))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_rotateImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_rotateImage_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().RotateImage(rctx, args["id"].(string), args["degrees"].(int))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_flipImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_flipImage_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().FlipImage(rctx, args["id"].(string), args["axis"].(model.FlipAxis))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_cropImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_cropImage_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().CropImage(rctx, args["id"].(string), args["rect"].(model.RectInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_savePreset(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_savePreset_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().SavePreset(rctx, args["name"].(string), args["size"].(model.SizeInput), args["rerender"].(*bool))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Preset)
+	fc.Result = res
+	return ec.marshalNPreset2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPreset(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_deletePreset(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_deletePreset_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().DeletePreset(rctx, args["name"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_saveWatermark(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_saveWatermark_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().SaveWatermark(rctx, args["id"].(string), args["overlay"].(*graphql.Upload), args["position"].(*model.Anchor), args["opacity"].(*float64), args["scale"].(*float64), args["tile"].(*bool))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Watermark)
+	fc.Result = res
+	return ec.marshalNWatermark2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐWatermark(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Mutation_deleteWatermark(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Mutation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_deleteWatermark_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().DeleteWatermark(rctx, args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Operation_name(ctx context.Context, field graphql.CollectedField, obj *model.Operation) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Operation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(model.OperationName)
+	fc.Result = res
+	return ec.marshalNOperationName2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationName(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Operation_value(ctx context.Context, field graphql.CollectedField, obj *model.Operation) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Operation",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Value, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*float64)
+	fc.Result = res
+	return ec.marshalOFloat2ᚖfloat64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *model.PageInfo) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "PageInfo",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasNextPage, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *model.PageInfo) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "PageInfo",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasPreviousPage, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *model.PageInfo) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "PageInfo",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.StartCursor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *model.PageInfo) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "PageInfo",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.EndCursor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_name(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_width(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Width, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_height(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Height, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_mode(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Mode, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(model.ResizeMode)
+	fc.Result = res
+	return ec.marshalNResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_anchor(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Anchor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.Anchor)
+	fc.Result = res
+	return ec.marshalOAnchor2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_background(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Background, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_format(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Format, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.ImageFormat)
+	fc.Result = res
+	return ec.marshalOImageFormat2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_quality(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Quality, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_compression(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Compression, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.PNGCompression)
+	fc.Result = res
+	return ec.marshalOPNGCompression2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_operations(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Operations, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Operation)
+	fc.Result = res
+	return ec.marshalNOperation2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_watermark(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Watermark, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_frame(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Frame, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_version(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Version, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Preset_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.Preset) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Preset",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UpdatedAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(time.Time)
+	fc.Result = res
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_image(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_image_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Image(rctx, args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_images(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_images_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Images(rctx, args["limit"].(int), args["offset"].(int), args["filter"].(*model.ImageFilter), args["sort"].(*model.ImageSort))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Image)
+	fc.Result = res
+	return ec.marshalNImage2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_imagesConnection(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_imagesConnection_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ImagesConnection(rctx, args["first"].(int), args["after"].(*string), args["filter"].(*model.ImageFilter))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.ImageConnection)
+	fc.Result = res
+	return ec.marshalNImageConnection2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageConnection(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_job(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_job_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Job(rctx, args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*model.Job)
+	fc.Result = res
+	return ec.marshalNJob2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐJob(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_presets(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Presets(rctx)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Preset)
+	fc.Result = res
+	return ec.marshalNPreset2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPresetᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_watermarks(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Watermarks(rctx)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Watermark)
+	fc.Result = res
+	return ec.marshalNWatermark2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐWatermarkᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query___type_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	fc.Args = args
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.introspectType(args["name"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	fc.Result = res
+	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.introspectSchema()
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Schema)
+	fc.Result = res
+	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_path(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Path, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_width(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Width, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_height(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Height, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_mode(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Mode, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(model.ResizeMode)
+	fc.Result = res
+	return ec.marshalNResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_anchor(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Anchor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.Anchor)
+	fc.Result = res
+	return ec.marshalOAnchor2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_background(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Background, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_format(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Format, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(model.ImageFormat)
+	fc.Result = res
+	return ec.marshalNImageFormat2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_quality(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Quality, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_compression(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Compression, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*model.PNGCompression)
+	fc.Result = res
+	return ec.marshalOPNGCompression2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_operations(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Operations, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*model.Operation)
+	fc.Result = res
+	return ec.marshalNOperation2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Size_watermark(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Size",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
 
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.Watermark, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Image_uploadAt(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+func (ec *executionContext) _Size_frame(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -599,7 +5195,7 @@ func (ec *executionContext) _Image_uploadAt(ctx context.Context, field graphql.C
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Image",
+		Object:   "Size",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -608,7 +5204,7 @@ func (ec *executionContext) _Image_uploadAt(ctx context.Context, field graphql.C
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.UploadAt, nil
+		return obj.Frame, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -617,12 +5213,12 @@ func (ec *executionContext) _Image_uploadAt(ctx context.Context, field graphql.C
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Image_sizes(ctx context.Context, field graphql.CollectedField, obj *model.Image) (ret graphql.Marshaler) {
+func (ec *executionContext) _Size_status(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -630,7 +5226,7 @@ func (ec *executionContext) _Image_sizes(ctx context.Context, field graphql.Coll
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Image",
+		Object:   "Size",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -639,7 +5235,7 @@ func (ec *executionContext) _Image_sizes(ctx context.Context, field graphql.Coll
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Sizes, nil
+		return obj.Status, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -651,12 +5247,12 @@ func (ec *executionContext) _Image_sizes(ctx context.Context, field graphql.Coll
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]*model.Size)
+	res := resTmp.(model.SizeStatus)
 	fc.Result = res
-	return ec.marshalNSize2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeᚄ(ctx, field.Selections, res)
+	return ec.marshalNSizeStatus2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeStatus(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_uploadImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Size_preset(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -664,40 +5260,30 @@ func (ec *executionContext) _Mutation_uploadImage(ctx context.Context, field gra
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Mutation",
+		Object:   "Size",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 
 	ctx = graphql.WithFieldContext(ctx, fc)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_uploadImage_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	fc.Args = args
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().UploadImage(rctx, args["image"].(graphql.Upload), args["sizes"].([]*model.SizeInput))
+		return obj.Preset, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*model.Image)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_resizeImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Size_url(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -705,7 +5291,7 @@ func (ec *executionContext) _Mutation_resizeImage(ctx context.Context, field gra
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Mutation",
+		Object:   "Size",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
@@ -713,7 +5299,7 @@ func (ec *executionContext) _Mutation_resizeImage(ctx context.Context, field gra
 
 	ctx = graphql.WithFieldContext(ctx, fc)
 	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_resizeImage_args(ctx, rawArgs)
+	args, err := ec.field_Size_url_args(ctx, rawArgs)
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
@@ -721,7 +5307,7 @@ func (ec *executionContext) _Mutation_resizeImage(ctx context.Context, field gra
 	fc.Args = args
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().ResizeImage(rctx, args["imageId"].(string), args["sizes"].([]*model.SizeInput))
+		return ec.resolvers.Size().URL(rctx, obj, args["expiresIn"].(*int))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -733,20 +5319,20 @@ func (ec *executionContext) _Mutation_resizeImage(ctx context.Context, field gra
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*model.Image)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Query_images(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Subscription_imageProcessed(ctx context.Context, field graphql.CollectedField) (ret func() graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
-			ret = graphql.Null
+			ret = nil
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Query",
+		Object:   "Subscription",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
@@ -754,15 +5340,59 @@ func (ec *executionContext) _Query_images(ctx context.Context, field graphql.Col
 
 	ctx = graphql.WithFieldContext(ctx, fc)
 	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Query_images_args(ctx, rawArgs)
+	args, err := ec.field_Subscription_imageProcessed_args(ctx, rawArgs)
 	if err != nil {
 		ec.Error(ctx, err)
-		return graphql.Null
+		return nil
 	}
 	fc.Args = args
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Images(rctx, args["limit"].(int), args["offset"].(int))
+		return ec.resolvers.Subscription().ImageProcessed(rctx, args["imageId"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return nil
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return nil
+	}
+	return func() graphql.Marshaler {
+		res, ok := <-resTmp.(<-chan *model.Image)
+		if !ok {
+			return nil
+		}
+		return graphql.WriterFunc(func(w io.Writer) {
+			w.Write([]byte{'{'})
+			graphql.MarshalString(field.Alias).MarshalGQL(w)
+			w.Write([]byte{':'})
+			ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, field.Selections, res).MarshalGQL(w)
+			w.Write([]byte{'}'})
+		})
+	}
+}
+
+func (ec *executionContext) _Watermark_id(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Watermark",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -774,12 +5404,12 @@ func (ec *executionContext) _Query_images(ctx context.Context, field graphql.Col
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]*model.Image)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNImage2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageᚄ(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Watermark_position(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -787,37 +5417,67 @@ func (ec *executionContext) _Query___type(ctx context.Context, field graphql.Col
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Query",
+		Object:   "Watermark",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 
 	ctx = graphql.WithFieldContext(ctx, fc)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Query___type_args(ctx, rawArgs)
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Position, nil
+	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
-	fc.Args = args
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(model.Anchor)
+	fc.Result = res
+	return ec.marshalNAnchor2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Watermark_opacity(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:   "Watermark",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectType(args["name"].(string))
+		return obj.Opacity, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(float64)
 	fc.Result = res
-	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+func (ec *executionContext) _Watermark_scale(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -825,30 +5485,33 @@ func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.C
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Query",
+		Object:   "Watermark",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectSchema()
+		return obj.Scale, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Schema)
+	res := resTmp.(float64)
 	fc.Result = res
-	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Size_path(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+func (ec *executionContext) _Watermark_tile(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -856,7 +5519,7 @@ func (ec *executionContext) _Size_path(ctx context.Context, field graphql.Collec
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Size",
+		Object:   "Watermark",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -865,7 +5528,7 @@ func (ec *executionContext) _Size_path(ctx context.Context, field graphql.Collec
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Path, nil
+		return obj.Tile, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -877,12 +5540,12 @@ func (ec *executionContext) _Size_path(ctx context.Context, field graphql.Collec
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(bool)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Size_width(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+func (ec *executionContext) _Watermark_version(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -890,7 +5553,7 @@ func (ec *executionContext) _Size_width(ctx context.Context, field graphql.Colle
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Size",
+		Object:   "Watermark",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -899,7 +5562,7 @@ func (ec *executionContext) _Size_width(ctx context.Context, field graphql.Colle
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Width, nil
+		return obj.Version, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -916,7 +5579,7 @@ func (ec *executionContext) _Size_width(ctx context.Context, field graphql.Colle
 	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Size_height(ctx context.Context, field graphql.CollectedField, obj *model.Size) (ret graphql.Marshaler) {
+func (ec *executionContext) _Watermark_updatedAt(ctx context.Context, field graphql.CollectedField, obj *model.Watermark) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
 			ec.Error(ctx, ec.Recover(ctx, r))
@@ -924,7 +5587,7 @@ func (ec *executionContext) _Size_height(ctx context.Context, field graphql.Coll
 		}
 	}()
 	fc := &graphql.FieldContext{
-		Object:   "Size",
+		Object:   "Watermark",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -933,7 +5596,7 @@ func (ec *executionContext) _Size_height(ctx context.Context, field graphql.Coll
 	ctx = graphql.WithFieldContext(ctx, fc)
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Height, nil
+		return obj.UpdatedAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -945,9 +5608,9 @@ func (ec *executionContext) _Size_height(ctx context.Context, field graphql.Coll
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(time.Time)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
 func (ec *executionContext) ___Directive_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) (ret graphql.Marshaler) {
@@ -1996,19 +6659,156 @@ func (ec *executionContext) ___Type_ofType(ctx context.Context, field graphql.Co
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
-	fc.Result = res
-	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
-}
-
-// endregion **************************** field.gotpl *****************************
+	res := resTmp.(*introspection.Type)
+	fc.Result = res
+	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+// endregion **************************** field.gotpl *****************************
+
+// region    **************************** input.gotpl *****************************
+
+func (ec *executionContext) unmarshalInputImageFilter(ctx context.Context, obj interface{}) (model.ImageFilter, error) {
+	var it model.ImageFilter
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "mimeType":
+			var err error
+			it.MimeType, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "uploadedAfter":
+			var err error
+			it.UploadedAfter, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "uploadedBefore":
+			var err error
+			it.UploadedBefore, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "clientNamePrefix":
+			var err error
+			it.ClientNamePrefix, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputImageSort(ctx context.Context, obj interface{}) (model.ImageSort, error) {
+	var it model.ImageSort
+	var asMap = obj.(map[string]interface{})
+
+	if _, present := asMap["field"]; !present {
+		asMap["field"] = "UPLOAD_AT"
+	}
+	if _, present := asMap["direction"]; !present {
+		asMap["direction"] = "ASC"
+	}
+
+	for k, v := range asMap {
+		switch k {
+		case "field":
+			var err error
+			it.Field, err = ec.unmarshalNImageSortField2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSortField(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "direction":
+			var err error
+			it.Direction, err = ec.unmarshalNSortDirection2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSortDirection(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputOperationInput(ctx context.Context, obj interface{}) (model.OperationInput, error) {
+	var it model.OperationInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "name":
+			var err error
+			it.Name, err = ec.unmarshalNOperationName2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationName(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "value":
+			var err error
+			it.Value, err = ec.unmarshalOFloat2ᚖfloat64(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputRectInput(ctx context.Context, obj interface{}) (model.RectInput, error) {
+	var it model.RectInput
+	var asMap = obj.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "x":
+			var err error
+			it.X, err = ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "y":
+			var err error
+			it.Y, err = ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "width":
+			var err error
+			it.Width, err = ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "height":
+			var err error
+			it.Height, err = ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
 
-// region    **************************** input.gotpl *****************************
+	return it, nil
+}
 
 func (ec *executionContext) unmarshalInputSizeInput(ctx context.Context, obj interface{}) (model.SizeInput, error) {
 	var it model.SizeInput
 	var asMap = obj.(map[string]interface{})
 
+	if _, present := asMap["mode"]; !present {
+		asMap["mode"] = "STRETCH"
+	}
+	if _, present := asMap["anchor"]; !present {
+		asMap["anchor"] = "CENTER"
+	}
+	if _, present := asMap["background"]; !present {
+		asMap["background"] = "#ffffff"
+	}
+
 	for k, v := range asMap {
 		switch k {
 		case "width":
@@ -2023,6 +6823,60 @@ func (ec *executionContext) unmarshalInputSizeInput(ctx context.Context, obj int
 			if err != nil {
 				return it, err
 			}
+		case "mode":
+			var err error
+			it.Mode, err = ec.unmarshalOResizeMode2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "anchor":
+			var err error
+			it.Anchor, err = ec.unmarshalOAnchor2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "background":
+			var err error
+			it.Background, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "format":
+			var err error
+			it.Format, err = ec.unmarshalOImageFormat2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "quality":
+			var err error
+			it.Quality, err = ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "compression":
+			var err error
+			it.Compression, err = ec.unmarshalOPNGCompression2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "operations":
+			var err error
+			it.Operations, err = ec.unmarshalNOperationInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationInputᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "watermark":
+			var err error
+			it.Watermark, err = ec.unmarshalOID2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "frame":
+			var err error
+			it.Frame, err = ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -2035,48 +6889,368 @@ func (ec *executionContext) unmarshalInputSizeInput(ctx context.Context, obj int
 
 // endregion ************************** interface.gotpl ***************************
 
-// region    **************************** object.gotpl ****************************
+// region    **************************** object.gotpl ****************************
+
+var exifImplementors = []string{"Exif"}
+
+func (ec *executionContext) _Exif(ctx context.Context, sel ast.SelectionSet, obj *model.Exif) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, exifImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Exif")
+		case "make":
+			out.Values[i] = ec._Exif_make(ctx, field, obj)
+		case "model":
+			out.Values[i] = ec._Exif_model(ctx, field, obj)
+		case "lensModel":
+			out.Values[i] = ec._Exif_lensModel(ctx, field, obj)
+		case "software":
+			out.Values[i] = ec._Exif_software(ctx, field, obj)
+		case "dateTimeOriginal":
+			out.Values[i] = ec._Exif_dateTimeOriginal(ctx, field, obj)
+		case "exposureTime":
+			out.Values[i] = ec._Exif_exposureTime(ctx, field, obj)
+		case "fNumber":
+			out.Values[i] = ec._Exif_fNumber(ctx, field, obj)
+		case "iso":
+			out.Values[i] = ec._Exif_iso(ctx, field, obj)
+		case "focalLength":
+			out.Values[i] = ec._Exif_focalLength(ctx, field, obj)
+		case "hasGps":
+			out.Values[i] = ec._Exif_hasGps(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var focalPointImplementors = []string{"FocalPoint"}
+
+func (ec *executionContext) _FocalPoint(ctx context.Context, sel ast.SelectionSet, obj *model.FocalPoint) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, focalPointImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("FocalPoint")
+		case "x":
+			out.Values[i] = ec._FocalPoint_x(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "y":
+			out.Values[i] = ec._FocalPoint_y(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var imageImplementors = []string{"Image"}
+
+func (ec *executionContext) _Image(ctx context.Context, sel ast.SelectionSet, obj *model.Image) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, imageImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Image")
+		case "id":
+			out.Values[i] = ec._Image_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "path":
+			out.Values[i] = ec._Image_path(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "clientName":
+			out.Values[i] = ec._Image_clientName(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "mimeType":
+			out.Values[i] = ec._Image_mimeType(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "format":
+			out.Values[i] = ec._Image_format(ctx, field, obj)
+		case "size":
+			out.Values[i] = ec._Image_size(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "hash":
+			out.Values[i] = ec._Image_hash(ctx, field, obj)
+		case "width":
+			out.Values[i] = ec._Image_width(ctx, field, obj)
+		case "height":
+			out.Values[i] = ec._Image_height(ctx, field, obj)
+		case "orientation":
+			out.Values[i] = ec._Image_orientation(ctx, field, obj)
+		case "colorModel":
+			out.Values[i] = ec._Image_colorModel(ctx, field, obj)
+		case "hasAlpha":
+			out.Values[i] = ec._Image_hasAlpha(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "exif":
+			out.Values[i] = ec._Image_exif(ctx, field, obj)
+		case "metadataPolicy":
+			out.Values[i] = ec._Image_metadataPolicy(ctx, field, obj)
+		case "focalPoint":
+			out.Values[i] = ec._Image_focalPoint(ctx, field, obj)
+		case "uploadAt":
+			out.Values[i] = ec._Image_uploadAt(ctx, field, obj)
+		case "sizes":
+			out.Values[i] = ec._Image_sizes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "jobId":
+			out.Values[i] = ec._Image_jobId(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var imageConnectionImplementors = []string{"ImageConnection"}
+
+func (ec *executionContext) _ImageConnection(ctx context.Context, sel ast.SelectionSet, obj *model.ImageConnection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, imageConnectionImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ImageConnection")
+		case "edges":
+			out.Values[i] = ec._ImageConnection_edges(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "pageInfo":
+			out.Values[i] = ec._ImageConnection_pageInfo(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "totalCount":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._ImageConnection_totalCount(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var imageEdgeImplementors = []string{"ImageEdge"}
+
+func (ec *executionContext) _ImageEdge(ctx context.Context, sel ast.SelectionSet, obj *model.ImageEdge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, imageEdgeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ImageEdge")
+		case "cursor":
+			out.Values[i] = ec._ImageEdge_cursor(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "node":
+			out.Values[i] = ec._ImageEdge_node(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var jobImplementors = []string{"Job"}
+
+func (ec *executionContext) _Job(ctx context.Context, sel ast.SelectionSet, obj *model.Job) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, jobImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Job")
+		case "id":
+			out.Values[i] = ec._Job_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "imageId":
+			out.Values[i] = ec._Job_imageId(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "status":
+			out.Values[i] = ec._Job_status(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "error":
+			out.Values[i] = ec._Job_error(ctx, field, obj)
+		case "attempts":
+			out.Values[i] = ec._Job_attempts(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "createdAt":
+			out.Values[i] = ec._Job_createdAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "updatedAt":
+			out.Values[i] = ec._Job_updatedAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var mutationImplementors = []string{"Mutation"}
 
-var imageImplementors = []string{"Image"}
+func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, mutationImplementors)
 
-func (ec *executionContext) _Image(ctx context.Context, sel ast.SelectionSet, obj *model.Image) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, imageImplementors)
+	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: "Mutation",
+	})
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Image")
-		case "id":
-			out.Values[i] = ec._Image_id(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Mutation")
+		case "uploadImage":
+			out.Values[i] = ec._Mutation_uploadImage(ctx, field)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "path":
-			out.Values[i] = ec._Image_path(ctx, field, obj)
+		case "resizeImage":
+			out.Values[i] = ec._Mutation_resizeImage(ctx, field)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "clientName":
-			out.Values[i] = ec._Image_clientName(ctx, field, obj)
+		case "deleteImage":
+			out.Values[i] = ec._Mutation_deleteImage(ctx, field)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "mimeType":
-			out.Values[i] = ec._Image_mimeType(ctx, field, obj)
+		case "deleteSize":
+			out.Values[i] = ec._Mutation_deleteSize(ctx, field)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "size":
-			out.Values[i] = ec._Image_size(ctx, field, obj)
+		case "setFocalPoint":
+			out.Values[i] = ec._Mutation_setFocalPoint(ctx, field)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "uploadAt":
-			out.Values[i] = ec._Image_uploadAt(ctx, field, obj)
-		case "sizes":
-			out.Values[i] = ec._Image_sizes(ctx, field, obj)
+		case "rotateImage":
+			out.Values[i] = ec._Mutation_rotateImage(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "flipImage":
+			out.Values[i] = ec._Mutation_flipImage(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "cropImage":
+			out.Values[i] = ec._Mutation_cropImage(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "savePreset":
+			out.Values[i] = ec._Mutation_savePreset(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "deletePreset":
+			out.Values[i] = ec._Mutation_deletePreset(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "saveWatermark":
+			out.Values[i] = ec._Mutation_saveWatermark(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "deleteWatermark":
+			out.Values[i] = ec._Mutation_deleteWatermark(ctx, field)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
@@ -2091,28 +7265,128 @@ func (ec *executionContext) _Image(ctx context.Context, sel ast.SelectionSet, ob
 	return out
 }
 
-var mutationImplementors = []string{"Mutation"}
+var operationImplementors = []string{"Operation"}
 
-func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, mutationImplementors)
+func (ec *executionContext) _Operation(ctx context.Context, sel ast.SelectionSet, obj *model.Operation) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, operationImplementors)
 
-	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
-		Object: "Mutation",
-	})
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Operation")
+		case "name":
+			out.Values[i] = ec._Operation_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "value":
+			out.Values[i] = ec._Operation_value(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var pageInfoImplementors = []string{"PageInfo"}
+
+func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *model.PageInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, pageInfoImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Mutation")
-		case "uploadImage":
-			out.Values[i] = ec._Mutation_uploadImage(ctx, field)
+			out.Values[i] = graphql.MarshalString("PageInfo")
+		case "hasNextPage":
+			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "resizeImage":
-			out.Values[i] = ec._Mutation_resizeImage(ctx, field)
+		case "hasPreviousPage":
+			out.Values[i] = ec._PageInfo_hasPreviousPage(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "startCursor":
+			out.Values[i] = ec._PageInfo_startCursor(ctx, field, obj)
+		case "endCursor":
+			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var presetImplementors = []string{"Preset"}
+
+func (ec *executionContext) _Preset(ctx context.Context, sel ast.SelectionSet, obj *model.Preset) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, presetImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Preset")
+		case "name":
+			out.Values[i] = ec._Preset_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "width":
+			out.Values[i] = ec._Preset_width(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "height":
+			out.Values[i] = ec._Preset_height(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "mode":
+			out.Values[i] = ec._Preset_mode(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "anchor":
+			out.Values[i] = ec._Preset_anchor(ctx, field, obj)
+		case "background":
+			out.Values[i] = ec._Preset_background(ctx, field, obj)
+		case "format":
+			out.Values[i] = ec._Preset_format(ctx, field, obj)
+		case "quality":
+			out.Values[i] = ec._Preset_quality(ctx, field, obj)
+		case "compression":
+			out.Values[i] = ec._Preset_compression(ctx, field, obj)
+		case "operations":
+			out.Values[i] = ec._Preset_operations(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "watermark":
+			out.Values[i] = ec._Preset_watermark(ctx, field, obj)
+		case "frame":
+			out.Values[i] = ec._Preset_frame(ctx, field, obj)
+		case "version":
+			out.Values[i] = ec._Preset_version(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "updatedAt":
+			out.Values[i] = ec._Preset_updatedAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
@@ -2142,6 +7416,20 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 		switch field.Name {
 		case "__typename":
 			out.Values[i] = graphql.MarshalString("Query")
+		case "image":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_image(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
 		case "images":
 			field := field
 			out.Concurrently(i, func() (res graphql.Marshaler) {
@@ -2156,6 +7444,62 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 				}
 				return res
 			})
+		case "imagesConnection":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_imagesConnection(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
+		case "job":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_job(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
+		case "presets":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_presets(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
+		case "watermarks":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_watermarks(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
 		case "__type":
 			out.Values[i] = ec._Query___type(ctx, field)
 		case "__schema":
@@ -2185,15 +7529,140 @@ func (ec *executionContext) _Size(ctx context.Context, sel ast.SelectionSet, obj
 		case "path":
 			out.Values[i] = ec._Size_path(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				invalids++
+				atomic.AddUint32(&invalids, 1)
 			}
 		case "width":
 			out.Values[i] = ec._Size_width(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "height":
+			out.Values[i] = ec._Size_height(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "mode":
+			out.Values[i] = ec._Size_mode(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "anchor":
+			out.Values[i] = ec._Size_anchor(ctx, field, obj)
+		case "background":
+			out.Values[i] = ec._Size_background(ctx, field, obj)
+		case "format":
+			out.Values[i] = ec._Size_format(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "quality":
+			out.Values[i] = ec._Size_quality(ctx, field, obj)
+		case "compression":
+			out.Values[i] = ec._Size_compression(ctx, field, obj)
+		case "operations":
+			out.Values[i] = ec._Size_operations(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "watermark":
+			out.Values[i] = ec._Size_watermark(ctx, field, obj)
+		case "frame":
+			out.Values[i] = ec._Size_frame(ctx, field, obj)
+		case "status":
+			out.Values[i] = ec._Size_status(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "preset":
+			out.Values[i] = ec._Size_preset(ctx, field, obj)
+		case "url":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Size_url(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			})
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var subscriptionImplementors = []string{"Subscription"}
+
+func (ec *executionContext) _Subscription(ctx context.Context, sel ast.SelectionSet) func() graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, subscriptionImplementors)
+	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: "Subscription",
+	})
+	if len(fields) != 1 {
+		ec.Errorf(ctx, "must subscribe to exactly one stream")
+		return nil
+	}
+
+	switch fields[0].Name {
+	case "imageProcessed":
+		return ec._Subscription_imageProcessed(ctx, fields[0])
+	default:
+		panic("unknown field " + strconv.Quote(fields[0].Name))
+	}
+}
+
+var watermarkImplementors = []string{"Watermark"}
+
+func (ec *executionContext) _Watermark(ctx context.Context, sel ast.SelectionSet, obj *model.Watermark) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, watermarkImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Watermark")
+		case "id":
+			out.Values[i] = ec._Watermark_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "position":
+			out.Values[i] = ec._Watermark_position(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "opacity":
+			out.Values[i] = ec._Watermark_opacity(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "scale":
+			out.Values[i] = ec._Watermark_scale(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "tile":
+			out.Values[i] = ec._Watermark_tile(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "version":
+			out.Values[i] = ec._Watermark_version(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		case "height":
-			out.Values[i] = ec._Size_height(ctx, field, obj)
+		case "updatedAt":
+			out.Values[i] = ec._Watermark_updatedAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
@@ -2395,97 +7864,470 @@ func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet,
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var __TypeImplementors = []string{"__Type"}
+
+func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("__Type")
+		case "kind":
+			out.Values[i] = ec.___Type_kind(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "name":
+			out.Values[i] = ec.___Type_name(ctx, field, obj)
+		case "description":
+			out.Values[i] = ec.___Type_description(ctx, field, obj)
+		case "fields":
+			out.Values[i] = ec.___Type_fields(ctx, field, obj)
+		case "interfaces":
+			out.Values[i] = ec.___Type_interfaces(ctx, field, obj)
+		case "possibleTypes":
+			out.Values[i] = ec.___Type_possibleTypes(ctx, field, obj)
+		case "enumValues":
+			out.Values[i] = ec.___Type_enumValues(ctx, field, obj)
+		case "inputFields":
+			out.Values[i] = ec.___Type_inputFields(ctx, field, obj)
+		case "ofType":
+			out.Values[i] = ec.___Type_ofType(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+// endregion **************************** object.gotpl ****************************
+
+// region    ***************************** type.gotpl *****************************
+
+func (ec *executionContext) unmarshalNAnchor2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx context.Context, v interface{}) (model.Anchor, error) {
+	var res model.Anchor
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNAnchor2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx context.Context, sel ast.SelectionSet, v model.Anchor) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNBoolean2bool(ctx context.Context, v interface{}) (bool, error) {
+	return graphql.UnmarshalBoolean(v)
+}
+
+func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.SelectionSet, v bool) graphql.Marshaler {
+	res := graphql.MarshalBoolean(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNFlipAxis2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐFlipAxis(ctx context.Context, v interface{}) (model.FlipAxis, error) {
+	var res model.FlipAxis
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNFlipAxis2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐFlipAxis(ctx context.Context, sel ast.SelectionSet, v model.FlipAxis) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNFloat2float64(ctx context.Context, v interface{}) (float64, error) {
+	return graphql.UnmarshalFloat(v)
+}
+
+func (ec *executionContext) marshalNFloat2float64(ctx context.Context, sel ast.SelectionSet, v float64) graphql.Marshaler {
+	res := graphql.MarshalFloat(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNID2string(ctx context.Context, v interface{}) (string, error) {
+	return graphql.UnmarshalID(v)
+}
+
+func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
+	res := graphql.MarshalID(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) marshalNImage2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx context.Context, sel ast.SelectionSet, v model.Image) graphql.Marshaler {
+	return ec._Image(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNImage2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Image) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx context.Context, sel ast.SelectionSet, v *model.Image) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._Image(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNImageConnection2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageConnection(ctx context.Context, sel ast.SelectionSet, v model.ImageConnection) graphql.Marshaler {
+	return ec._ImageConnection(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNImageConnection2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageConnection(ctx context.Context, sel ast.SelectionSet, v *model.ImageConnection) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._ImageConnection(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNImageEdge2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageEdge(ctx context.Context, sel ast.SelectionSet, v model.ImageEdge) graphql.Marshaler {
+	return ec._ImageEdge(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNImageEdge2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageEdgeᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.ImageEdge) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNImageEdge2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageEdge(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) marshalNImageEdge2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageEdge(ctx context.Context, sel ast.SelectionSet, v *model.ImageEdge) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._ImageEdge(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNImageFormat2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx context.Context, v interface{}) (model.ImageFormat, error) {
+	var res model.ImageFormat
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNImageFormat2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx context.Context, sel ast.SelectionSet, v model.ImageFormat) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNImageSortField2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSortField(ctx context.Context, v interface{}) (model.ImageSortField, error) {
+	var res model.ImageSortField
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNImageSortField2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSortField(ctx context.Context, sel ast.SelectionSet, v model.ImageSortField) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNInt2int(ctx context.Context, v interface{}) (int, error) {
+	return graphql.UnmarshalInt(v)
+}
+
+func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
+	res := graphql.MarshalInt(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) marshalNJob2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐJob(ctx context.Context, sel ast.SelectionSet, v model.Job) graphql.Marshaler {
+	return ec._Job(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNJob2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐJob(ctx context.Context, sel ast.SelectionSet, v *model.Job) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._Job(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNJobStatus2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐJobStatus(ctx context.Context, v interface{}) (model.JobStatus, error) {
+	var res model.JobStatus
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNJobStatus2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐJobStatus(ctx context.Context, sel ast.SelectionSet, v model.JobStatus) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNOperation2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperation(ctx context.Context, sel ast.SelectionSet, v model.Operation) graphql.Marshaler {
+	return ec._Operation(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNOperation2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Operation) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNOperation2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperation(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
 		}
+
 	}
-	out.Dispatch()
-	if invalids > 0 {
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) marshalNOperation2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperation(ctx context.Context, sel ast.SelectionSet, v *model.Operation) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	return out
+	return ec._Operation(ctx, sel, v)
 }
 
-var __TypeImplementors = []string{"__Type"}
-
-func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+func (ec *executionContext) unmarshalNOperationInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationInput(ctx context.Context, v interface{}) (model.OperationInput, error) {
+	return ec.unmarshalInputOperationInput(ctx, v)
+}
 
-	out := graphql.NewFieldSet(fields)
-	var invalids uint32
-	for i, field := range fields {
-		switch field.Name {
-		case "__typename":
-			out.Values[i] = graphql.MarshalString("__Type")
-		case "kind":
-			out.Values[i] = ec.___Type_kind(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				invalids++
-			}
-		case "name":
-			out.Values[i] = ec.___Type_name(ctx, field, obj)
-		case "description":
-			out.Values[i] = ec.___Type_description(ctx, field, obj)
-		case "fields":
-			out.Values[i] = ec.___Type_fields(ctx, field, obj)
-		case "interfaces":
-			out.Values[i] = ec.___Type_interfaces(ctx, field, obj)
-		case "possibleTypes":
-			out.Values[i] = ec.___Type_possibleTypes(ctx, field, obj)
-		case "enumValues":
-			out.Values[i] = ec.___Type_enumValues(ctx, field, obj)
-		case "inputFields":
-			out.Values[i] = ec.___Type_inputFields(ctx, field, obj)
-		case "ofType":
-			out.Values[i] = ec.___Type_ofType(ctx, field, obj)
-		default:
-			panic("unknown field " + strconv.Quote(field.Name))
+func (ec *executionContext) unmarshalNOperationInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationInputᚄ(ctx context.Context, v interface{}) ([]*model.OperationInput, error) {
+	var vSlice []interface{}
+	if v != nil {
+		if tmp1, ok := v.([]interface{}); ok {
+			vSlice = tmp1
+		} else {
+			vSlice = []interface{}{v}
 		}
 	}
-	out.Dispatch()
-	if invalids > 0 {
-		return graphql.Null
+	var err error
+	res := make([]*model.OperationInput, len(vSlice))
+	for i := range vSlice {
+		res[i], err = ec.unmarshalNOperationInput2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
 	}
-	return out
+	return res, nil
 }
 
-// endregion **************************** object.gotpl ****************************
+func (ec *executionContext) unmarshalNOperationInput2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationInput(ctx context.Context, v interface{}) (*model.OperationInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalNOperationInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationInput(ctx, v)
+	return &res, err
+}
 
-// region    ***************************** type.gotpl *****************************
+func (ec *executionContext) unmarshalNOperationName2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationName(ctx context.Context, v interface{}) (model.OperationName, error) {
+	var res model.OperationName
+	return res, res.UnmarshalGQL(v)
+}
 
-func (ec *executionContext) unmarshalNBoolean2bool(ctx context.Context, v interface{}) (bool, error) {
-	return graphql.UnmarshalBoolean(v)
+func (ec *executionContext) marshalNOperationName2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐOperationName(ctx context.Context, sel ast.SelectionSet, v model.OperationName) graphql.Marshaler {
+	return v
 }
 
-func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.SelectionSet, v bool) graphql.Marshaler {
-	res := graphql.MarshalBoolean(v)
-	if res == graphql.Null {
+func (ec *executionContext) marshalNPageInfo2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v model.PageInfo) graphql.Marshaler {
+	return ec._PageInfo(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNPageInfo2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v *model.PageInfo) graphql.Marshaler {
+	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
 		}
+		return graphql.Null
 	}
-	return res
+	return ec._PageInfo(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNID2string(ctx context.Context, v interface{}) (string, error) {
-	return graphql.UnmarshalID(v)
+func (ec *executionContext) marshalNPreset2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPreset(ctx context.Context, sel ast.SelectionSet, v model.Preset) graphql.Marshaler {
+	return ec._Preset(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
-	res := graphql.MarshalID(v)
-	if res == graphql.Null {
+func (ec *executionContext) marshalNPreset2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPresetᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Preset) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNPreset2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPreset(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) marshalNPreset2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPreset(ctx context.Context, sel ast.SelectionSet, v *model.Preset) graphql.Marshaler {
+	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
 		}
+		return graphql.Null
 	}
-	return res
+	return ec._Preset(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNImage2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx context.Context, sel ast.SelectionSet, v model.Image) graphql.Marshaler {
-	return ec._Image(ctx, sel, &v)
+func (ec *executionContext) unmarshalNRectInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐRectInput(ctx context.Context, v interface{}) (model.RectInput, error) {
+	return ec.unmarshalInputRectInput(ctx, v)
 }
 
-func (ec *executionContext) marshalNImage2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Image) graphql.Marshaler {
+func (ec *executionContext) unmarshalNResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx context.Context, v interface{}) (model.ResizeMode, error) {
+	var res model.ResizeMode
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx context.Context, sel ast.SelectionSet, v model.ResizeMode) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNSize2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSize(ctx context.Context, sel ast.SelectionSet, v model.Size) graphql.Marshaler {
+	return ec._Size(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSize2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Size) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -2509,7 +8351,7 @@ func (ec *executionContext) marshalNImage2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑ
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx, sel, v[i])
+			ret[i] = ec.marshalNSize2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSize(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -2522,22 +8364,129 @@ func (ec *executionContext) marshalNImage2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑ
 	return ret
 }
 
-func (ec *executionContext) marshalNImage2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImage(ctx context.Context, sel ast.SelectionSet, v *model.Image) graphql.Marshaler {
+func (ec *executionContext) marshalNSize2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSize(ctx context.Context, sel ast.SelectionSet, v *model.Size) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
 		}
 		return graphql.Null
 	}
-	return ec._Image(ctx, sel, v)
+	return ec._Size(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNInt2int(ctx context.Context, v interface{}) (int, error) {
-	return graphql.UnmarshalInt(v)
+func (ec *executionContext) unmarshalNSizeInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx context.Context, v interface{}) (model.SizeInput, error) {
+	return ec.unmarshalInputSizeInput(ctx, v)
+}
+
+func (ec *executionContext) unmarshalNSizeInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInputᚄ(ctx context.Context, v interface{}) ([]*model.SizeInput, error) {
+	var vSlice []interface{}
+	if v != nil {
+		if tmp1, ok := v.([]interface{}); ok {
+			vSlice = tmp1
+		} else {
+			vSlice = []interface{}{v}
+		}
+	}
+	var err error
+	res := make([]*model.SizeInput, len(vSlice))
+	for i := range vSlice {
+		res[i], err = ec.unmarshalNSizeInput2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) unmarshalNSizeInput2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx context.Context, v interface{}) (*model.SizeInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalNSizeInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalNSizeStatus2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeStatus(ctx context.Context, v interface{}) (model.SizeStatus, error) {
+	var res model.SizeStatus
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNSizeStatus2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeStatus(ctx context.Context, sel ast.SelectionSet, v model.SizeStatus) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNSortDirection2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSortDirection(ctx context.Context, v interface{}) (model.SortDirection, error) {
+	var res model.SortDirection
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNSortDirection2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSortDirection(ctx context.Context, sel ast.SelectionSet, v model.SortDirection) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNString2string(ctx context.Context, v interface{}) (string, error) {
+	return graphql.UnmarshalString(v)
+}
+
+func (ec *executionContext) marshalNString2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
+	res := graphql.MarshalString(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNString2ᚕstringᚄ(ctx context.Context, v interface{}) ([]string, error) {
+	var vSlice []interface{}
+	if v != nil {
+		if tmp1, ok := v.([]interface{}); ok {
+			vSlice = tmp1
+		} else {
+			vSlice = []interface{}{v}
+		}
+	}
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		res[i], err = ec.unmarshalNString2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalNString2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
+	}
+
+	return ret
+}
+
+func (ec *executionContext) unmarshalNTime2timeᚐTime(ctx context.Context, v interface{}) (time.Time, error) {
+	return graphql.UnmarshalTime(v)
+}
+
+func (ec *executionContext) marshalNTime2timeᚐTime(ctx context.Context, sel ast.SelectionSet, v time.Time) graphql.Marshaler {
+	res := graphql.MarshalTime(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, v interface{}) (graphql.Upload, error) {
+	return graphql.UnmarshalUpload(v)
 }
 
-func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
-	res := graphql.MarshalInt(v)
+func (ec *executionContext) marshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, sel ast.SelectionSet, v graphql.Upload) graphql.Marshaler {
+	res := graphql.MarshalUpload(v)
 	if res == graphql.Null {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
@@ -2546,11 +8495,11 @@ func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.Selecti
 	return res
 }
 
-func (ec *executionContext) marshalNSize2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSize(ctx context.Context, sel ast.SelectionSet, v model.Size) graphql.Marshaler {
-	return ec._Size(ctx, sel, &v)
+func (ec *executionContext) marshalNWatermark2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐWatermark(ctx context.Context, sel ast.SelectionSet, v model.Watermark) graphql.Marshaler {
+	return ec._Watermark(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNSize2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Size) graphql.Marshaler {
+func (ec *executionContext) marshalNWatermark2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐWatermarkᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.Watermark) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -2574,7 +8523,7 @@ func (ec *executionContext) marshalNSize2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑr
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNSize2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSize(ctx, sel, v[i])
+			ret[i] = ec.marshalNWatermark2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐWatermark(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -2587,74 +8536,14 @@ func (ec *executionContext) marshalNSize2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑr
 	return ret
 }
 
-func (ec *executionContext) marshalNSize2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSize(ctx context.Context, sel ast.SelectionSet, v *model.Size) graphql.Marshaler {
+func (ec *executionContext) marshalNWatermark2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐWatermark(ctx context.Context, sel ast.SelectionSet, v *model.Watermark) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
 		}
 		return graphql.Null
 	}
-	return ec._Size(ctx, sel, v)
-}
-
-func (ec *executionContext) unmarshalNSizeInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx context.Context, v interface{}) (model.SizeInput, error) {
-	return ec.unmarshalInputSizeInput(ctx, v)
-}
-
-func (ec *executionContext) unmarshalNSizeInput2ᚕᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInputᚄ(ctx context.Context, v interface{}) ([]*model.SizeInput, error) {
-	var vSlice []interface{}
-	if v != nil {
-		if tmp1, ok := v.([]interface{}); ok {
-			vSlice = tmp1
-		} else {
-			vSlice = []interface{}{v}
-		}
-	}
-	var err error
-	res := make([]*model.SizeInput, len(vSlice))
-	for i := range vSlice {
-		res[i], err = ec.unmarshalNSizeInput2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx, vSlice[i])
-		if err != nil {
-			return nil, err
-		}
-	}
-	return res, nil
-}
-
-func (ec *executionContext) unmarshalNSizeInput2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx context.Context, v interface{}) (*model.SizeInput, error) {
-	if v == nil {
-		return nil, nil
-	}
-	res, err := ec.unmarshalNSizeInput2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐSizeInput(ctx, v)
-	return &res, err
-}
-
-func (ec *executionContext) unmarshalNString2string(ctx context.Context, v interface{}) (string, error) {
-	return graphql.UnmarshalString(v)
-}
-
-func (ec *executionContext) marshalNString2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
-	res := graphql.MarshalString(v)
-	if res == graphql.Null {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "must not be null")
-		}
-	}
-	return res
-}
-
-func (ec *executionContext) unmarshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, v interface{}) (graphql.Upload, error) {
-	return graphql.UnmarshalUpload(v)
-}
-
-func (ec *executionContext) marshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, sel ast.SelectionSet, v graphql.Upload) graphql.Marshaler {
-	res := graphql.MarshalUpload(v)
-	if res == graphql.Null {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "must not be null")
-		}
-	}
-	return res
+	return ec._Watermark(ctx, sel, v)
 }
 
 func (ec *executionContext) marshalN__Directive2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx context.Context, sel ast.SelectionSet, v introspection.Directive) graphql.Marshaler {
@@ -2883,6 +8772,30 @@ func (ec *executionContext) marshalN__TypeKind2string(ctx context.Context, sel a
 	return res
 }
 
+func (ec *executionContext) unmarshalOAnchor2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx context.Context, v interface{}) (model.Anchor, error) {
+	var res model.Anchor
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOAnchor2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx context.Context, sel ast.SelectionSet, v model.Anchor) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOAnchor2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx context.Context, v interface{}) (*model.Anchor, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOAnchor2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOAnchor2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐAnchor(ctx context.Context, sel ast.SelectionSet, v *model.Anchor) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOBoolean2bool(ctx context.Context, v interface{}) (bool, error) {
 	return graphql.UnmarshalBoolean(v)
 }
@@ -2906,6 +8819,241 @@ func (ec *executionContext) marshalOBoolean2ᚖbool(ctx context.Context, sel ast
 	return ec.marshalOBoolean2bool(ctx, sel, *v)
 }
 
+func (ec *executionContext) unmarshalOColorModel2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐColorModel(ctx context.Context, v interface{}) (model.ColorModel, error) {
+	var res model.ColorModel
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOColorModel2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐColorModel(ctx context.Context, sel ast.SelectionSet, v model.ColorModel) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOColorModel2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐColorModel(ctx context.Context, v interface{}) (*model.ColorModel, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOColorModel2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐColorModel(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOColorModel2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐColorModel(ctx context.Context, sel ast.SelectionSet, v *model.ColorModel) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) marshalOExif2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐExif(ctx context.Context, sel ast.SelectionSet, v model.Exif) graphql.Marshaler {
+	return ec._Exif(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalOExif2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐExif(ctx context.Context, sel ast.SelectionSet, v *model.Exif) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Exif(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOFloat2float64(ctx context.Context, v interface{}) (float64, error) {
+	return graphql.UnmarshalFloat(v)
+}
+
+func (ec *executionContext) marshalOFloat2float64(ctx context.Context, sel ast.SelectionSet, v float64) graphql.Marshaler {
+	return graphql.MarshalFloat(v)
+}
+
+func (ec *executionContext) unmarshalOFloat2ᚖfloat64(ctx context.Context, v interface{}) (*float64, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOFloat2float64(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOFloat2ᚖfloat64(ctx context.Context, sel ast.SelectionSet, v *float64) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalOFloat2float64(ctx, sel, *v)
+}
+
+func (ec *executionContext) marshalOFocalPoint2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐFocalPoint(ctx context.Context, sel ast.SelectionSet, v model.FocalPoint) graphql.Marshaler {
+	return ec._FocalPoint(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalOFocalPoint2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐFocalPoint(ctx context.Context, sel ast.SelectionSet, v *model.FocalPoint) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._FocalPoint(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOID2string(ctx context.Context, v interface{}) (string, error) {
+	return graphql.UnmarshalID(v)
+}
+
+func (ec *executionContext) marshalOID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
+	return graphql.MarshalID(v)
+}
+
+func (ec *executionContext) unmarshalOID2ᚖstring(ctx context.Context, v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOID2string(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOID2ᚖstring(ctx context.Context, sel ast.SelectionSet, v *string) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalOID2string(ctx, sel, *v)
+}
+
+func (ec *executionContext) unmarshalOImageFilter2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFilter(ctx context.Context, v interface{}) (model.ImageFilter, error) {
+	return ec.unmarshalInputImageFilter(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOImageFilter2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFilter(ctx context.Context, v interface{}) (*model.ImageFilter, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOImageFilter2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFilter(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalOImageFormat2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx context.Context, v interface{}) (model.ImageFormat, error) {
+	var res model.ImageFormat
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOImageFormat2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx context.Context, sel ast.SelectionSet, v model.ImageFormat) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOImageFormat2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx context.Context, v interface{}) (*model.ImageFormat, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOImageFormat2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOImageFormat2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageFormat(ctx context.Context, sel ast.SelectionSet, v *model.ImageFormat) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOImageSort2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSort(ctx context.Context, v interface{}) (model.ImageSort, error) {
+	return ec.unmarshalInputImageSort(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOImageSort2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSort(ctx context.Context, v interface{}) (*model.ImageSort, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOImageSort2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐImageSort(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalOInt2int(ctx context.Context, v interface{}) (int, error) {
+	return graphql.UnmarshalInt(v)
+}
+
+func (ec *executionContext) marshalOInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
+	return graphql.MarshalInt(v)
+}
+
+func (ec *executionContext) unmarshalOInt2ᚖint(ctx context.Context, v interface{}) (*int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOInt2int(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOInt2ᚖint(ctx context.Context, sel ast.SelectionSet, v *int) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalOInt2int(ctx, sel, *v)
+}
+
+func (ec *executionContext) unmarshalOMetadataPolicy2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx context.Context, v interface{}) (model.MetadataPolicy, error) {
+	var res model.MetadataPolicy
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOMetadataPolicy2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx context.Context, sel ast.SelectionSet, v model.MetadataPolicy) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOMetadataPolicy2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx context.Context, v interface{}) (*model.MetadataPolicy, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOMetadataPolicy2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOMetadataPolicy2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐMetadataPolicy(ctx context.Context, sel ast.SelectionSet, v *model.MetadataPolicy) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOPNGCompression2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx context.Context, v interface{}) (model.PNGCompression, error) {
+	var res model.PNGCompression
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOPNGCompression2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx context.Context, sel ast.SelectionSet, v model.PNGCompression) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOPNGCompression2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx context.Context, v interface{}) (*model.PNGCompression, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOPNGCompression2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOPNGCompression2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐPNGCompression(ctx context.Context, sel ast.SelectionSet, v *model.PNGCompression) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx context.Context, v interface{}) (model.ResizeMode, error) {
+	var res model.ResizeMode
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx context.Context, sel ast.SelectionSet, v model.ResizeMode) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOResizeMode2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx context.Context, v interface{}) (*model.ResizeMode, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOResizeMode2githubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOResizeMode2ᚖgithubᚗcomᚋporteyᚋimageᚑresizerᚋgraphᚋmodelᚐResizeMode(ctx context.Context, sel ast.SelectionSet, v *model.ResizeMode) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOString2string(ctx context.Context, v interface{}) (string, error) {
 	return graphql.UnmarshalString(v)
 }
@@ -2952,6 +9100,29 @@ func (ec *executionContext) marshalOTime2ᚖtimeᚐTime(ctx context.Context, sel
 	return ec.marshalOTime2timeᚐTime(ctx, sel, *v)
 }
 
+func (ec *executionContext) unmarshalOUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, v interface{}) (graphql.Upload, error) {
+	return graphql.UnmarshalUpload(v)
+}
+
+func (ec *executionContext) marshalOUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, sel ast.SelectionSet, v graphql.Upload) graphql.Marshaler {
+	return graphql.MarshalUpload(v)
+}
+
+func (ec *executionContext) unmarshalOUpload2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, v interface{}) (*graphql.Upload, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOUpload2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, sel ast.SelectionSet, v *graphql.Upload) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalOUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx, sel, *v)
+}
+
 func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐEnumValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.EnumValue) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
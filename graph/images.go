@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	serviceerrors "github.com/portey/image-resizer/errors"
+	servicemodel "github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/signature"
+	log "github.com/sirupsen/logrus"
+)
+
+const imagesPrefix = "/img/"
+
+var sizePattern = regexp.MustCompile(`^(\d+)x(\d+)\.([a-z]+)$`)
+
+var extensions = map[string]servicemodel.Format{
+	"jpg":  servicemodel.FormatJPEG,
+	"jpeg": servicemodel.FormatJPEG,
+	"png":  servicemodel.FormatPNG,
+	"gif":  servicemodel.FormatGIF,
+	"bmp":  servicemodel.FormatBMP,
+	"tif":  servicemodel.FormatTIFF,
+	"tiff": servicemodel.FormatTIFF,
+}
+
+type (
+	ImageSource interface {
+		Variant(ctx context.Context, id string, request servicemodel.SizeRequest) (*servicemodel.Size, error)
+		Read(ctx context.Context, path string) (io.Reader, error)
+	}
+
+	ImagesConfig struct {
+		CacheMaxAge time.Duration
+		Signer      *signature.Signer
+	}
+
+	// imageHandler serves variants by signed URLs like /img/{id}/{width}x{height}.{ext}?mode=fill&exp=..&kid=..&sig=..,
+	// a missing variant is created on the first request.
+	imageHandler struct {
+		images ImageSource
+		config ImagesConfig
+	}
+)
+
+func (h *imageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.config.Signer.Verify(r.URL.Path, r.URL.Query(), time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	id, request, err := parseImageURL(r.URL.Path, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	size, err := h.images.Variant(r.Context(), id, request)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	etag := sizeETag(size)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.config.CacheMaxAge.Seconds())))
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", size.OutputFormat().MimeType())
+	if size.Bytes > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(size.Bytes, 10))
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	content, err := h.images.Read(r.Context(), size.Path)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if closer, ok := content.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Error("can't write image response ", err)
+	}
+}
+
+func parseImageURL(path string, query url.Values) (string, servicemodel.SizeRequest, error) {
+	parts := strings.Split(strings.TrimPrefix(path, imagesPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		return "", servicemodel.SizeRequest{}, fmt.Errorf("path must be %s{id}/{width}x{height}.{ext}", imagesPrefix)
+	}
+
+	match := sizePattern.FindStringSubmatch(parts[1])
+	if match == nil {
+		return "", servicemodel.SizeRequest{}, fmt.Errorf("invalid size %q", parts[1])
+	}
+	format, ok := extensions[match[3]]
+	if !ok {
+		return "", servicemodel.SizeRequest{}, fmt.Errorf("unsupported extension %q", match[3])
+	}
+
+	request := servicemodel.SizeRequest{
+		Mode:        servicemodel.ResizeMode(query.Get("mode")),
+		Anchor:      servicemodel.Anchor(query.Get("anchor")),
+		Format:      format,
+		Compression: servicemodel.Compression(query.Get("compression")),
+		Watermark:   query.Get("watermark"),
+	}
+	request.Width, _ = strconv.Atoi(match[1])
+	request.Height, _ = strconv.Atoi(match[2])
+	if background := query.Get("background"); background != "" {
+		request.Background = "#" + strings.TrimPrefix(background, "#")
+	}
+	if quality := query.Get("quality"); quality != "" {
+		q, err := strconv.Atoi(quality)
+		if err != nil {
+			return "", servicemodel.SizeRequest{}, fmt.Errorf("invalid quality %q", quality)
+		}
+		request.Quality = q
+	}
+	if frame := query.Get("frame"); frame != "" {
+		n, err := strconv.Atoi(frame)
+		if err != nil {
+			return "", servicemodel.SizeRequest{}, fmt.Errorf("invalid frame %q", frame)
+		}
+		request.Frame = n
+	}
+	operations, err := servicemodel.ParseOperations(query.Get("ops"))
+	if err != nil {
+		return "", servicemodel.SizeRequest{}, err
+	}
+	request.Operations = operations
+
+	return parts[0], request, nil
+}
+
+func sizeETag(size *servicemodel.Size) string {
+	// variants are stored under content-addressed paths, so the path identifies the content
+	sum := sha1.Sum([]byte(size.Path)) // nolint:gosec
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case serviceerrors.InvalidParams:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch err {
+	case serviceerrors.NotFound:
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	case serviceerrors.ImageTooLarge:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
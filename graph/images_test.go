@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	serviceerrors "github.com/portey/image-resizer/errors"
+	servicemodel "github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+type imageSourceStub struct {
+	requests []servicemodel.SizeRequest
+	reads    int
+}
+
+func (s *imageSourceStub) Variant(_ context.Context, id string, request servicemodel.SizeRequest) (*servicemodel.Size, error) {
+	if id != "known" {
+		return nil, serviceerrors.NotFound
+	}
+	if request.Width > 1000 {
+		return nil, serviceerrors.ImageTooLarge
+	}
+	s.requests = append(s.requests, request)
+
+	return &servicemodel.Size{
+		Path:   "2020/01/01/100_50/test.jpeg",
+		Width:  request.Width,
+		Height: request.Height,
+		Bytes:  7,
+		Format: request.Format,
+	}, nil
+}
+
+func (s *imageSourceStub) Read(context.Context, string) (io.Reader, error) {
+	s.reads++
+	return strings.NewReader("content"), nil
+}
+
+var testSigner = signature.New([]signature.Key{{ID: "test", Secret: "secret"}})
+
+func signedTarget(t *testing.T, target string) string {
+	u, err := url.Parse(target)
+	assert.NoError(t, err)
+
+	query, err := testSigner.Sign(u.Path, u.Query(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func TestImageHandler_Serve(t *testing.T) {
+	source := &imageSourceStub{}
+	handler := &imageHandler{images: source, config: ImagesConfig{CacheMaxAge: time.Hour, Signer: testSigner}}
+	target := signedTarget(t, "/img/known/100x50.jpg?mode=fill&anchor=top&quality=80")
+
+	rr := httptest.NewRecorder()
+	rq := httptest.NewRequest("GET", target, nil)
+	handler.ServeHTTP(rr, rq)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "content", rr.Body.String())
+	assert.Equal(t, "image/jpeg", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "7", rr.Header().Get("Content-Length"))
+	assert.Equal(t, "public, max-age=3600", rr.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	assert.Equal(t, []servicemodel.SizeRequest{{
+		Width:   100,
+		Height:  50,
+		Mode:    servicemodel.ResizeModeFill,
+		Anchor:  servicemodel.AnchorTop,
+		Format:  servicemodel.FormatJPEG,
+		Quality: 80,
+	}}, source.requests)
+
+	// revalidation doesn't read the content
+	etag := rr.Header().Get("ETag")
+	rr = httptest.NewRecorder()
+	rq = httptest.NewRequest("GET", target, nil)
+	rq.Header.Set("If-None-Match", etag)
+	handler.ServeHTTP(rr, rq)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, 1, source.reads)
+}
+
+func TestImageHandler_Errors(t *testing.T) {
+	source := &imageSourceStub{}
+	handler := &imageHandler{images: source, config: ImagesConfig{Signer: testSigner}}
+
+	f := func(method, target string, code int) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, signedTarget(t, target), nil))
+		assert.Equal(t, code, rr.Code, target)
+	}
+
+	f("GET", "/img/unknown/100x50.png", http.StatusNotFound)
+	f("GET", "/img/known/100x50.webp", http.StatusBadRequest)
+	f("GET", "/img/known/2000x50.png", http.StatusUnprocessableEntity)
+	f("GET", "/img/known/100-50.png", http.StatusBadRequest)
+	f("GET", "/img/known/100x50.png?quality=high", http.StatusBadRequest)
+	f("GET", "/img/known/100x50.png?ops=blur:x", http.StatusBadRequest)
+	f("GET", "/img/known/100x50.png?frame=last", http.StatusBadRequest)
+	f("GET", "/img/known", http.StatusBadRequest)
+	f("POST", "/img/known/100x50.png", http.StatusMethodNotAllowed)
+
+	// unsigned and tampered urls are rejected before resizing
+	for _, target := range []string{
+		"/img/known/100x50.png",
+		strings.Replace(signedTarget(t, "/img/known/100x50.png"), "100x50", "5000x5000", 1),
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusForbidden, rr.Code, target)
+	}
+	assert.Empty(t, source.requests)
+}
+
+func TestImageURLs_Signed(t *testing.T) {
+	source := &imageSourceStub{}
+	handler := &imageHandler{images: source, config: ImagesConfig{Signer: testSigner}}
+	urls := NewImageURLs("http://localhost:8080/", testSigner)
+
+	target, err := urls.Signed("known", servicemodel.Size{
+		Width:      300,
+		Height:     200,
+		Mode:       servicemodel.ResizeModePad,
+		Background: "#000000",
+		Format:     servicemodel.FormatPNG,
+		Operations: []servicemodel.Operation{{Name: servicemodel.OperationResize}, {Name: servicemodel.OperationSharpen, Value: 0.5}},
+		Watermark:  "logo",
+	}, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(target, "http://localhost:8080/img/known/300x200.png?"))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []servicemodel.SizeRequest{{
+		Width:      300,
+		Height:     200,
+		Mode:       servicemodel.ResizeModePad,
+		Background: "#000000",
+		Format:     servicemodel.FormatPNG,
+		Operations: []servicemodel.Operation{{Name: servicemodel.OperationResize}, {Name: servicemodel.OperationSharpen, Value: 0.5}},
+		Watermark:  "logo",
+	}}, source.requests)
+}
+
+func TestParseImageURL(t *testing.T) {
+	id, request, err := parseImageURL("/img/abc/300x200.png", url.Values{
+		"mode":        {"pad"},
+		"background":  {"000000"},
+		"compression": {"best-speed"},
+		"frame":       {"2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", id)
+	assert.Equal(t, servicemodel.SizeRequest{
+		Width:       300,
+		Height:      200,
+		Mode:        servicemodel.ResizeModePad,
+		Background:  "#000000",
+		Format:      servicemodel.FormatPNG,
+		Compression: servicemodel.CompressionBestSpeed,
+		Frame:       2,
+	}, request)
+}
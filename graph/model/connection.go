@@ -0,0 +1,11 @@
+package model
+
+import servicemodel "github.com/portey/image-resizer/model"
+
+type ImageConnection struct {
+	Edges    []*ImageEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+
+	// Filter is used to count images only if the total count is requested.
+	Filter servicemodel.ImageFilter `json:"-"`
+}
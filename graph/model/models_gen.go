@@ -3,26 +3,695 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 )
 
+type Exif struct {
+	Make             *string  `json:"make"`
+	Model            *string  `json:"model"`
+	LensModel        *string  `json:"lensModel"`
+	Software         *string  `json:"software"`
+	DateTimeOriginal *string  `json:"dateTimeOriginal"`
+	ExposureTime     *string  `json:"exposureTime"`
+	FNumber          *float64 `json:"fNumber"`
+	Iso              *int     `json:"iso"`
+	FocalLength      *float64 `json:"focalLength"`
+	HasGps           bool     `json:"hasGps"`
+}
+
+type FocalPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
 type Image struct {
-	ID         string     `json:"id"`
-	Path       string     `json:"path"`
-	ClientName string     `json:"clientName"`
-	MimeType   string     `json:"mimeType"`
-	Size       int        `json:"size"`
-	UploadAt   *time.Time `json:"uploadAt"`
-	Sizes      []*Size    `json:"sizes"`
+	ID             string          `json:"id"`
+	Path           string          `json:"path"`
+	ClientName     string          `json:"clientName"`
+	MimeType       string          `json:"mimeType"`
+	Format         *ImageFormat    `json:"format"`
+	Size           int             `json:"size"`
+	Hash           *string         `json:"hash"`
+	Width          *int            `json:"width"`
+	Height         *int            `json:"height"`
+	Orientation    *int            `json:"orientation"`
+	ColorModel     *ColorModel     `json:"colorModel"`
+	HasAlpha       bool            `json:"hasAlpha"`
+	Exif           *Exif           `json:"exif"`
+	MetadataPolicy *MetadataPolicy `json:"metadataPolicy"`
+	FocalPoint     *FocalPoint     `json:"focalPoint"`
+	UploadAt       *time.Time      `json:"uploadAt"`
+	Sizes          []*Size         `json:"sizes"`
+	JobID          *string         `json:"jobId"`
 }
 
-type Size struct {
-	Path   string `json:"path"`
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
+type ImageEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Image `json:"node"`
 }
 
-type SizeInput struct {
+type ImageFilter struct {
+	MimeType         *string    `json:"mimeType"`
+	UploadedAfter    *time.Time `json:"uploadedAfter"`
+	UploadedBefore   *time.Time `json:"uploadedBefore"`
+	ClientNamePrefix *string    `json:"clientNamePrefix"`
+}
+
+type ImageSort struct {
+	Field     ImageSortField `json:"field"`
+	Direction SortDirection  `json:"direction"`
+}
+
+type Job struct {
+	ID        string    `json:"id"`
+	ImageID   string    `json:"imageId"`
+	Status    JobStatus `json:"status"`
+	Error     *string   `json:"error"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Operation struct {
+	Name  OperationName `json:"name"`
+	Value *float64      `json:"value"`
+}
+
+type OperationInput struct {
+	Name  OperationName `json:"name"`
+	Value *float64      `json:"value"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+type Preset struct {
+	Name        string          `json:"name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Mode        ResizeMode      `json:"mode"`
+	Anchor      *Anchor         `json:"anchor"`
+	Background  *string         `json:"background"`
+	Format      *ImageFormat    `json:"format"`
+	Quality     *int            `json:"quality"`
+	Compression *PNGCompression `json:"compression"`
+	Operations  []*Operation    `json:"operations"`
+	Watermark   *string         `json:"watermark"`
+	Frame       *int            `json:"frame"`
+	Version     int             `json:"version"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+type RectInput struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
 	Width  int `json:"width"`
 	Height int `json:"height"`
 }
+
+type SizeInput struct {
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Mode        *ResizeMode       `json:"mode"`
+	Anchor      *Anchor           `json:"anchor"`
+	Background  *string           `json:"background"`
+	Format      *ImageFormat      `json:"format"`
+	Quality     *int              `json:"quality"`
+	Compression *PNGCompression   `json:"compression"`
+	Operations  []*OperationInput `json:"operations"`
+	Watermark   *string           `json:"watermark"`
+	Frame       *int              `json:"frame"`
+}
+
+type Watermark struct {
+	ID        string    `json:"id"`
+	Position  Anchor    `json:"position"`
+	Opacity   float64   `json:"opacity"`
+	Scale     float64   `json:"scale"`
+	Tile      bool      `json:"tile"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Anchor string
+
+const (
+	AnchorCenter      Anchor = "CENTER"
+	AnchorTopLeft     Anchor = "TOP_LEFT"
+	AnchorTop         Anchor = "TOP"
+	AnchorTopRight    Anchor = "TOP_RIGHT"
+	AnchorLeft        Anchor = "LEFT"
+	AnchorRight       Anchor = "RIGHT"
+	AnchorBottomLeft  Anchor = "BOTTOM_LEFT"
+	AnchorBottom      Anchor = "BOTTOM"
+	AnchorBottomRight Anchor = "BOTTOM_RIGHT"
+	AnchorSmart       Anchor = "SMART"
+)
+
+var AllAnchor = []Anchor{
+	AnchorCenter,
+	AnchorTopLeft,
+	AnchorTop,
+	AnchorTopRight,
+	AnchorLeft,
+	AnchorRight,
+	AnchorBottomLeft,
+	AnchorBottom,
+	AnchorBottomRight,
+	AnchorSmart,
+}
+
+func (e Anchor) IsValid() bool {
+	switch e {
+	case AnchorCenter, AnchorTopLeft, AnchorTop, AnchorTopRight, AnchorLeft, AnchorRight, AnchorBottomLeft, AnchorBottom, AnchorBottomRight, AnchorSmart:
+		return true
+	}
+	return false
+}
+
+func (e Anchor) String() string {
+	return string(e)
+}
+
+func (e *Anchor) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Anchor(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Anchor", str)
+	}
+	return nil
+}
+
+func (e Anchor) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ColorModel string
+
+const (
+	ColorModelGray      ColorModel = "GRAY"
+	ColorModelGrayAlpha ColorModel = "GRAY_ALPHA"
+	ColorModelRgb       ColorModel = "RGB"
+	ColorModelRgba      ColorModel = "RGBA"
+	ColorModelPaletted  ColorModel = "PALETTED"
+	ColorModelYcbcr     ColorModel = "YCBCR"
+	ColorModelCmyk      ColorModel = "CMYK"
+)
+
+var AllColorModel = []ColorModel{
+	ColorModelGray,
+	ColorModelGrayAlpha,
+	ColorModelRgb,
+	ColorModelRgba,
+	ColorModelPaletted,
+	ColorModelYcbcr,
+	ColorModelCmyk,
+}
+
+func (e ColorModel) IsValid() bool {
+	switch e {
+	case ColorModelGray, ColorModelGrayAlpha, ColorModelRgb, ColorModelRgba, ColorModelPaletted, ColorModelYcbcr, ColorModelCmyk:
+		return true
+	}
+	return false
+}
+
+func (e ColorModel) String() string {
+	return string(e)
+}
+
+func (e *ColorModel) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ColorModel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ColorModel", str)
+	}
+	return nil
+}
+
+func (e ColorModel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type FlipAxis string
+
+const (
+	FlipAxisHorizontal FlipAxis = "HORIZONTAL"
+	FlipAxisVertical   FlipAxis = "VERTICAL"
+)
+
+var AllFlipAxis = []FlipAxis{
+	FlipAxisHorizontal,
+	FlipAxisVertical,
+}
+
+func (e FlipAxis) IsValid() bool {
+	switch e {
+	case FlipAxisHorizontal, FlipAxisVertical:
+		return true
+	}
+	return false
+}
+
+func (e FlipAxis) String() string {
+	return string(e)
+}
+
+func (e *FlipAxis) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FlipAxis(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FlipAxis", str)
+	}
+	return nil
+}
+
+func (e FlipAxis) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImageFormat string
+
+const (
+	ImageFormatJpeg ImageFormat = "JPEG"
+	ImageFormatPng  ImageFormat = "PNG"
+	ImageFormatGif  ImageFormat = "GIF"
+	ImageFormatBmp  ImageFormat = "BMP"
+	ImageFormatTiff ImageFormat = "TIFF"
+)
+
+var AllImageFormat = []ImageFormat{
+	ImageFormatJpeg,
+	ImageFormatPng,
+	ImageFormatGif,
+	ImageFormatBmp,
+	ImageFormatTiff,
+}
+
+func (e ImageFormat) IsValid() bool {
+	switch e {
+	case ImageFormatJpeg, ImageFormatPng, ImageFormatGif, ImageFormatBmp, ImageFormatTiff:
+		return true
+	}
+	return false
+}
+
+func (e ImageFormat) String() string {
+	return string(e)
+}
+
+func (e *ImageFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageFormat", str)
+	}
+	return nil
+}
+
+func (e ImageFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImageSortField string
+
+const (
+	ImageSortFieldUploadAt ImageSortField = "UPLOAD_AT"
+	ImageSortFieldSize     ImageSortField = "SIZE"
+)
+
+var AllImageSortField = []ImageSortField{
+	ImageSortFieldUploadAt,
+	ImageSortFieldSize,
+}
+
+func (e ImageSortField) IsValid() bool {
+	switch e {
+	case ImageSortFieldUploadAt, ImageSortFieldSize:
+		return true
+	}
+	return false
+}
+
+func (e ImageSortField) String() string {
+	return string(e)
+}
+
+func (e *ImageSortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageSortField", str)
+	}
+	return nil
+}
+
+func (e ImageSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "PENDING"
+	JobStatusRunning JobStatus = "RUNNING"
+	JobStatusDone    JobStatus = "DONE"
+	JobStatusFailed  JobStatus = "FAILED"
+)
+
+var AllJobStatus = []JobStatus{
+	JobStatusPending,
+	JobStatusRunning,
+	JobStatusDone,
+	JobStatusFailed,
+}
+
+func (e JobStatus) IsValid() bool {
+	switch e {
+	case JobStatusPending, JobStatusRunning, JobStatusDone, JobStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e JobStatus) String() string {
+	return string(e)
+}
+
+func (e *JobStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JobStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JobStatus", str)
+	}
+	return nil
+}
+
+func (e JobStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MetadataPolicy string
+
+const (
+	MetadataPolicyKeep     MetadataPolicy = "KEEP"
+	MetadataPolicyStripGps MetadataPolicy = "STRIP_GPS"
+	MetadataPolicyStripAll MetadataPolicy = "STRIP_ALL"
+)
+
+var AllMetadataPolicy = []MetadataPolicy{
+	MetadataPolicyKeep,
+	MetadataPolicyStripGps,
+	MetadataPolicyStripAll,
+}
+
+func (e MetadataPolicy) IsValid() bool {
+	switch e {
+	case MetadataPolicyKeep, MetadataPolicyStripGps, MetadataPolicyStripAll:
+		return true
+	}
+	return false
+}
+
+func (e MetadataPolicy) String() string {
+	return string(e)
+}
+
+func (e *MetadataPolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MetadataPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MetadataPolicy", str)
+	}
+	return nil
+}
+
+func (e MetadataPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OperationName string
+
+const (
+	OperationNameResize     OperationName = "RESIZE"
+	OperationNameBlur       OperationName = "BLUR"
+	OperationNameSharpen    OperationName = "SHARPEN"
+	OperationNameGamma      OperationName = "GAMMA"
+	OperationNameContrast   OperationName = "CONTRAST"
+	OperationNameBrightness OperationName = "BRIGHTNESS"
+	OperationNameSaturation OperationName = "SATURATION"
+	OperationNameGrayscale  OperationName = "GRAYSCALE"
+	OperationNameInvert     OperationName = "INVERT"
+)
+
+var AllOperationName = []OperationName{
+	OperationNameResize,
+	OperationNameBlur,
+	OperationNameSharpen,
+	OperationNameGamma,
+	OperationNameContrast,
+	OperationNameBrightness,
+	OperationNameSaturation,
+	OperationNameGrayscale,
+	OperationNameInvert,
+}
+
+func (e OperationName) IsValid() bool {
+	switch e {
+	case OperationNameResize, OperationNameBlur, OperationNameSharpen, OperationNameGamma, OperationNameContrast, OperationNameBrightness, OperationNameSaturation, OperationNameGrayscale, OperationNameInvert:
+		return true
+	}
+	return false
+}
+
+func (e OperationName) String() string {
+	return string(e)
+}
+
+func (e *OperationName) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OperationName(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OperationName", str)
+	}
+	return nil
+}
+
+func (e OperationName) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PNGCompression string
+
+const (
+	PNGCompressionDefault         PNGCompression = "DEFAULT"
+	PNGCompressionNone            PNGCompression = "NONE"
+	PNGCompressionBestSpeed       PNGCompression = "BEST_SPEED"
+	PNGCompressionBestCompression PNGCompression = "BEST_COMPRESSION"
+)
+
+var AllPNGCompression = []PNGCompression{
+	PNGCompressionDefault,
+	PNGCompressionNone,
+	PNGCompressionBestSpeed,
+	PNGCompressionBestCompression,
+}
+
+func (e PNGCompression) IsValid() bool {
+	switch e {
+	case PNGCompressionDefault, PNGCompressionNone, PNGCompressionBestSpeed, PNGCompressionBestCompression:
+		return true
+	}
+	return false
+}
+
+func (e PNGCompression) String() string {
+	return string(e)
+}
+
+func (e *PNGCompression) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PNGCompression(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PNGCompression", str)
+	}
+	return nil
+}
+
+func (e PNGCompression) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ResizeMode string
+
+const (
+	ResizeModeStretch ResizeMode = "STRETCH"
+	ResizeModeFit     ResizeMode = "FIT"
+	ResizeModeFill    ResizeMode = "FILL"
+	ResizeModePad     ResizeMode = "PAD"
+	ResizeModeLimit   ResizeMode = "LIMIT"
+)
+
+var AllResizeMode = []ResizeMode{
+	ResizeModeStretch,
+	ResizeModeFit,
+	ResizeModeFill,
+	ResizeModePad,
+	ResizeModeLimit,
+}
+
+func (e ResizeMode) IsValid() bool {
+	switch e {
+	case ResizeModeStretch, ResizeModeFit, ResizeModeFill, ResizeModePad, ResizeModeLimit:
+		return true
+	}
+	return false
+}
+
+func (e ResizeMode) String() string {
+	return string(e)
+}
+
+func (e *ResizeMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ResizeMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ResizeMode", str)
+	}
+	return nil
+}
+
+func (e ResizeMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SizeStatus string
+
+const (
+	SizeStatusReady   SizeStatus = "READY"
+	SizeStatusPending SizeStatus = "PENDING"
+	SizeStatusFailed  SizeStatus = "FAILED"
+)
+
+var AllSizeStatus = []SizeStatus{
+	SizeStatusReady,
+	SizeStatusPending,
+	SizeStatusFailed,
+}
+
+func (e SizeStatus) IsValid() bool {
+	switch e {
+	case SizeStatusReady, SizeStatusPending, SizeStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e SizeStatus) String() string {
+	return string(e)
+}
+
+func (e *SizeStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SizeStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SizeStatus", str)
+	}
+	return nil
+}
+
+func (e SizeStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,24 @@
+package model
+
+import servicemodel "github.com/portey/image-resizer/model"
+
+type Size struct {
+	Path        string          `json:"path"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Mode        ResizeMode      `json:"mode"`
+	Anchor      *Anchor         `json:"anchor"`
+	Background  *string         `json:"background"`
+	Format      ImageFormat     `json:"format"`
+	Quality     *int            `json:"quality"`
+	Compression *PNGCompression `json:"compression"`
+	Operations  []*Operation    `json:"operations"`
+	Status      SizeStatus      `json:"status"`
+	Watermark   *string         `json:"watermark"`
+	Frame       *int            `json:"frame"`
+	Preset      *string         `json:"preset"`
+
+	// ImageID and Variant are used to build the signed url of the size.
+	ImageID string            `json:"-"`
+	Variant servicemodel.Size `json:"-"`
+}
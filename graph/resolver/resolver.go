@@ -1,15 +1,45 @@
 package resolver
 
-import "github.com/portey/image-resizer/service"
+import (
+	"fmt"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/graph"
+	"github.com/portey/image-resizer/service"
+)
 
 //go:generate go run github.com/99designs/gqlgen
 
+const defaultURLExpiresIn = time.Hour
+
 type Resolver struct {
 	service *service.ImageService
+	urls    *graph.ImageURLs
+	// maxURLExpiresIn limits the validity of signed URLs, zero doesn't limit it
+	maxURLExpiresIn time.Duration
 }
 
-func New(service *service.ImageService) *Resolver {
+func New(service *service.ImageService, urls *graph.ImageURLs, maxURLExpiresIn time.Duration) *Resolver {
 	return &Resolver{
-		service: service,
+		service:         service,
+		urls:            urls,
+		maxURLExpiresIn: maxURLExpiresIn,
+	}
+}
+
+func (r *Resolver) urlExpiresIn(expiresIn *int) (time.Duration, error) {
+	if expiresIn == nil {
+		return defaultURLExpiresIn, nil
 	}
+	if *expiresIn < 1 {
+		return 0, errors.InvalidParams{{Param: "expiresIn", Message: "min=1"}}
+	}
+
+	ttl := time.Duration(*expiresIn) * time.Second
+	if max := r.maxURLExpiresIn; max > 0 && ttl > max {
+		return 0, errors.InvalidParams{{Param: "expiresIn", Message: fmt.Sprintf("max=%d", max/time.Second)}}
+	}
+
+	return ttl, nil
 }
@@ -5,6 +5,7 @@ package resolver
 
 import (
 	"context"
+	"strings"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/portey/image-resizer/graph/generated"
@@ -12,16 +13,32 @@ import (
 	servicemodel "github.com/portey/image-resizer/model"
 )
 
-func (r *mutationResolver) UploadImage(ctx context.Context, image graphql.Upload, sizes []*model.SizeInput) (*model.Image, error) {
+func (r *imageConnectionResolver) TotalCount(ctx context.Context, obj *model.ImageConnection) (int, error) {
+	return r.service.Count(ctx, obj.Filter)
+}
+
+func (r *mutationResolver) UploadImage(ctx context.Context, image graphql.Upload, sizes []*model.SizeInput, presets []string, async *bool, metadataPolicy *model.MetadataPolicy, dedupe *bool) (*model.Image, error) {
 	upload := servicemodel.ImageUpload{
 		Content:  image.File,
 		Filename: image.Filename,
 		Size:     image.Size,
 		MimeType: image.ContentType,
+		Dedupe:   dedupe != nil && *dedupe,
+	}
+	if metadataPolicy != nil {
+		upload.MetadataPolicy = servicemodel.MetadataPolicy(strings.ReplaceAll(strings.ToLower(metadataPolicy.String()), "_", "-"))
+	}
+	sz, err := r.sizeRequests(ctx, sizes, presets)
+	if err != nil {
+		return nil, err
+	}
+
+	doUpload := r.service.Upload
+	if async != nil && *async {
+		doUpload = r.service.UploadAsync
 	}
-	sz := graphQLSizesToModelSizes(sizes)
 
-	i, err := r.service.Upload(ctx, upload, sz)
+	i, err := doUpload(ctx, upload, sz)
 	if err != nil {
 		return nil, err
 	}
@@ -29,8 +46,11 @@ func (r *mutationResolver) UploadImage(ctx context.Context, image graphql.Upload
 	return modelImageToGraphQLImage(i), nil
 }
 
-func (r *mutationResolver) ResizeImage(ctx context.Context, imageID string, sizes []*model.SizeInput) (*model.Image, error) {
-	sz := graphQLSizesToModelSizes(sizes)
+func (r *mutationResolver) ResizeImage(ctx context.Context, imageID string, sizes []*model.SizeInput, presets []string) (*model.Image, error) {
+	sz, err := r.sizeRequests(ctx, sizes, presets)
+	if err != nil {
+		return nil, err
+	}
 
 	i, err := r.service.Resize(ctx, imageID, sz)
 	if err != nil {
@@ -40,8 +60,131 @@ func (r *mutationResolver) ResizeImage(ctx context.Context, imageID string, size
 	return modelImageToGraphQLImage(i), nil
 }
 
-func (r *queryResolver) Images(ctx context.Context, limit int, offset int) ([]*model.Image, error) {
-	list, err := r.service.List(ctx, limit, offset)
+func (r *mutationResolver) DeleteImage(ctx context.Context, id string) (bool, error) {
+	if err := r.service.DeleteImage(ctx, id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) DeleteSize(ctx context.Context, imageID string, width int, height int) (*model.Image, error) {
+	i, err := r.service.DeleteSize(ctx, imageID, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelImageToGraphQLImage(i), nil
+}
+
+func (r *mutationResolver) SetFocalPoint(ctx context.Context, imageID string, x float64, y float64) (*model.Image, error) {
+	i, err := r.service.SetFocalPoint(ctx, imageID, servicemodel.FocalPoint{X: x, Y: y})
+	if err != nil {
+		return nil, err
+	}
+
+	return modelImageToGraphQLImage(i), nil
+}
+
+func (r *mutationResolver) RotateImage(ctx context.Context, id string, degrees int) (*model.Image, error) {
+	i, err := r.service.RotateImage(ctx, id, degrees)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelImageToGraphQLImage(i), nil
+}
+
+func (r *mutationResolver) FlipImage(ctx context.Context, id string, axis model.FlipAxis) (*model.Image, error) {
+	i, err := r.service.FlipImage(ctx, id, servicemodel.FlipAxis(strings.ToLower(axis.String())))
+	if err != nil {
+		return nil, err
+	}
+
+	return modelImageToGraphQLImage(i), nil
+}
+
+func (r *mutationResolver) CropImage(ctx context.Context, id string, rect model.RectInput) (*model.Image, error) {
+	i, err := r.service.CropImage(ctx, id, servicemodel.Rect{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height})
+	if err != nil {
+		return nil, err
+	}
+
+	return modelImageToGraphQLImage(i), nil
+}
+
+func (r *mutationResolver) SavePreset(ctx context.Context, name string, size model.SizeInput, rerender *bool) (*model.Preset, error) {
+	p, err := r.service.SavePreset(ctx, name, graphQLSizeToModelSize(&size), rerender != nil && *rerender)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelPresetToGraphQLPreset(p), nil
+}
+
+func (r *mutationResolver) DeletePreset(ctx context.Context, name string) (bool, error) {
+	if err := r.service.DeletePreset(ctx, name); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SaveWatermark(ctx context.Context, id string, overlay *graphql.Upload, position *model.Anchor, opacity *float64, scale *float64, tile *bool) (*model.Watermark, error) {
+	watermark := servicemodel.Watermark{
+		ID:       id,
+		Position: servicemodel.AnchorBottomRight,
+		Opacity:  servicemodel.DefaultWatermarkOpacity,
+		Scale:    servicemodel.DefaultWatermarkScale,
+		Tile:     tile != nil && *tile,
+	}
+	if position != nil {
+		watermark.Position = servicemodel.Anchor(strings.ReplaceAll(strings.ToLower(position.String()), "_", "-"))
+	}
+	if opacity != nil {
+		watermark.Opacity = *opacity
+	}
+	if scale != nil {
+		watermark.Scale = *scale
+	}
+
+	var upload *servicemodel.ImageUpload
+	if overlay != nil {
+		upload = &servicemodel.ImageUpload{
+			Content:  overlay.File,
+			Filename: overlay.Filename,
+			Size:     overlay.Size,
+			MimeType: overlay.ContentType,
+		}
+	}
+
+	w, err := r.service.SaveWatermark(ctx, watermark, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelWatermarkToGraphQLWatermark(w), nil
+}
+
+func (r *mutationResolver) DeleteWatermark(ctx context.Context, id string) (bool, error) {
+	if err := r.service.DeleteWatermark(ctx, id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *queryResolver) Image(ctx context.Context, id string) (*model.Image, error) {
+	i, err := r.service.Image(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelImageToGraphQLImage(i), nil
+}
+
+func (r *queryResolver) Images(ctx context.Context, limit int, offset int, filter *model.ImageFilter, sort *model.ImageSort) ([]*model.Image, error) {
+	list, err := r.service.List(ctx, graphQLFilterToModelFilter(filter), graphQLSortToModelSort(sort), limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -57,14 +200,142 @@ func (r *queryResolver) Images(ctx context.Context, limit int, offset int) ([]*m
 	return res, nil
 }
 
+func (r *queryResolver) ImagesConnection(ctx context.Context, first int, after *string, filter *model.ImageFilter) (*model.ImageConnection, error) {
+	request := servicemodel.PageRequest{First: first}
+	if after != nil {
+		request.After = *after
+	}
+	modelFilter := graphQLFilterToModelFilter(filter)
+
+	page, err := r.service.Page(ctx, modelFilter, request)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model.ImageEdge, len(page.Images))
+	for i, image := range page.Images {
+		edges[i] = &model.ImageEdge{
+			Cursor: servicemodel.CursorOf(image).String(),
+			Node:   modelImageToGraphQLImage(image),
+		}
+	}
+
+	pageInfo := &model.PageInfo{
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: request.After != "",
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = &edges[0].Cursor
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.ImageConnection{
+		Edges:    edges,
+		PageInfo: pageInfo,
+		Filter:   modelFilter,
+	}, nil
+}
+
+func (r *queryResolver) Job(ctx context.Context, id string) (*model.Job, error) {
+	job, err := r.service.Job(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelJobToGraphQLJob(job), nil
+}
+
+func (r *queryResolver) Presets(ctx context.Context) ([]*model.Preset, error) {
+	list, err := r.service.Presets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Preset, 0, len(list))
+	for _, item := range list {
+		res = append(res, modelPresetToGraphQLPreset(item))
+	}
+
+	return res, nil
+}
+
+func (r *queryResolver) Watermarks(ctx context.Context) ([]*model.Watermark, error) {
+	list, err := r.service.Watermarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Watermark, 0, len(list))
+	for _, item := range list {
+		res = append(res, modelWatermarkToGraphQLWatermark(item))
+	}
+
+	return res, nil
+}
+
+func (r *sizeResolver) URL(ctx context.Context, obj *model.Size, expiresIn *int) (string, error) {
+	ttl, err := r.urlExpiresIn(expiresIn)
+	if err != nil {
+		return "", err
+	}
+
+	return r.urls.Signed(obj.ImageID, obj.Variant, ttl)
+}
+
+func (r *subscriptionResolver) ImageProcessed(ctx context.Context, imageID string) (<-chan *model.Image, error) {
+	images := r.service.Subscribe(ctx, imageID)
+
+	res := make(chan *model.Image, 1)
+	go func() {
+		defer close(res)
+		for image := range images {
+			select {
+			case res <- modelImageToGraphQLImage(image):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+// ImageConnection returns generated.ImageConnectionResolver implementation.
+func (r *Resolver) ImageConnection() generated.ImageConnectionResolver {
+	return &imageConnectionResolver{r}
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Size returns generated.SizeResolver implementation.
+func (r *Resolver) Size() generated.SizeResolver { return &sizeResolver{r} }
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type imageConnectionResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type sizeResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+
+func (r *Resolver) sizeRequests(ctx context.Context, sizes []*model.SizeInput, presets []string) ([]servicemodel.SizeRequest, error) {
+	res := graphQLSizesToModelSizes(sizes)
+	if len(presets) == 0 {
+		return res, nil
+	}
+
+	resolved, err := r.service.ResolvePresets(ctx, presets)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(res, resolved...), nil
+}
 
 func graphQLSizesToModelSizes(sizes []*model.SizeInput) []servicemodel.SizeRequest {
 	res := make([]servicemodel.SizeRequest, 0, len(sizes))
@@ -72,15 +343,90 @@ func graphQLSizesToModelSizes(sizes []*model.SizeInput) []servicemodel.SizeReque
 		if size == nil {
 			continue
 		}
-		res = append(res, servicemodel.SizeRequest{
-			Width:  size.Width,
-			Height: size.Height,
-		})
+		res = append(res, graphQLSizeToModelSize(size))
+	}
+
+	return res
+}
+
+func graphQLSizeToModelSize(size *model.SizeInput) servicemodel.SizeRequest {
+	request := servicemodel.SizeRequest{
+		Width:  size.Width,
+		Height: size.Height,
+	}
+	if size.Mode != nil {
+		request.Mode = servicemodel.ResizeMode(strings.ToLower(size.Mode.String()))
+	}
+	if size.Anchor != nil {
+		request.Anchor = servicemodel.Anchor(strings.ReplaceAll(strings.ToLower(size.Anchor.String()), "_", "-"))
+	}
+	if size.Background != nil {
+		request.Background = *size.Background
+	}
+	if size.Format != nil {
+		request.Format = servicemodel.Format(strings.ToLower(size.Format.String()))
+	}
+	if size.Quality != nil {
+		request.Quality = *size.Quality
+	}
+	if size.Compression != nil {
+		request.Compression = servicemodel.Compression(strings.ReplaceAll(strings.ToLower(size.Compression.String()), "_", "-"))
+	}
+	if len(size.Operations) > 0 {
+		request.Operations = make([]servicemodel.Operation, len(size.Operations))
+		for i, operation := range size.Operations {
+			request.Operations[i].Name = servicemodel.OperationName(strings.ToLower(operation.Name.String()))
+			if operation.Value != nil {
+				request.Operations[i].Value = *operation.Value
+			}
+		}
+	}
+	if size.Watermark != nil {
+		request.Watermark = *size.Watermark
+	}
+	if size.Frame != nil {
+		request.Frame = *size.Frame
+	}
+
+	return request
+}
+
+func graphQLFilterToModelFilter(filter *model.ImageFilter) servicemodel.ImageFilter {
+	var res servicemodel.ImageFilter
+	if filter == nil {
+		return res
+	}
+	if filter.MimeType != nil {
+		res.MimeType = *filter.MimeType
+	}
+	if filter.UploadedAfter != nil {
+		res.UploadedAfter = *filter.UploadedAfter
+	}
+	if filter.UploadedBefore != nil {
+		res.UploadedBefore = *filter.UploadedBefore
+	}
+	if filter.ClientNamePrefix != nil {
+		res.ClientNamePrefix = *filter.ClientNamePrefix
 	}
 
 	return res
 }
 
+func graphQLSortToModelSort(sort *model.ImageSort) servicemodel.ImageSort {
+	var res servicemodel.ImageSort
+	if sort == nil {
+		return res
+	}
+	if sort.Field == model.ImageSortFieldSize {
+		res.Field = servicemodel.ImageSortSize
+	} else {
+		res.Field = servicemodel.ImageSortUploadAt
+	}
+	res.Desc = sort.Direction == model.SortDirectionDesc
+
+	return res
+}
+
 func modelImageToGraphQLImage(image *servicemodel.Image) *model.Image {
 	if image == nil {
 		return nil
@@ -88,20 +434,200 @@ func modelImageToGraphQLImage(image *servicemodel.Image) *model.Image {
 
 	sizes := make([]*model.Size, len(image.Sizes))
 	for i, size := range image.Sizes {
-		sizes[i] = &model.Size{
-			Path:   size.Path,
-			Width:  size.Width,
-			Height: size.Height,
-		}
+		sizes[i] = modelSizeToGraphQLSize(image.ID, size)
 	}
 
-	return &model.Image{
+	res := &model.Image{
 		ID:         image.ID,
 		Path:       image.Path,
 		ClientName: image.ClientName,
 		MimeType:   image.MimeType,
 		Size:       int(image.Size),
+		Hash:       optionalString(image.Hash),
 		UploadAt:   &image.UploadAt,
 		Sizes:      sizes,
+		JobID:      optionalString(image.JobID),
+	}
+	if image.Format != "" {
+		format := model.ImageFormat(strings.ToUpper(string(image.Format)))
+		res.Format = &format
+	}
+	res.Width = optionalInt(image.Width)
+	res.Height = optionalInt(image.Height)
+	res.Orientation = optionalInt(image.Orientation)
+	if image.ColorModel != "" {
+		colorModel := model.ColorModel(strings.ReplaceAll(strings.ToUpper(string(image.ColorModel)), "-", "_"))
+		res.ColorModel = &colorModel
+	}
+	res.HasAlpha = image.HasAlpha
+	res.Exif = modelExifToGraphQLExif(image.Exif)
+	if image.MetadataPolicy != "" {
+		policy := model.MetadataPolicy(strings.ReplaceAll(strings.ToUpper(string(image.MetadataPolicy)), "-", "_"))
+		res.MetadataPolicy = &policy
+	}
+	if image.FocalPoint != nil {
+		res.FocalPoint = &model.FocalPoint{X: image.FocalPoint.X, Y: image.FocalPoint.Y}
+	}
+
+	return res
+}
+
+func modelExifToGraphQLExif(exif *servicemodel.Exif) *model.Exif {
+	if exif == nil {
+		return nil
+	}
+
+	res := &model.Exif{
+		Make:             optionalString(exif.Make),
+		Model:            optionalString(exif.Model),
+		LensModel:        optionalString(exif.LensModel),
+		Software:         optionalString(exif.Software),
+		DateTimeOriginal: optionalString(exif.DateTimeOriginal),
+		ExposureTime:     optionalString(exif.ExposureTime),
+		Iso:              optionalInt(exif.ISO),
+		HasGps:           exif.HasGPS,
+	}
+	if exif.FNumber != 0 {
+		fNumber := exif.FNumber
+		res.FNumber = &fNumber
+	}
+	if exif.FocalLength != 0 {
+		focalLength := exif.FocalLength
+		res.FocalLength = &focalLength
+	}
+
+	return res
+}
+
+func modelJobToGraphQLJob(job *servicemodel.Job) *model.Job {
+	return &model.Job{
+		ID:        job.ID,
+		ImageID:   job.ImageID,
+		Status:    model.JobStatus(strings.ToUpper(string(job.Status))),
+		Error:     optionalString(job.Error),
+		Attempts:  job.Attempts,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func optionalInt(value int) *int {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
+}
+
+func modelSizeToGraphQLSize(imageID string, size servicemodel.Size) *model.Size {
+	mode := size.Mode
+	if mode == "" {
+		mode = servicemodel.ResizeModeStretch
+	}
+
+	res := &model.Size{
+		Path:   size.Path,
+		Width:  size.Width,
+		Height: size.Height,
+		Mode:   model.ResizeMode(strings.ToUpper(string(mode))),
+		Format: model.ImageFormat(strings.ToUpper(string(size.OutputFormat()))),
+		Status: model.SizeStatusReady,
+
+		ImageID: imageID,
+		Variant: size,
+	}
+	if size.Anchor != "" {
+		anchor := model.Anchor(strings.ReplaceAll(strings.ToUpper(string(size.Anchor)), "-", "_"))
+		res.Anchor = &anchor
+	}
+	if !size.Ready() {
+		res.Status = model.SizeStatus(strings.ToUpper(string(size.Status)))
+	}
+	res.Background = optionalString(size.Background)
+	if size.Quality != 0 {
+		quality := size.Quality
+		res.Quality = &quality
+	}
+	if size.Compression != "" {
+		compression := model.PNGCompression(strings.ReplaceAll(strings.ToUpper(string(size.Compression)), "-", "_"))
+		res.Compression = &compression
+	}
+	res.Preset = optionalString(size.Preset)
+	res.Operations = modelOperationsToGraphQLOperations(size.Operations)
+	res.Watermark = optionalString(size.Watermark)
+	res.Frame = optionalInt(size.Frame)
+
+	return res
+}
+
+func modelPresetToGraphQLPreset(preset *servicemodel.Preset) *model.Preset {
+	size := preset.Size
+	res := &model.Preset{
+		Name:       preset.Name,
+		Width:      size.Width,
+		Height:     size.Height,
+		Mode:       model.ResizeMode(strings.ToUpper(string(size.ResizeMode()))),
+		Background: optionalString(size.PadBackground()),
+		Version:    preset.Version,
+		UpdatedAt:  preset.UpdatedAt,
+	}
+	if anchor := size.CropAnchor(); anchor != "" {
+		anchor := model.Anchor(strings.ReplaceAll(strings.ToUpper(string(anchor)), "-", "_"))
+		res.Anchor = &anchor
+	}
+	if size.Format != "" {
+		format := model.ImageFormat(strings.ToUpper(string(size.Format)))
+		res.Format = &format
+	}
+	if size.Quality != 0 {
+		quality := size.Quality
+		res.Quality = &quality
+	}
+	if size.Compression != "" {
+		compression := model.PNGCompression(strings.ReplaceAll(strings.ToUpper(string(size.Compression)), "-", "_"))
+		res.Compression = &compression
+	}
+	res.Operations = modelOperationsToGraphQLOperations(size.Operations)
+	res.Watermark = optionalString(size.Watermark)
+	res.Frame = optionalInt(size.Frame)
+
+	return res
+}
+
+func modelOperationsToGraphQLOperations(operations []servicemodel.Operation) []*model.Operation {
+	res := make([]*model.Operation, len(operations))
+	for i, operation := range operations {
+		res[i] = &model.Operation{Name: model.OperationName(strings.ToUpper(string(operation.Name)))}
+		if operation.Value != 0 {
+			value := operation.Value
+			res[i].Value = &value
+		}
+	}
+
+	return res
+}
+
+func modelWatermarkToGraphQLWatermark(watermark *servicemodel.Watermark) *model.Watermark {
+	position := watermark.Position
+	if position == "" {
+		position = servicemodel.AnchorBottomRight
+	}
+
+	return &model.Watermark{
+		ID:        watermark.ID,
+		Position:  model.Anchor(strings.ReplaceAll(strings.ToUpper(string(position)), "-", "_")),
+		Opacity:   watermark.Opacity,
+		Scale:     watermark.Scale,
+		Tile:      watermark.Tile,
+		Version:   watermark.Version,
+		UpdatedAt: watermark.UpdatedAt,
 	}
 }
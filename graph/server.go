@@ -2,9 +2,14 @@ package graph
 
 import (
 	"errors"
+	"math"
 	"net/http"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	serviceerrors "github.com/portey/image-resizer/errors"
 	"github.com/portey/image-resizer/graph/generated"
@@ -20,7 +25,19 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// multipartOverhead is allowed above the upload limit for form fields, so slightly larger uploads are
+// rejected by the service with a typed error instead of a failed form parsing.
+const multipartOverhead = 1 << 20
+
 type (
+	UploadConfig struct {
+		// MaxBytes limits bytes of an upload, zero keeps the gqlgen default.
+		MaxBytes int64
+		// MaxMemory limits bytes of a request parsed in memory, the rest is stored in temp files,
+		// zero keeps the whole request in memory like the spools of the service.
+		MaxMemory int64
+	}
+
 	Server struct {
 		http      *http.Server
 		runErr    error
@@ -28,10 +45,10 @@ type (
 	}
 )
 
-func New(port int, resolver generated.ResolverRoot) *Server {
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+func New(port int, resolver generated.ResolverRoot, images ImageSource, imagesCfg ImagesConfig, uploadCfg UploadConfig) *Server {
+	srv := newHandler(generated.NewExecutableSchema(generated.Config{
 		Resolvers: resolver,
-	}))
+	}), uploadCfg)
 	srv.SetErrorPresenter(func(ctx context.Context, err error) *gqlerror.Error {
 		switch err.(type) {
 		case serviceerrors.ServiceError:
@@ -65,6 +82,10 @@ func New(port int, resolver generated.ResolverRoot) *Server {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", playground.Handler("GraphQL playground", "/query"))
 	mux.HandleFunc("/query", srv.ServeHTTP)
+	mux.Handle(imagesPrefix, &imageHandler{
+		images: images,
+		config: imagesCfg,
+	})
 
 	return &Server{
 		http: &http.Server{
@@ -88,7 +109,8 @@ func (s *Server) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	go func() {
 		<-ctx.Done()
-		sdCtx, _ := context.WithTimeout(context.Background(), 5*time.Second) // nolint
+		sdCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 		err := s.http.Shutdown(sdCtx)
 		if err != nil {
 			log.Info("graphql service shutdown (", err, ")")
@@ -107,3 +129,33 @@ func (s *Server) HealthCheck() error {
 	}
 	return nil
 }
+
+func newHandler(es graphql.ExecutableSchema, uploadCfg UploadConfig) *handler.Server {
+	multipart := transport.MultipartForm{}
+	if uploadCfg.MaxBytes > 0 {
+		multipart.MaxUploadSize = uploadCfg.MaxBytes + multipartOverhead
+	}
+	multipart.MaxMemory = uploadCfg.MaxMemory
+	if uploadCfg.MaxMemory <= 0 {
+		// gqlgen falls back to its default for zero
+		multipart.MaxMemory = math.MaxInt64
+	}
+
+	srv := handler.New(es)
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(multipart)
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New(100),
+	})
+
+	return srv
+}
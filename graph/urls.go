@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	servicemodel "github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/signature"
+)
+
+// ImageURLs builds signed URLs of variants served by the image handler.
+type ImageURLs struct {
+	baseURL string
+	signer  *signature.Signer
+}
+
+func NewImageURLs(baseURL string, signer *signature.Signer) *ImageURLs {
+	return &ImageURLs{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		signer:  signer,
+	}
+}
+
+func (u *ImageURLs) Signed(imageID string, size servicemodel.Size, expiresIn time.Duration) (string, error) {
+	path := fmt.Sprintf("%s%s/%dx%d.%s", imagesPrefix, imageID, size.Width, size.Height, size.OutputFormat().Extension())
+
+	query := url.Values{}
+	if size.Mode != "" {
+		query.Set("mode", string(size.Mode))
+	}
+	if size.Anchor != "" {
+		query.Set("anchor", string(size.Anchor))
+	}
+	if size.Background != "" {
+		query.Set("background", strings.TrimPrefix(size.Background, "#"))
+	}
+	if size.Quality != 0 {
+		query.Set("quality", strconv.Itoa(size.Quality))
+	}
+	if size.Compression != "" {
+		query.Set("compression", string(size.Compression))
+	}
+	if len(size.Operations) > 0 {
+		query.Set("ops", servicemodel.FormatOperations(size.Operations))
+	}
+	if size.Watermark != "" {
+		query.Set("watermark", size.Watermark)
+	}
+	if size.Frame != 0 {
+		query.Set("frame", strconv.Itoa(size.Frame))
+	}
+
+	signed, err := u.signer.Sign(path, query, time.Now().Add(expiresIn))
+	if err != nil {
+		return "", err
+	}
+
+	return u.baseURL + (&url.URL{Path: path, RawQuery: signed.Encode()}).String(), nil
+}
@@ -56,7 +56,8 @@ func (s *Server) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	go func() {
 		<-ctx.Done()
-		sdCtx, _ := context.WithTimeout(context.Background(), 5*time.Second) // nolint
+		sdCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 		err := s.http.Shutdown(sdCtx)
 		if err != nil {
 			log.Info("health check service shutdown (", err, ")")
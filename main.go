@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"strings"
@@ -12,9 +13,14 @@ import (
 	"github.com/portey/image-resizer/graph/resolver"
 	"github.com/portey/image-resizer/healthcheck"
 	"github.com/portey/image-resizer/opts"
+	"github.com/portey/image-resizer/repository/bolt"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
 	"github.com/portey/image-resizer/repository/mongo"
 	"github.com/portey/image-resizer/resizer"
 	"github.com/portey/image-resizer/service"
+	"github.com/portey/image-resizer/signature"
+	"github.com/portey/image-resizer/storage/filesystem"
+	storagememory "github.com/portey/image-resizer/storage/memory"
 	"github.com/portey/image-resizer/storage/minio"
 	log "github.com/sirupsen/logrus"
 )
@@ -23,23 +29,51 @@ func main() {
 	config := opts.ReadOS()
 	initLogger(config.LogLevel, config.PrettyLogOutput)
 
+	// a mistyped policy would keep metadata which is meant to be stripped
+	if !config.MetadataPolicy.Valid() {
+		log.Fatalf("unknown metadata policy %q", config.MetadataPolicy)
+	}
+	if !config.MetadataMethod.Valid() {
+		log.Fatalf("unknown metadata method %q", config.MetadataMethod)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	setupGracefulShutdown(cancel)
 
-	storage, err := minio.New(config.StorageCfg)
+	storage, err := newStorage(config)
 	if err != nil {
 		log.Fatalf("storage initialization %v", err)
 	}
 
-	repo, err := mongo.New(ctx, config.MongoURI, config.MongoDatabase)
+	repo, err := newRepository(ctx, config)
 	if err != nil {
 		log.Fatalf("repository initialization %v", err)
 	}
 
-	srv := service.New(storage, resizer.New(), repo)
+	srv := service.New(storage, resizer.New(config.MaxInputPixels), repo, repo, repo, repo, config.ResizeWorkers, service.Limits{
+		MaxUploadBytes:     config.MaxUploadBytes,
+		MaxOutputDimension: config.MaxOutputDimension,
+		ResizeTimeout:      config.ResizeTimeout,
+		MaxMemoryBytes:     config.MaxMemoryBytes,
+		CleanupGracePeriod: config.CleanupGracePeriod,
+	}, service.MetadataConfig{
+		Policy: config.MetadataPolicy,
+		Method: config.MetadataMethod,
+	})
 
-	graphqlResolver := resolver.New(srv)
-	graphqlSrv := graph.New(config.GraphQLPort, graphqlResolver)
+	if len(config.ImageSigningKeys) == 0 {
+		log.Warn("no image signing keys configured, image urls can't be signed")
+	}
+	signer := signature.New(config.ImageSigningKeys)
+
+	graphqlResolver := resolver.New(srv, graph.NewImageURLs(config.ImageBaseURL, signer), config.ImageURLMaxExpiresIn)
+	graphqlSrv := graph.New(config.GraphQLPort, graphqlResolver, srv, graph.ImagesConfig{
+		CacheMaxAge: config.ImageCacheMaxAge,
+		Signer:      signer,
+	}, graph.UploadConfig{
+		MaxBytes:  config.MaxUploadBytes,
+		MaxMemory: config.MaxMemoryBytes,
+	})
 
 	healthCheckSrv := healthcheck.New(config.HealthCHeckPort, []healthcheck.Check{
 		repo.Ping,
@@ -47,11 +81,46 @@ func main() {
 	})
 
 	var wg sync.WaitGroup
+	srv.RunJobs(ctx, &wg, config.JobWorkers)
 	graphqlSrv.Run(ctx, &wg)
 	healthCheckSrv.Run(ctx, &wg)
 	wg.Wait()
 }
 
+type repository interface {
+	service.Repository
+	service.JobRepository
+	service.PresetRepository
+	service.WatermarkRepository
+	Ping() error
+}
+
+func newRepository(ctx context.Context, config opts.Config) (repository, error) {
+	switch config.Backend {
+	case opts.BackendMongo:
+		return mongo.New(ctx, config.MongoURI, config.MongoDatabase)
+	case opts.BackendBolt:
+		return bolt.New(config.BoltPath)
+	case opts.BackendMemory:
+		return repositorymemory.New(), nil
+	}
+
+	return nil, fmt.Errorf("unknown backend %q", config.Backend)
+}
+
+func newStorage(config opts.Config) (service.Storage, error) {
+	switch config.StorageBackend {
+	case opts.StorageBackendMinio:
+		return minio.New(config.StorageCfg)
+	case opts.StorageBackendFilesystem:
+		return filesystem.New(config.FilesystemCfg)
+	case opts.StorageBackendMemory:
+		return storagememory.New(), nil
+	}
+
+	return nil, fmt.Errorf("unknown storage backend %q", config.StorageBackend)
+}
+
 func setupGracefulShutdown(stop func()) {
 	signalChannel := make(chan os.Signal, 1)
 	signal.Notify(signalChannel, os.Interrupt, syscall.SIGTERM)
@@ -0,0 +1,64 @@
+package model
+
+const (
+	FlipAxisHorizontal FlipAxis = "horizontal"
+	FlipAxisVertical   FlipAxis = "vertical"
+)
+
+type (
+	FlipAxis string
+
+	// Edit has exactly one of the changes set.
+	Edit struct {
+		// clockwise
+		Rotate int      `validate:"omitempty,oneof=90 180 270"`
+		Flip   FlipAxis `validate:"omitempty,oneof=horizontal vertical"`
+		Crop   *Rect
+	}
+
+	// Rect is in pixels of the original as displayed.
+	Rect struct {
+		X      int `validate:"min=0"`
+		Y      int `validate:"min=0"`
+		Width  int `validate:"required,min=1"`
+		Height int `validate:"required,min=1"`
+	}
+)
+
+func (r Rect) Within(width, height int) bool {
+	return r.X+r.Width <= width && r.Y+r.Height <= height
+}
+
+// FocalPoint moves the point with the content, it's nil if the point is cropped out.
+func (e Edit) FocalPoint(point *FocalPoint, width, height int) *FocalPoint {
+	if point == nil {
+		return nil
+	}
+
+	res := *point
+	switch e.Rotate {
+	case 90:
+		res = FocalPoint{X: 1 - point.Y, Y: point.X}
+	case 180:
+		res = FocalPoint{X: 1 - point.X, Y: 1 - point.Y}
+	case 270:
+		res = FocalPoint{X: point.Y, Y: 1 - point.X}
+	}
+
+	switch e.Flip {
+	case FlipAxisHorizontal:
+		res.X = 1 - res.X
+	case FlipAxisVertical:
+		res.Y = 1 - res.Y
+	}
+
+	if crop := e.Crop; crop != nil {
+		res.X = (res.X*float64(width) - float64(crop.X)) / float64(crop.Width)
+		res.Y = (res.Y*float64(height) - float64(crop.Y)) / float64(crop.Height)
+		if res.X < 0 || res.X > 1 || res.Y < 0 || res.Y > 1 {
+			return nil
+		}
+	}
+
+	return &res
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdit_FocalPoint(t *testing.T) {
+	point := &FocalPoint{X: 0.25, Y: 0.1}
+	f := func(edit Edit, expected *FocalPoint) {
+		assert.Equal(t, expected, edit.FocalPoint(point, 400, 200), edit)
+	}
+
+	f(Edit{Rotate: 90}, &FocalPoint{X: 0.9, Y: 0.25})
+	f(Edit{Rotate: 180}, &FocalPoint{X: 0.75, Y: 0.9})
+	f(Edit{Rotate: 270}, &FocalPoint{X: 0.1, Y: 0.75})
+	f(Edit{Flip: FlipAxisHorizontal}, &FocalPoint{X: 0.75, Y: 0.1})
+	f(Edit{Flip: FlipAxisVertical}, &FocalPoint{X: 0.25, Y: 0.9})
+	f(Edit{Crop: &Rect{X: 50, Y: 0, Width: 200, Height: 100}}, &FocalPoint{X: 0.25, Y: 0.2})
+	// the point is cropped out
+	f(Edit{Crop: &Rect{X: 200, Y: 0, Width: 200, Height: 200}}, nil)
+
+	assert.Nil(t, Edit{Rotate: 90}.FocalPoint(nil, 400, 200))
+}
+
+func TestImage_ReplaceOriginal(t *testing.T) {
+	i := Image{Path: "original", Hash: "hash", Shared: true, Size: 100}
+	i.AddSize("small", 10, SizeRequest{Width: 100, Height: 100})
+	i.AddPendingSize(SizeRequest{Width: 200, Height: 200})
+
+	i.ReplaceOriginal("derived", 50, "derived-hash", Metadata{Format: FormatPNG, Width: 10, Height: 20})
+	requests := i.RemoveReadySizes()
+
+	assert.Equal(t, "derived", i.Path)
+	assert.Equal(t, int64(50), i.Size)
+	assert.Equal(t, "derived-hash", i.Hash)
+	assert.Equal(t, 20, i.Height)
+	assert.False(t, i.Shared)
+	assert.Equal(t, []string{"original", "small"}, i.Trash)
+	// pending sizes are rendered from the derived original later
+	assert.Len(t, i.Sizes, 1)
+	assert.Equal(t, SizeStatusPending, i.Sizes[0].Status)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, 100, requests[0].Width)
+}
@@ -2,9 +2,92 @@ package model
 
 import (
 	"io"
+	"strings"
 	"time"
 )
 
+const (
+	ResizeModeStretch ResizeMode = "stretch"
+	ResizeModeFit     ResizeMode = "fit"
+	ResizeModeFill    ResizeMode = "fill"
+	ResizeModePad     ResizeMode = "pad"
+	ResizeModeLimit   ResizeMode = "limit"
+)
+
+const (
+	AnchorCenter      Anchor = "center"
+	AnchorTopLeft     Anchor = "top-left"
+	AnchorTop         Anchor = "top"
+	AnchorTopRight    Anchor = "top-right"
+	AnchorLeft        Anchor = "left"
+	AnchorRight       Anchor = "right"
+	AnchorBottomLeft  Anchor = "bottom-left"
+	AnchorBottom      Anchor = "bottom"
+	AnchorBottomRight Anchor = "bottom-right"
+	// AnchorSmart crops around the focal point of the image or the most detailed part of the image if it isn't set.
+	AnchorSmart Anchor = "smart"
+)
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatBMP  Format = "bmp"
+	FormatTIFF Format = "tiff"
+)
+
+const (
+	CompressionDefault   Compression = "default"
+	CompressionNone      Compression = "none"
+	CompressionBestSpeed Compression = "best-speed"
+	CompressionBest      Compression = "best-compression"
+)
+
+const (
+	SizeStatusReady   SizeStatus = "ready"
+	SizeStatusPending SizeStatus = "pending"
+	SizeStatusFailed  SizeStatus = "failed"
+)
+
+const (
+	DefaultBackground  = "#ffffff"
+	DefaultJPEGQuality = 95
+)
+
+type (
+	ResizeMode  string
+	Anchor      string
+	Format      string
+	Compression string
+	SizeStatus  string
+)
+
+var formatMimeTypes = map[Format]string{
+	FormatJPEG: "image/jpeg",
+	FormatPNG:  "image/png",
+	FormatGIF:  "image/gif",
+	FormatBMP:  "image/bmp",
+	FormatTIFF: "image/tiff",
+}
+
+func FormatFromMimeType(mimeType string) Format {
+	for format, formatMimeType := range formatMimeTypes {
+		if formatMimeType == mimeType {
+			return format
+		}
+	}
+
+	return ""
+}
+
+func (f Format) MimeType() string {
+	return formatMimeTypes[f]
+}
+
+func (f Format) Extension() string {
+	return string(f)
+}
+
 type Image struct {
 	ID         string    `json:"id" bson:"_id"`
 	Path       string    `json:"path" bson:"path"`
@@ -14,11 +97,76 @@ type Image struct {
 	UploadAt   time.Time `json:"uploadAt" bson:"uploadAt"`
 	Sizes      []Size    `json:"sizes" bson:"sizes"`
 	Version    int       `json:"version" bson:"version"`
+	Hash       string    `json:"hash,omitempty" bson:"hash,omitempty"`
+	Metadata   `bson:",inline"`
+	// empty for images uploaded before policies were introduced
+	MetadataPolicy MetadataPolicy `json:"metadataPolicy,omitempty" bson:"metadataPolicy,omitempty"`
+	FocalPoint     *FocalPoint    `json:"focalPoint,omitempty" bson:"focalPoint,omitempty"`
+	// at most one image is shared per hash
+	Shared bool   `json:"shared,omitempty" bson:"shared"`
+	JobID  string `json:"jobId,omitempty" bson:"jobId,omitempty"`
+	// hidden until their stored objects are removed
+	Deleted bool     `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	Trash   []string `json:"trash,omitempty" bson:"trash,omitempty"`
 }
 
-func (i *Image) HasResizedSize(width int, height int) bool {
+// FocalPoint coordinates are relative to the dimensions of the original as displayed, from 0 to 1.
+type FocalPoint struct {
+	X float64 `json:"x" bson:"x" validate:"min=0,max=1"`
+	Y float64 `json:"y" bson:"y" validate:"min=0,max=1"`
+}
+
+func (i *Image) HasResizedSize(request SizeRequest) bool {
+	_, ok := i.ResizedSize(request)
+	return ok
+}
+
+func (i *Image) ResizedSize(request SizeRequest) (Size, bool) {
 	for _, size := range i.Sizes {
-		if size.Height == height && size.Width == width {
+		if size.Matches(request) && size.Ready() {
+			return size, true
+		}
+	}
+
+	return Size{}, false
+}
+
+func (i *Image) AddSize(path string, bytes int64, request SizeRequest) {
+	size := newSize(request)
+	size.Path = path
+	size.Bytes = bytes
+	size.Status = SizeStatusReady
+
+	i.setSize(size, request)
+}
+
+func (i *Image) AddPendingSize(request SizeRequest) {
+	if i.HasResizedSize(request) {
+		return
+	}
+
+	size := newSize(request)
+	size.Status = SizeStatusPending
+
+	i.setSize(size, request)
+}
+
+func (i *Image) FailPendingSizes(requests []SizeRequest) {
+	for n, size := range i.Sizes {
+		if size.Status != SizeStatusPending {
+			continue
+		}
+		for _, request := range requests {
+			if size.Matches(request) {
+				i.Sizes[n].Status = SizeStatusFailed
+			}
+		}
+	}
+}
+
+func (i *Image) HasPendingSize(width, height int) bool {
+	for _, size := range i.Sizes {
+		if size.Width == width && size.Height == height && size.Status == SizeStatusPending {
 			return true
 		}
 	}
@@ -26,28 +174,336 @@ func (i *Image) HasResizedSize(width int, height int) bool {
 	return false
 }
 
-func (i *Image) AddSize(path string, width int, height int) {
-	i.Sizes = append(i.Sizes, Size{
-		Path:   path,
-		Width:  width,
-		Height: height,
+func (i *Image) RemoveSizes(width, height int) []Size {
+	var removed []Size
+	kept := make([]Size, 0, len(i.Sizes))
+	for _, size := range i.Sizes {
+		if size.Width != width || size.Height != height {
+			kept = append(kept, size)
+			continue
+		}
+
+		removed = append(removed, size)
+		i.trash(size.Path)
+	}
+	i.Sizes = kept
+
+	return removed
+}
+
+func (i *Image) LabelPresetSize(request SizeRequest) {
+	for n, size := range i.Sizes {
+		if size.Matches(request) && size.Ready() {
+			if size.Preset == "" {
+				i.Sizes[n].Preset = request.Preset
+			}
+			return
+		}
+	}
+}
+
+func (i *Image) ReplacePresetSizes(request SizeRequest) {
+	kept := make([]Size, 0, len(i.Sizes))
+	for _, size := range i.Sizes {
+		switch {
+		case size.Matches(request) && size.Ready():
+			size.Preset = request.Preset
+		case size.Preset == request.Preset:
+			i.trash(size.Path)
+			continue
+		}
+		kept = append(kept, size)
+	}
+	i.Sizes = kept
+}
+
+// RemoveSmartSizes keeps pending sizes, they are rendered with the focal point current at that moment.
+func (i *Image) RemoveSmartSizes() []SizeRequest {
+	return i.removeReadySizes(func(size Size) bool {
+		return size.Anchor == AnchorSmart
 	})
 }
 
+func (i *Image) RemoveReadySizes() []SizeRequest {
+	return i.removeReadySizes(func(Size) bool {
+		return true
+	})
+}
+
+func (i *Image) removeReadySizes(matches func(Size) bool) []SizeRequest {
+	var requests []SizeRequest
+	kept := make([]Size, 0, len(i.Sizes))
+	for _, size := range i.Sizes {
+		if !size.Ready() || !matches(size) {
+			kept = append(kept, size)
+			continue
+		}
+
+		requests = append(requests, size.Request())
+		i.trash(size.Path)
+	}
+	i.Sizes = kept
+
+	return requests
+}
+
+// ReplaceOriginal doesn't share the derived original, so deduplicated uploads of the previous content
+// don't return the image.
+func (i *Image) ReplaceOriginal(path string, size int64, hash string, metadata Metadata) {
+	i.trash(i.Path)
+	i.Path = path
+	i.Size = size
+	i.Hash = hash
+	i.Metadata = metadata
+	i.Shared = false
+}
+
+func (i *Image) MarkDeleted() {
+	i.Deleted = true
+	// the content can be uploaded again
+	i.Shared = false
+	i.trash(i.Path)
+	for _, size := range i.Sizes {
+		i.trash(size.Path)
+	}
+	i.Sizes = []Size{}
+}
+
+func (i *Image) EmptyTrash(removed []string) {
+	kept := make([]string, 0, len(i.Trash))
+	for _, path := range i.Trash {
+		if !contains(removed, path) {
+			kept = append(kept, path)
+		}
+	}
+	i.Trash = kept
+}
+
+func (i *Image) trash(path string) {
+	if path != "" && !contains(i.Trash, path) {
+		i.Trash = append(i.Trash, path)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *Image) setSize(size Size, request SizeRequest) {
+	for n, existing := range i.Sizes {
+		if existing.Matches(request) && !existing.Ready() {
+			i.Sizes[n] = size
+			return
+		}
+	}
+
+	i.Sizes = append(i.Sizes, size)
+}
+
+func newSize(request SizeRequest) Size {
+	return Size{
+		Width:       request.Width,
+		Height:      request.Height,
+		Mode:        request.ResizeMode(),
+		Anchor:      request.CropAnchor(),
+		Background:  request.PadBackground(),
+		Format:      request.OutputFormat(),
+		Quality:     request.JPEGQuality(),
+		Compression: request.PNGCompression(),
+		Operations:  normalizeOperations(request.Operations),
+		Watermark:   request.Watermark,
+		Frame:       request.PosterFrame(),
+		Preset:      request.Preset,
+	}
+}
+
 type Size struct {
-	Path   string `json:"path" bson:"path"`
-	Width  int    `json:"width" bson:"width"`
-	Height int    `json:"height" bson:"height"`
+	Path        string      `json:"path" bson:"path"`
+	Width       int         `json:"width" bson:"width"`
+	Height      int         `json:"height" bson:"height"`
+	Bytes       int64       `json:"bytes,omitempty" bson:"bytes,omitempty"`
+	Mode        ResizeMode  `json:"mode" bson:"mode"`
+	Anchor      Anchor      `json:"anchor,omitempty" bson:"anchor,omitempty"`
+	Background  string      `json:"background,omitempty" bson:"background,omitempty"`
+	Format      Format      `json:"format" bson:"format"`
+	Quality     int         `json:"quality,omitempty" bson:"quality,omitempty"`
+	Compression Compression `json:"compression,omitempty" bson:"compression,omitempty"`
+	Status      SizeStatus  `json:"status,omitempty" bson:"status,omitempty"`
+	Operations  []Operation `json:"operations,omitempty" bson:"operations,omitempty"`
+	Watermark   string      `json:"watermark,omitempty" bson:"watermark,omitempty"`
+	Frame       int         `json:"frame,omitempty" bson:"frame,omitempty"`
+	Preset      string      `json:"preset,omitempty" bson:"preset,omitempty"`
+}
+
+// Ready treats sizes stored before statuses were introduced as ready.
+func (s Size) Ready() bool {
+	return s.Status == "" || s.Status == SizeStatusReady
+}
+
+// Matches treats sizes stored before resize modes and formats were introduced as stretched PNGs.
+func (s Size) Matches(request SizeRequest) bool {
+	mode := s.Mode
+	if mode == "" {
+		mode = ResizeModeStretch
+	}
+	compression := s.Compression
+	if s.Format == "" {
+		compression = CompressionDefault
+	}
+
+	return s.Width == request.Width &&
+		s.Height == request.Height &&
+		mode == request.ResizeMode() &&
+		s.Anchor == request.CropAnchor() &&
+		s.Background == request.PadBackground() &&
+		s.OutputFormat() == request.OutputFormat() &&
+		s.Quality == request.JPEGQuality() &&
+		compression == request.PNGCompression() &&
+		operationsEqual(s.Operations, normalizeOperations(request.Operations)) &&
+		s.Watermark == request.Watermark &&
+		s.Frame == request.PosterFrame()
+}
+
+func (s Size) Request() SizeRequest {
+	return SizeRequest{
+		Width:       s.Width,
+		Height:      s.Height,
+		Mode:        s.Mode,
+		Anchor:      s.Anchor,
+		Background:  s.Background,
+		Format:      s.OutputFormat(),
+		Quality:     s.Quality,
+		Compression: s.Compression,
+		Operations:  s.Operations,
+		Watermark:   s.Watermark,
+		Frame:       s.Frame,
+		Preset:      s.Preset,
+	}
+}
+
+// OutputFormat is PNG for sizes stored before formats were introduced.
+func (s Size) OutputFormat() Format {
+	if s.Format == "" {
+		return FormatPNG
+	}
+
+	return s.Format
 }
 
 type ImageUpload struct {
-	Content  io.Reader `validate:"required"`
-	Filename string    `validate:"required,min=5"`
-	Size     int64     `validate:"required,min=1000"`
-	MimeType string    `validate:"required,min=5,eq=image/jpeg|eq=image/png"`
+	Content        io.Reader      `validate:"required"`
+	Filename       string         `validate:"required,min=5"`
+	Size           int64          `validate:"required,min=1000"`
+	MimeType       string         `validate:"required,min=5,eq=image/jpeg|eq=image/png|eq=image/gif"`
+	MetadataPolicy MetadataPolicy `validate:"omitempty,oneof=keep strip-gps strip-all"`
+	Dedupe         bool
 }
 
 type SizeRequest struct {
-	Width  int `validate:"required,min=10"`
-	Height int `validate:"required,min=10"`
+	Width       int         `validate:"required,min=10"`
+	Height      int         `validate:"required,min=10"`
+	Mode        ResizeMode  `validate:"omitempty,oneof=stretch fit fill pad limit"`
+	Anchor      Anchor      `validate:"omitempty,oneof=center top-left top top-right left right bottom-left bottom bottom-right smart"`
+	Background  string      `validate:"omitempty,hexcolor"`
+	Format      Format      `validate:"omitempty,oneof=jpeg png gif bmp tiff"`
+	Quality     int         `validate:"omitempty,min=1,max=100"`
+	Compression Compression `validate:"omitempty,oneof=default none best-speed best-compression"`
+	// the resize is applied first unless it's placed
+	Operations []Operation `validate:"max=10,operations"`
+	Watermark  string      `validate:"omitempty,max=64,identifier"`
+	// frames past the last one render the last frame
+	Frame int `validate:"min=0"`
+	// isn't a part of the size identity
+	Preset string
+	// set from the image when the size is rendered
+	FocalPoint *FocalPoint `json:"-" bson:"-"`
+	// set when the size is rendered
+	Overlay *Overlay `json:"-" bson:"-"`
+}
+
+func (r SizeRequest) WithSourceFormat(mimeType string) SizeRequest {
+	if r.Format == "" {
+		r.Format = FormatFromMimeType(mimeType)
+	}
+
+	return r
+}
+
+func (r SizeRequest) OutputFormat() Format {
+	if _, ok := formatMimeTypes[r.Format]; !ok {
+		return FormatPNG
+	}
+
+	return r.Format
+}
+
+func (r SizeRequest) JPEGQuality() int {
+	if r.OutputFormat() != FormatJPEG {
+		return 0
+	}
+	if r.Quality == 0 {
+		return DefaultJPEGQuality
+	}
+
+	return r.Quality
+}
+
+func (r SizeRequest) PNGCompression() Compression {
+	if r.OutputFormat() != FormatPNG {
+		return ""
+	}
+	if r.Compression == "" {
+		return CompressionDefault
+	}
+
+	return r.Compression
+}
+
+// PosterFrame is zero for GIF output which keeps all frames.
+func (r SizeRequest) PosterFrame() int {
+	if r.OutputFormat() == FormatGIF {
+		return 0
+	}
+
+	return r.Frame
+}
+
+func (r SizeRequest) Pipeline() []Operation {
+	return pipeline(r.Operations)
+}
+
+func (r SizeRequest) ResizeMode() ResizeMode {
+	if r.Mode == "" {
+		return ResizeModeStretch
+	}
+
+	return r.Mode
+}
+
+func (r SizeRequest) CropAnchor() Anchor {
+	if r.ResizeMode() != ResizeModeFill {
+		return ""
+	}
+	if r.Anchor == "" {
+		return AnchorCenter
+	}
+
+	return r.Anchor
+}
+
+func (r SizeRequest) PadBackground() string {
+	if r.ResizeMode() != ResizeModePad {
+		return ""
+	}
+	if r.Background == "" {
+		return DefaultBackground
+	}
+
+	return strings.ToLower(r.Background)
 }
@@ -8,20 +8,161 @@ import (
 
 func TestImage_AddSize(t *testing.T) {
 	i := Image{}
-	i.AddSize("test", 1, 2)
+	i.AddSize("test", 123, SizeRequest{Width: 1, Height: 2})
 	assert.Len(t, i.Sizes, 1)
 	assert.Equal(t, "test", i.Sizes[0].Path)
+	assert.Equal(t, int64(123), i.Sizes[0].Bytes)
 	assert.Equal(t, 1, i.Sizes[0].Width)
 	assert.Equal(t, 2, i.Sizes[0].Height)
+	assert.Equal(t, ResizeModeStretch, i.Sizes[0].Mode)
 
-	i.AddSize("test2", 1, 2)
+	i.AddSize("test2", 0, SizeRequest{Width: 1, Height: 2, Mode: ResizeModeFill})
 	assert.Len(t, i.Sizes, 2)
+	assert.Equal(t, ResizeModeFill, i.Sizes[1].Mode)
+	assert.Equal(t, AnchorCenter, i.Sizes[1].Anchor)
 }
 
 func TestImage_HasResizedSize(t *testing.T) {
 	i := Image{}
-	i.AddSize("test", 1, 2)
+	i.AddSize("test", 0, SizeRequest{Width: 1, Height: 2})
 
-	assert.True(t, i.HasResizedSize(1, 2))
-	assert.False(t, i.HasResizedSize(1, 1))
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2}))
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Mode: ResizeModeStretch}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 1}))
+
+	i.AddSize("test2", 0, SizeRequest{Width: 100, Height: 100, Mode: ResizeModeFill})
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100, Mode: ResizeModeFill, Anchor: AnchorCenter}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100, Mode: ResizeModeFit}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100, Mode: ResizeModeFill, Anchor: AnchorTop}))
+
+	// sizes stored before resize modes existed
+	i.Sizes = append(i.Sizes, Size{Path: "legacy", Width: 50, Height: 50})
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 50, Height: 50}))
+}
+
+func TestSizeRequest_WithSourceFormat(t *testing.T) {
+	r := SizeRequest{Width: 1, Height: 2}.WithSourceFormat("image/jpeg")
+	assert.Equal(t, FormatJPEG, r.OutputFormat())
+	assert.Equal(t, DefaultJPEGQuality, r.JPEGQuality())
+	assert.Empty(t, r.PNGCompression())
+
+	r = SizeRequest{Width: 1, Height: 2, Format: FormatPNG, Quality: 50}.WithSourceFormat("image/jpeg")
+	assert.Equal(t, FormatPNG, r.OutputFormat())
+	assert.Equal(t, 0, r.JPEGQuality())
+	assert.Equal(t, CompressionDefault, r.PNGCompression())
+
+	i := Image{}
+	i.AddSize("test", 0, SizeRequest{Width: 1, Height: 2, Format: FormatJPEG, Quality: 80})
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Format: FormatJPEG, Quality: 80}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Format: FormatJPEG}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Format: FormatPNG}))
+}
+
+func TestSizeRequest_PosterFrame(t *testing.T) {
+	assert.Equal(t, 3, SizeRequest{Format: FormatJPEG, Frame: 3}.PosterFrame())
+	assert.Equal(t, 0, SizeRequest{Format: FormatGIF, Frame: 3}.PosterFrame())
+
+	i := Image{}
+	i.AddSize("test", 0, SizeRequest{Width: 1, Height: 2, Format: FormatPNG, Frame: 3})
+	i.AddSize("test2", 0, SizeRequest{Width: 1, Height: 2, Format: FormatGIF, Frame: 3})
+	assert.Equal(t, 3, i.Sizes[0].Frame)
+	assert.Equal(t, 0, i.Sizes[1].Frame)
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Format: FormatPNG, Frame: 3}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Format: FormatPNG}))
+	// animated sizes keep all frames, so the frame doesn't matter
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2, Format: FormatGIF}))
+}
+
+func TestImage_PendingSizes(t *testing.T) {
+	i := Image{}
+	i.AddPendingSize(SizeRequest{Width: 1, Height: 2})
+	i.AddPendingSize(SizeRequest{Width: 3, Height: 4})
+	assert.Len(t, i.Sizes, 2)
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2}))
+	assert.True(t, i.HasPendingSize(1, 2))
+
+	// resized size replaces the placeholder
+	i.AddSize("test", 10, SizeRequest{Width: 1, Height: 2})
+	assert.Len(t, i.Sizes, 2)
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 1, Height: 2}))
+	assert.False(t, i.HasPendingSize(1, 2))
+	assert.Equal(t, SizeStatusReady, i.Sizes[0].Status)
+
+	i.FailPendingSizes([]SizeRequest{{Width: 1, Height: 2}, {Width: 3, Height: 4}})
+	assert.Equal(t, SizeStatusReady, i.Sizes[0].Status)
+	assert.Equal(t, SizeStatusFailed, i.Sizes[1].Status)
+}
+
+func TestImage_Delete(t *testing.T) {
+	i := Image{Path: "original"}
+	i.AddSize("small", 10, SizeRequest{Width: 10, Height: 10})
+	i.AddSize("small-fit", 10, SizeRequest{Width: 10, Height: 10, Mode: ResizeModeFit})
+	i.AddSize("big", 10, SizeRequest{Width: 100, Height: 100})
+
+	removed := i.RemoveSizes(10, 10)
+	assert.Len(t, removed, 2)
+	assert.Len(t, i.Sizes, 1)
+	assert.Equal(t, []string{"small", "small-fit"}, i.Trash)
+
+	assert.Empty(t, i.RemoveSizes(20, 20))
+
+	i.MarkDeleted()
+	assert.True(t, i.Deleted)
+	assert.Empty(t, i.Sizes)
+	assert.Equal(t, []string{"small", "small-fit", "original", "big"}, i.Trash)
+
+	i.EmptyTrash([]string{"small", "big"})
+	assert.Equal(t, []string{"small-fit", "original"}, i.Trash)
+}
+
+func TestImage_ReplacePresetSizes(t *testing.T) {
+	thumb := SizeRequest{Width: 100, Height: 100, Preset: "thumb"}
+	i := Image{}
+	i.AddSize("old", 10, thumb)
+	i.AddSize("other", 10, SizeRequest{Width: 50, Height: 50})
+	assert.Equal(t, "thumb", i.Sizes[0].Preset)
+
+	// the preset is changed and the new size is resized
+	thumb.Width = 200
+	i.AddSize("new", 10, SizeRequest{Width: 200, Height: 100})
+	i.ReplacePresetSizes(thumb)
+
+	assert.Len(t, i.Sizes, 2)
+	assert.Equal(t, "other", i.Sizes[0].Path)
+	assert.Equal(t, "", i.Sizes[0].Preset)
+	assert.Equal(t, "new", i.Sizes[1].Path)
+	assert.Equal(t, "thumb", i.Sizes[1].Preset)
+	assert.Equal(t, []string{"old"}, i.Trash)
+}
+
+func TestImage_LabelPresetSize(t *testing.T) {
+	i := Image{}
+	i.AddSize("plain", 10, SizeRequest{Width: 100, Height: 100})
+	i.AddSize("card", 10, SizeRequest{Width: 200, Height: 200, Preset: "card"})
+
+	i.LabelPresetSize(SizeRequest{Width: 100, Height: 100, Preset: "thumb"})
+	assert.Equal(t, "thumb", i.Sizes[0].Preset)
+
+	// sizes of other presets keep their labels
+	i.LabelPresetSize(SizeRequest{Width: 200, Height: 200, Preset: "thumb"})
+	assert.Equal(t, "card", i.Sizes[1].Preset)
+}
+
+func TestImage_RemoveSmartSizes(t *testing.T) {
+	smart := SizeRequest{Width: 100, Height: 100, Mode: ResizeModeFill, Anchor: AnchorSmart, Format: FormatJPEG, Preset: "thumb"}
+	i := Image{}
+	i.AddSize("smart", 10, smart)
+	i.AddSize("center", 10, SizeRequest{Width: 100, Height: 100, Mode: ResizeModeFill})
+	i.AddPendingSize(SizeRequest{Width: 200, Height: 200, Mode: ResizeModeFill, Anchor: AnchorSmart})
+
+	requests := i.RemoveSmartSizes()
+	assert.Len(t, requests, 1)
+	// the request renders the same size again
+	i.AddSize("smart-new", 10, requests[0])
+	assert.Equal(t, "thumb", i.Sizes[2].Preset)
+	assert.True(t, i.HasResizedSize(smart))
+
+	// pending sizes are kept
+	assert.Equal(t, []string{"center", "", "smart-new"}, []string{i.Sizes[0].Path, i.Sizes[1].Path, i.Sizes[2].Path})
+	assert.Equal(t, []string{"smart"}, i.Trash)
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+const (
+	JobKindResize   JobKind = ""
+	JobKindCleanup  JobKind = "cleanup"
+	JobKindRerender JobKind = "rerender"
+	JobKindRemove   JobKind = "remove"
+)
+
+type (
+	JobStatus string
+	JobKind   string
+)
+
+type Job struct {
+	ID        string        `json:"id" bson:"_id"`
+	Kind      JobKind       `json:"kind,omitempty" bson:"kind,omitempty"`
+	ImageID   string        `json:"imageId" bson:"imageId"`
+	Sizes     []SizeRequest `json:"sizes" bson:"sizes"`
+	Paths     []string      `json:"paths,omitempty" bson:"paths,omitempty"`
+	Status    JobStatus     `json:"status" bson:"status"`
+	Error     string        `json:"error,omitempty" bson:"error,omitempty"`
+	Attempts  int           `json:"attempts" bson:"attempts"`
+	CreatedAt time.Time     `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusDone || j.Status == JobStatusFailed
+}
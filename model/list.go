@@ -0,0 +1,80 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ImageSortUploadAt ImageSortField = "uploadAt"
+	ImageSortSize     ImageSortField = "size"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+type ImageSortField string
+
+// ImageFilter fields which are empty don't filter.
+type ImageFilter struct {
+	MimeType         string
+	UploadedAfter    time.Time
+	UploadedBefore   time.Time
+	ClientNamePrefix string
+}
+
+type ImageSort struct {
+	Field ImageSortField `validate:"omitempty,oneof=uploadAt size"`
+	Desc  bool
+}
+
+func (s ImageSort) SortField() ImageSortField {
+	if s.Field == "" {
+		return ImageSortUploadAt
+	}
+
+	return s.Field
+}
+
+type ImageCursor struct {
+	UploadAt time.Time
+	ID       string
+}
+
+func CursorOf(image *Image) ImageCursor {
+	return ImageCursor{UploadAt: image.UploadAt, ID: image.ID}
+}
+
+func (c ImageCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.UploadAt.UnixNano(), 10) + ":" + c.ID))
+}
+
+func ParseImageCursor(value string) (ImageCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ImageCursor{}, err
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return ImageCursor{}, errInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ImageCursor{}, err
+	}
+
+	return ImageCursor{UploadAt: time.Unix(0, nanos).UTC(), ID: parts[1]}, nil
+}
+
+type PageRequest struct {
+	First int `validate:"min=1,max=100"`
+	After string
+}
+
+type ImagePage struct {
+	Images      []*Image
+	HasNextPage bool
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCursor(t *testing.T) {
+	cursor := ImageCursor{UploadAt: time.Date(2020, 5, 1, 10, 0, 0, 123, time.UTC), ID: "id:with:colons"}
+
+	parsed, err := ParseImageCursor(cursor.String())
+	assert.NoError(t, err)
+	assert.True(t, cursor.UploadAt.Equal(parsed.UploadAt))
+	assert.Equal(t, cursor.ID, parsed.ID)
+
+	f := func(value string) {
+		_, err := ParseImageCursor(value)
+		assert.Error(t, err)
+	}
+
+	f("not base64!")
+	f("bm8tc2VwYXJhdG9y")
+	f("YWJjOmlk")
+}
@@ -0,0 +1,97 @@
+package model
+
+const (
+	ColorModelGray      ColorModel = "gray"
+	ColorModelGrayAlpha ColorModel = "gray-alpha"
+	ColorModelRGB       ColorModel = "rgb"
+	ColorModelRGBA      ColorModel = "rgba"
+	ColorModelPaletted  ColorModel = "paletted"
+	ColorModelYCbCr     ColorModel = "ycbcr"
+	ColorModelCMYK      ColorModel = "cmyk"
+)
+
+const (
+	MetadataPolicyKeep     MetadataPolicy = "keep"
+	MetadataPolicyStripGPS MetadataPolicy = "strip-gps"
+	MetadataPolicyStripAll MetadataPolicy = "strip-all"
+)
+
+const (
+	MetadataMethodRewrite  MetadataMethod = "rewrite"
+	MetadataMethodReencode MetadataMethod = "reencode"
+)
+
+type (
+	ColorModel     string
+	MetadataPolicy string
+	MetadataMethod string
+)
+
+func (p MetadataPolicy) Valid() bool {
+	return p == MetadataPolicyKeep || p.Strips()
+}
+
+func (p MetadataPolicy) Strips() bool {
+	return p == MetadataPolicyStripGPS || p == MetadataPolicyStripAll
+}
+
+func (m MetadataMethod) Valid() bool {
+	return m == MetadataMethodRewrite || m == MetadataMethodReencode
+}
+
+// For rewrites only JPEG and PNG metadata in place, originals of other formats are re-encoded.
+func (m MetadataMethod) For(format Format) MetadataMethod {
+	if m == MetadataMethodRewrite && (format == FormatJPEG || format == FormatPNG) {
+		return MetadataMethodRewrite
+	}
+
+	return MetadataMethodReencode
+}
+
+// Metadata is empty for images uploaded before metadata was introduced.
+type Metadata struct {
+	Format Format `json:"format,omitempty" bson:"format,omitempty"`
+	// as displayed, i.e. with the orientation applied
+	Width  int `json:"width,omitempty" bson:"width,omitempty"`
+	Height int `json:"height,omitempty" bson:"height,omitempty"`
+	// zero if it isn't set
+	Orientation int        `json:"orientation,omitempty" bson:"orientation,omitempty"`
+	ColorModel  ColorModel `json:"colorModel,omitempty" bson:"colorModel,omitempty"`
+	HasAlpha    bool       `json:"hasAlpha,omitempty" bson:"hasAlpha,omitempty"`
+	Exif        *Exif      `json:"exif,omitempty" bson:"exif,omitempty"`
+}
+
+// Strip doesn't keep the orientation of re-encoded originals, re-encoding applies it to pixels.
+func (m Metadata) Strip(policy MetadataPolicy, method MetadataMethod) Metadata {
+	if !policy.Strips() {
+		return m
+	}
+
+	switch {
+	case policy == MetadataPolicyStripAll:
+		m.Exif = nil
+	case m.Exif != nil:
+		exif := *m.Exif
+		exif.HasGPS = false
+		m.Exif = &exif
+	}
+	if method == MetadataMethodReencode {
+		m.Orientation = 0
+	}
+
+	return m
+}
+
+// Exif doesn't keep GPS coordinates.
+type Exif struct {
+	Make             string  `json:"make,omitempty" bson:"make,omitempty"`
+	Model            string  `json:"model,omitempty" bson:"model,omitempty"`
+	LensModel        string  `json:"lensModel,omitempty" bson:"lensModel,omitempty"`
+	Software         string  `json:"software,omitempty" bson:"software,omitempty"`
+	DateTimeOriginal string  `json:"dateTimeOriginal,omitempty" bson:"dateTimeOriginal,omitempty"`
+	ExposureTime     string  `json:"exposureTime,omitempty" bson:"exposureTime,omitempty"`
+	FNumber          float64 `json:"fNumber,omitempty" bson:"fNumber,omitempty"`
+	ISO              int     `json:"iso,omitempty" bson:"iso,omitempty"`
+	FocalLength      float64 `json:"focalLength,omitempty" bson:"focalLength,omitempty"`
+	HasGPS           bool    `json:"hasGps,omitempty" bson:"hasGps,omitempty"`
+}
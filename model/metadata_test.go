@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata_Strip(t *testing.T) {
+	metadata := Metadata{Format: FormatJPEG, Orientation: 6, Exif: &Exif{Make: "Canon", HasGPS: true}}
+
+	f := func(policy MetadataPolicy, method MetadataMethod, expected Metadata) {
+		assert.Equal(t, expected, metadata.Strip(policy, method))
+	}
+
+	f(MetadataPolicyKeep, MetadataMethodReencode, metadata)
+	f(MetadataPolicyStripGPS, MetadataMethodRewrite, Metadata{Format: FormatJPEG, Orientation: 6, Exif: &Exif{Make: "Canon"}})
+	f(MetadataPolicyStripAll, MetadataMethodRewrite, Metadata{Format: FormatJPEG, Orientation: 6})
+	f(MetadataPolicyStripAll, MetadataMethodReencode, Metadata{Format: FormatJPEG})
+	// the original isn't modified
+	assert.True(t, metadata.Exif.HasGPS)
+}
+
+func TestMetadataMethod_For(t *testing.T) {
+	assert.Equal(t, MetadataMethodRewrite, MetadataMethodRewrite.For(FormatJPEG))
+	assert.Equal(t, MetadataMethodRewrite, MetadataMethodRewrite.For(FormatPNG))
+	assert.Equal(t, MetadataMethodReencode, MetadataMethodRewrite.For(FormatGIF))
+	assert.Equal(t, MetadataMethodReencode, MetadataMethodReencode.For(FormatJPEG))
+}
+
+func TestMetadataPolicy_Valid(t *testing.T) {
+	assert.True(t, MetadataPolicyKeep.Valid())
+	assert.True(t, MetadataPolicyStripGPS.Valid())
+	assert.True(t, MetadataPolicyStripAll.Valid())
+	assert.False(t, MetadataPolicy("strip_gps").Valid())
+	assert.False(t, MetadataPolicy("").Valid())
+
+	assert.True(t, MetadataMethodRewrite.Valid())
+	assert.True(t, MetadataMethodReencode.Valid())
+	assert.False(t, MetadataMethod("re-encode").Valid())
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	OperationResize     OperationName = "resize"
+	OperationBlur       OperationName = "blur"
+	OperationSharpen    OperationName = "sharpen"
+	OperationGamma      OperationName = "gamma"
+	OperationContrast   OperationName = "contrast"
+	OperationBrightness OperationName = "brightness"
+	OperationSaturation OperationName = "saturation"
+	OperationGrayscale  OperationName = "grayscale"
+	OperationInvert     OperationName = "invert"
+)
+
+type (
+	OperationName string
+
+	Operation struct {
+		Name OperationName `json:"name" bson:"name"`
+		// the sigma of blur and sharpen, the gamma of gamma, the percentage of contrast, brightness and saturation
+		Value float64 `json:"value,omitempty" bson:"value,omitempty"`
+	}
+)
+
+func (o Operation) String() string {
+	if o.Value == 0 {
+		return string(o.Name)
+	}
+
+	return string(o.Name) + ":" + strconv.FormatFloat(o.Value, 'f', -1, 64)
+}
+
+func pipeline(operations []Operation) []Operation {
+	for _, operation := range operations {
+		if operation.Name == OperationResize {
+			return operations
+		}
+	}
+
+	return append([]Operation{{Name: OperationResize}}, operations...)
+}
+
+// normalizeOperations stores the lone resize as no operations, so sizes stored before operations
+// were introduced match.
+func normalizeOperations(operations []Operation) []Operation {
+	operations = pipeline(operations)
+	if len(operations) == 1 {
+		return nil
+	}
+
+	return append([]Operation{}, operations...)
+}
+
+func operationsEqual(a, b []Operation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FormatOperations formats the operations like sharpen:0.5,grayscale.
+func FormatOperations(operations []Operation) string {
+	parts := make([]string, len(operations))
+	for i, operation := range operations {
+		parts[i] = operation.String()
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// ParseOperations doesn't validate the operations.
+func ParseOperations(value string) ([]Operation, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	operations := make([]Operation, len(parts))
+	for i, part := range parts {
+		name, number := part, ""
+		if n := strings.IndexByte(part, ':'); n >= 0 {
+			name, number = part[:n], part[n+1:]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid operation %q", part)
+		}
+		operations[i].Name = OperationName(name)
+		if number == "" {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation %q", part)
+		}
+		operations[i].Value = v
+	}
+
+	return operations, nil
+}
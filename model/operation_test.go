@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperations_Format(t *testing.T) {
+	f := func(value string, operations []Operation) {
+		parsed, err := ParseOperations(value)
+		assert.NoError(t, err)
+		assert.Equal(t, operations, parsed)
+		assert.Equal(t, value, FormatOperations(operations))
+	}
+
+	f("", nil)
+	f("grayscale", []Operation{{Name: OperationGrayscale}})
+	f("blur:2,resize,sharpen:0.5,contrast:-20", []Operation{
+		{Name: OperationBlur, Value: 2},
+		{Name: OperationResize},
+		{Name: OperationSharpen, Value: 0.5},
+		{Name: OperationContrast, Value: -20},
+	})
+
+	for _, invalid := range []string{",", "blur:x", ":1"} {
+		_, err := ParseOperations(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSize_MatchesOperations(t *testing.T) {
+	sharpen := Operation{Name: OperationSharpen, Value: 0.5}
+	request := SizeRequest{Width: 100, Height: 100, Operations: []Operation{sharpen}}
+	i := Image{}
+	i.AddSize("sharpened", 10, request)
+
+	// the resize is stored in place
+	assert.Equal(t, []Operation{{Name: OperationResize}, sharpen}, i.Sizes[0].Operations)
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100, Operations: []Operation{{Name: OperationResize}, sharpen}}))
+	assert.True(t, i.HasResizedSize(i.Sizes[0].Request()))
+	// the order is a part of the identity
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100, Operations: []Operation{sharpen, {Name: OperationResize}}}))
+	assert.False(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100}))
+
+	// the lone resize is the plain size
+	i.AddSize("plain", 10, SizeRequest{Width: 100, Height: 100, Operations: []Operation{{Name: OperationResize}}})
+	assert.Nil(t, i.Sizes[1].Operations)
+	assert.True(t, i.HasResizedSize(SizeRequest{Width: 100, Height: 100}))
+	assert.Len(t, i.Sizes, 2)
+}
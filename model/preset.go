@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type Preset struct {
+	Name      string      `json:"name" bson:"_id" validate:"required,max=64,identifier"`
+	Size      SizeRequest `json:"size" bson:"size"`
+	Version   int         `json:"version" bson:"version"`
+	UpdatedAt time.Time   `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (p Preset) Request() SizeRequest {
+	request := p.Size
+	request.Preset = p.Name
+
+	return request
+}
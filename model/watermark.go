@@ -0,0 +1,50 @@
+package model
+
+import (
+	"image"
+	"time"
+)
+
+const (
+	DefaultWatermarkOpacity = 0.5
+	DefaultWatermarkScale   = 0.25
+)
+
+type Watermark struct {
+	ID   string `json:"id" bson:"_id" validate:"required,max=64,identifier"`
+	Path string `json:"path" bson:"path"`
+	// ignored by tiled watermarks
+	Position Anchor `json:"position" bson:"position" validate:"omitempty,oneof=center top-left top top-right left right bottom-left bottom bottom-right"`
+	// from 0 to 1
+	Opacity float64 `json:"opacity" bson:"opacity" validate:"gt=0,max=1"`
+	// relative to the width of the size, the aspect ratio of the overlay is kept
+	Scale float64 `json:"scale" bson:"scale" validate:"gt=0,max=1"`
+	Tile  bool    `json:"tile" bson:"tile"`
+	// hidden, their overlays are kept for sizes which refer to them
+	Deleted   bool      `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	Version   int       `json:"version" bson:"version"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (w Watermark) Overlay(img image.Image) *Overlay {
+	position := w.Position
+	if position == "" {
+		position = AnchorBottomRight
+	}
+
+	return &Overlay{
+		Image:    img,
+		Position: position,
+		Opacity:  w.Opacity,
+		Scale:    w.Scale,
+		Tile:     w.Tile,
+	}
+}
+
+type Overlay struct {
+	Image    image.Image
+	Position Anchor
+	Opacity  float64
+	Scale    float64
+	Tile     bool
+}
@@ -1,6 +1,23 @@
 package opts
 
-import "github.com/portey/image-resizer/storage/minio"
+import (
+	"time"
+
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/signature"
+	"github.com/portey/image-resizer/storage/filesystem"
+	"github.com/portey/image-resizer/storage/minio"
+)
+
+const (
+	BackendMongo  = "mongo"
+	BackendBolt   = "bolt"
+	BackendMemory = "memory"
+
+	StorageBackendMinio      = "minio"
+	StorageBackendFilesystem = "filesystem"
+	StorageBackendMemory     = "memory"
+)
 
 type Config struct {
 	PrettyLogOutput bool
@@ -9,8 +26,31 @@ type Config struct {
 	GraphQLPort     int
 	HealthCHeckPort int
 
+	ImageCacheMaxAge     time.Duration
+	ImageBaseURL         string
+	ImageSigningKeys     []signature.Key
+	ImageURLMaxExpiresIn time.Duration
+
+	Backend       string
 	MongoURI      string
 	MongoDatabase string
+	BoltPath      string
+
+	StorageBackend string
+	StorageCfg     minio.Config
+	FilesystemCfg  filesystem.Config
+
+	ResizeWorkers int
+	JobWorkers    int
+
+	MaxInputPixels     int
+	MaxOutputDimension int
+	MaxUploadBytes     int64
+	ResizeTimeout      time.Duration
+	MaxMemoryBytes     int64
+
+	CleanupGracePeriod time.Duration
 
-	StorageCfg minio.Config
+	MetadataPolicy model.MetadataPolicy
+	MetadataMethod model.MetadataMethod
 }
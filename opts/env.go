@@ -1,6 +1,12 @@
 package opts
 
 import (
+	"runtime"
+	"strings"
+
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/signature"
+	"github.com/portey/image-resizer/storage/filesystem"
 	"github.com/portey/image-resizer/storage/minio"
 	"github.com/spf13/viper"
 )
@@ -16,8 +22,23 @@ func ReadOS() Config {
 	viper.SetDefault("GRAPH_QL_PORT", 8080)
 	viper.SetDefault("HEALTH_CHECK_PORT", 8888)
 
+	viper.SetDefault("IMAGE_CACHE_MAX_AGE", "24h")
+	viper.SetDefault("IMAGE_BASE_URL", "http://localhost:8080")
+	// comma separated id:secret pairs, the first key signs urls, all of them are accepted
+	viper.SetDefault("IMAGE_SIGNING_KEYS", "")
+	viper.SetDefault("IMAGE_URL_MAX_EXPIRES_IN", "168h")
+
+	// mongo, bolt or memory, memory keeps nothing across restarts and is meant for demos
+	viper.SetDefault("BACKEND", BackendMongo)
 	viper.SetDefault("MONGO_URI", "mongodb://localhost:27017")
 	viper.SetDefault("MONGO_DATABASE", "images")
+	viper.SetDefault("BOLT_PATH", "images.db")
+
+	// minio, filesystem or memory, the memory backend defaults to the memory storage
+	viper.SetDefault("STORAGE_BACKEND", StorageBackendMinio)
+	if viper.GetString("BACKEND") == BackendMemory {
+		viper.SetDefault("STORAGE_BACKEND", StorageBackendMemory)
+	}
 
 	viper.SetDefault("MINIO_ENDPOINT", "127.0.0.1:9000")
 	viper.SetDefault("MINIO_KEY_ID", "minioadmin")
@@ -26,6 +47,29 @@ func ReadOS() Config {
 	viper.SetDefault("MINIO_BUCKET", "images")
 	viper.SetDefault("MINIO_LOCATION", "us-east-1")
 	viper.SetDefault("MINIO_ROOT_PATH", "images")
+	// bytes of a multipart upload part, at least 5 MiB
+	viper.SetDefault("MINIO_PART_SIZE", 5<<20)
+
+	viper.SetDefault("FILESYSTEM_ROOT_PATH", "data")
+
+	viper.SetDefault("RESIZE_WORKERS", runtime.NumCPU())
+	viper.SetDefault("JOB_WORKERS", 2)
+
+	// zero disables a limit
+	viper.SetDefault("MAX_INPUT_PIXELS", 50000000)
+	viper.SetDefault("MAX_OUTPUT_DIMENSION", 8000)
+	viper.SetDefault("MAX_UPLOAD_BYTES", 32<<20)
+	viper.SetDefault("RESIZE_TIMEOUT", "30s")
+	// bytes of an upload kept in memory, the rest is spooled to temp files
+	viper.SetDefault("MAX_MEMORY_BYTES", 4<<20)
+
+	// unreferenced objects written within the period aren't removed, it must exceed the time to resize and save a size
+	viper.SetDefault("CLEANUP_GRACE_PERIOD", "1h")
+
+	// keep, strip-gps or strip-all, uploads can override the policy
+	viper.SetDefault("METADATA_POLICY", "keep")
+	// rewrite changes only metadata segments of JPEG and PNG originals, reencode decodes and encodes originals again
+	viper.SetDefault("METADATA_METHOD", "rewrite")
 
 	return Config{
 		PrettyLogOutput: viper.GetBool("PRETTY_LOG_OUTPUT"),
@@ -34,9 +78,18 @@ func ReadOS() Config {
 		GraphQLPort:     viper.GetInt("GRAPH_QL_PORT"),
 		HealthCHeckPort: viper.GetInt("HEALTH_CHECK_PORT"),
 
+		ImageCacheMaxAge: viper.GetDuration("IMAGE_CACHE_MAX_AGE"),
+		ImageBaseURL:     viper.GetString("IMAGE_BASE_URL"),
+		ImageSigningKeys: parseSigningKeys(viper.GetString("IMAGE_SIGNING_KEYS")),
+
+		ImageURLMaxExpiresIn: viper.GetDuration("IMAGE_URL_MAX_EXPIRES_IN"),
+
+		Backend:       viper.GetString("BACKEND"),
 		MongoURI:      viper.GetString("MONGO_URI"),
 		MongoDatabase: viper.GetString("MONGO_DATABASE"),
+		BoltPath:      viper.GetString("BOLT_PATH"),
 
+		StorageBackend: viper.GetString("STORAGE_BACKEND"),
 		StorageCfg: minio.Config{
 			Endpoint:        viper.GetString("MINIO_ENDPOINT"),
 			AccessKeyID:     viper.GetString("MINIO_KEY_ID"),
@@ -45,6 +98,40 @@ func ReadOS() Config {
 			BucketName:      viper.GetString("MINIO_BUCKET"),
 			Location:        viper.GetString("MINIO_LOCATION"),
 			RootPath:        viper.GetString("MINIO_ROOT_PATH"),
+			PartSize:        viper.GetUint64("MINIO_PART_SIZE"),
+		},
+		FilesystemCfg: filesystem.Config{
+			RootPath: viper.GetString("FILESYSTEM_ROOT_PATH"),
 		},
+
+		ResizeWorkers: viper.GetInt("RESIZE_WORKERS"),
+		JobWorkers:    viper.GetInt("JOB_WORKERS"),
+
+		MaxInputPixels:     viper.GetInt("MAX_INPUT_PIXELS"),
+		MaxOutputDimension: viper.GetInt("MAX_OUTPUT_DIMENSION"),
+		MaxUploadBytes:     viper.GetInt64("MAX_UPLOAD_BYTES"),
+		ResizeTimeout:      viper.GetDuration("RESIZE_TIMEOUT"),
+		MaxMemoryBytes:     viper.GetInt64("MAX_MEMORY_BYTES"),
+
+		CleanupGracePeriod: viper.GetDuration("CLEANUP_GRACE_PERIOD"),
+
+		MetadataPolicy: model.MetadataPolicy(viper.GetString("METADATA_POLICY")),
+		MetadataMethod: model.MetadataMethod(viper.GetString("METADATA_METHOD")),
 	}
 }
+
+func parseSigningKeys(value string) []signature.Key {
+	var keys []signature.Key
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		keys = append(keys, signature.Key{
+			ID:     parts[0],
+			Secret: parts[1],
+		})
+	}
+
+	return keys
+}
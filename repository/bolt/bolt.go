@@ -0,0 +1,833 @@
+// Package bolt keeps images, jobs and presets in an embedded bbolt file, so the service runs without MongoDB.
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+const openTimeout = 5 * time.Second
+
+var (
+	imagesBucket = []byte("images")
+	// uploadAtBucket indexes images which aren't deleted by the upload time and ID, the order of listing.
+	uploadAtBucket = []byte("images.uploadAt")
+	// pathsBucket indexes stored objects referenced by images which aren't deleted by the path and image ID.
+	pathsBucket = []byte("images.paths")
+	// sharedBucket maps hashes to shared images, at most one image is shared per hash.
+	sharedBucket     = []byte("images.shared")
+	jobsBucket       = []byte("jobs")
+	presetsBucket    = []byte("presets")
+	watermarksBucket = []byte("watermarks")
+)
+
+// Repository updates indexes in the same transaction as documents.
+type Repository struct {
+	db *bolt.DB
+}
+
+func New(path string) (*Repository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{imagesBucket, uploadAtBucket, pathsBucket, sharedBucket, jobsBucket, presetsBucket, watermarksBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Repository{db: db}, nil
+}
+
+func (r *Repository) Ping() error {
+	return r.db.View(func(*bolt.Tx) error {
+		return nil
+	})
+}
+
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+func (r *Repository) Get(ctx context.Context, id string) (*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var image *model.Image
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		image, err = getImage(tx, []byte(id))
+		if err == nil && image == nil {
+			err = errors.NotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return image, nil
+}
+
+// List walks the upload time index, images sorted by size are sorted in memory.
+func (r *Repository) List(ctx context.Context, filter model.ImageFilter, imageSort model.ImageSort, limit, offset int) ([]*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var images []*model.Image
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if imageSort.SortField() == model.ImageSortUploadAt {
+			skipped := 0
+			return scan(ctx, tx, filter, nil, imageSort.Desc, func(image *model.Image) bool {
+				if skipped < offset {
+					skipped++
+					return true
+				}
+				images = append(images, image)
+				return limit == 0 || len(images) < limit
+			})
+		}
+
+		err := scan(ctx, tx, filter, nil, false, func(image *model.Image) bool {
+			images = append(images, image)
+			return true
+		})
+		sort.SliceStable(images, func(i, j int) bool {
+			a, b := images[i], images[j]
+			if imageSort.Desc {
+				a, b = b, a
+			}
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+			return a.ID < b.ID
+		})
+		images = page(images, limit, offset)
+
+		return err
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return images, nil
+}
+
+func (r *Repository) ListAfter(ctx context.Context, filter model.ImageFilter, after *model.ImageCursor, limit int) ([]*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var images []*model.Image
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return scan(ctx, tx, filter, after, false, func(image *model.Image) bool {
+			images = append(images, image)
+			return limit == 0 || len(images) < limit
+		})
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return images, nil
+}
+
+func (r *Repository) Count(ctx context.Context, filter model.ImageFilter) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return scan(ctx, tx, filter, nil, false, func(*model.Image) bool {
+			count++
+			return true
+		})
+	})
+	if err != nil {
+		return 0, toServiceError(err)
+	}
+
+	return count, nil
+}
+
+// scan passes images matching the filter to fn in the order of the upload time index until fn returns false.
+func scan(ctx context.Context, tx *bolt.Tx, filter model.ImageFilter, after *model.ImageCursor, desc bool, fn func(*model.Image) bool) error {
+	// lower is the first key in the range, upper is the first key after the range
+	var lower, upper []byte
+	if !filter.UploadedAfter.IsZero() {
+		lower = timeKey(filter.UploadedAfter)
+	}
+	if after != nil {
+		// the least key greater than the key of the cursor
+		if key := append(uploadAtKey(after.UploadAt, after.ID), 0); bytes.Compare(key, lower) > 0 {
+			lower = key
+		}
+	}
+	if !filter.UploadedBefore.IsZero() {
+		upper = timeKey(filter.UploadedBefore)
+	}
+
+	c := tx.Bucket(uploadAtBucket).Cursor()
+	var key []byte
+	next := c.Next
+	switch {
+	case desc:
+		next = c.Prev
+		key = lastBefore(c, upper)
+	case lower != nil:
+		key, _ = c.Seek(lower)
+	default:
+		key, _ = c.First()
+	}
+
+	for ; key != nil; key, _ = next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if desc && lower != nil && bytes.Compare(key, lower) < 0 {
+			break
+		}
+		if !desc && upper != nil && bytes.Compare(key, upper) >= 0 {
+			break
+		}
+
+		image, err := getImage(tx, key[timeKeyLen:])
+		if err != nil {
+			return err
+		}
+		if image == nil || !matches(image, filter) {
+			continue
+		}
+		if !fn(image) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func lastBefore(c *bolt.Cursor, upper []byte) []byte {
+	if upper != nil {
+		if key, _ := c.Seek(upper); key != nil {
+			key, _ = c.Prev()
+			return key
+		}
+	}
+
+	key, _ := c.Last()
+	return key
+}
+
+func matches(image *model.Image, filter model.ImageFilter) bool {
+	switch {
+	case image.Deleted:
+		return false
+	case filter.MimeType != "" && image.MimeType != filter.MimeType:
+		return false
+	case !strings.HasPrefix(image.ClientName, filter.ClientNamePrefix):
+		return false
+	}
+
+	return true
+}
+
+// page doesn't limit images if the limit is zero like Mongo.
+func page(images []*model.Image, limit, offset int) []*model.Image {
+	if offset >= len(images) {
+		return nil
+	}
+	images = images[offset:]
+	if limit > 0 && limit < len(images) {
+		images = images[:limit]
+	}
+
+	return images
+}
+
+// ListPresetImages walks images after the ID in the key order, sizes aren't indexed by presets.
+func (r *Repository) ListPresetImages(ctx context.Context, preset, after string, limit int) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	err := r.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(imagesBucket).Cursor()
+		// the least key greater than the ID
+		key, value := c.Seek(append([]byte(after), 0))
+		for ; key != nil && (limit <= 0 || len(ids) < limit); key, value = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var image model.Image
+			if err := json.Unmarshal(value, &image); err != nil {
+				return err
+			}
+			if image.Deleted {
+				continue
+			}
+			for _, size := range image.Sizes {
+				if size.Preset == preset {
+					ids = append(ids, image.ID)
+					break
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return ids, nil
+}
+
+// ListTrashed walks all images, the trash isn't indexed.
+func (r *Repository) ListTrashed(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(imagesBucket).ForEach(func(_, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var image model.Image
+			if err := json.Unmarshal(value, &image); err != nil {
+				return err
+			}
+			if image.Deleted || len(image.Trash) > 0 {
+				ids = append(ids, image.ID)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) GetShared(ctx context.Context, hash string) (*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var image *model.Image
+	err := r.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(sharedBucket).Get([]byte(hash))
+		if id == nil {
+			return errors.NotFound
+		}
+
+		var err error
+		image, err = getImage(tx, id)
+		if err == nil && image == nil {
+			err = errors.NotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return image, nil
+}
+
+func (r *Repository) Referenced(ctx context.Context, path string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	var referenced bool
+	err := r.db.View(func(tx *bolt.Tx) error {
+		prefix := pathKey(path, "")
+		key, _ := tx.Bucket(pathsBucket).Cursor().Seek(prefix)
+		referenced = key != nil && bytes.HasPrefix(key, prefix)
+		return nil
+	})
+	if err != nil {
+		return false, toServiceError(err)
+	}
+
+	return referenced, nil
+}
+
+func (r *Repository) Save(ctx context.Context, version int, image model.Image) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := getImage(tx, []byte(image.ID))
+		if err != nil {
+			return err
+		}
+		if version == 0 && stored != nil {
+			return errors.RaceCondition
+		}
+		if version != 0 && (stored == nil || stored.Version != version) {
+			return errors.RaceCondition
+		}
+		if image.Shared {
+			if id := tx.Bucket(sharedBucket).Get([]byte(image.Hash)); id != nil && string(id) != image.ID {
+				return errors.RaceCondition
+			}
+		}
+
+		if stored != nil {
+			if err := unindex(tx, stored); err != nil {
+				return err
+			}
+		}
+		if err := index(tx, &image); err != nil {
+			return err
+		}
+
+		return put(tx.Bucket(imagesBucket), image.ID, image)
+	})
+
+	return toServiceError(err)
+}
+
+func (r *Repository) Delete(ctx context.Context, id string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := getImage(tx, []byte(id))
+		if err != nil {
+			return err
+		}
+		if stored == nil || stored.Version != version {
+			return errors.RaceCondition
+		}
+		if err := unindex(tx, stored); err != nil {
+			return err
+		}
+
+		return tx.Bucket(imagesBucket).Delete([]byte(id))
+	})
+
+	return toServiceError(err)
+}
+
+// index skips deleted images, they're only kept until their objects are removed.
+func index(tx *bolt.Tx, image *model.Image) error {
+	if image.Shared {
+		if err := tx.Bucket(sharedBucket).Put([]byte(image.Hash), []byte(image.ID)); err != nil {
+			return err
+		}
+	}
+	if image.Deleted {
+		return nil
+	}
+
+	if err := tx.Bucket(uploadAtBucket).Put(uploadAtKey(image.UploadAt, image.ID), nil); err != nil {
+		return err
+	}
+	for _, path := range paths(image) {
+		if err := tx.Bucket(pathsBucket).Put(pathKey(path, image.ID), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unindex(tx *bolt.Tx, image *model.Image) error {
+	if image.Shared {
+		if err := tx.Bucket(sharedBucket).Delete([]byte(image.Hash)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Bucket(uploadAtBucket).Delete(uploadAtKey(image.UploadAt, image.ID)); err != nil {
+		return err
+	}
+	for _, path := range paths(image) {
+		if err := tx.Bucket(pathsBucket).Delete(pathKey(path, image.ID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func paths(image *model.Image) []string {
+	var res []string
+	if image.Path != "" {
+		res = append(res, image.Path)
+	}
+	for _, size := range image.Sizes {
+		if size.Path != "" {
+			res = append(res, size.Path)
+		}
+	}
+
+	return res
+}
+
+const timeKeyLen = 12
+
+// timeKey encodes the time so encoded times are ordered as times, seconds are stored with the flipped sign bit.
+func timeKey(t time.Time) []byte {
+	key := make([]byte, timeKeyLen)
+	binary.BigEndian.PutUint64(key, uint64(t.Unix())^1<<63)
+	binary.BigEndian.PutUint32(key[8:], uint32(t.Nanosecond()))
+
+	return key
+}
+
+func uploadAtKey(uploadAt time.Time, id string) []byte {
+	return append(timeKey(uploadAt), id...)
+}
+
+// pathKey separates the path and the ID by zero byte, so all images referencing a path share the key prefix.
+func pathKey(path, id string) []byte {
+	key := append([]byte(path), 0)
+	return append(key, id...)
+}
+
+func getImage(tx *bolt.Tx, id []byte) (*model.Image, error) {
+	value := tx.Bucket(imagesBucket).Get(id)
+	if value == nil {
+		return nil, nil
+	}
+
+	var image model.Image
+	if err := json.Unmarshal(value, &image); err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+func put(bucket *bolt.Bucket, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(key), data)
+}
+
+func (r *Repository) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var job model.Job
+	err := r.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(jobsBucket).Get([]byte(id))
+		if value == nil {
+			return errors.NotFound
+		}
+		return json.Unmarshal(value, &job)
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return &job, nil
+}
+
+func (r *Repository) SaveJob(ctx context.Context, job model.Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(jobsBucket), job.ID, job)
+	})
+
+	return toServiceError(err)
+}
+
+func (r *Repository) ListUnfinishedJobs(ctx context.Context) ([]*model.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var jobs []*model.Job
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var job model.Job
+			if err := json.Unmarshal(value, &job); err != nil {
+				return err
+			}
+			if job.Status == model.JobStatusPending || job.Status == model.JobStatusRunning {
+				jobs = append(jobs, &job)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs, nil
+}
+
+func (r *Repository) GetPreset(ctx context.Context, name string) (*model.Preset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var preset *model.Preset
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		preset, err = getPreset(tx, name)
+		if err == nil && preset == nil {
+			err = errors.NotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return preset, nil
+}
+
+func (r *Repository) ListPresets(ctx context.Context) ([]*model.Preset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var presets []*model.Preset
+	err := r.db.View(func(tx *bolt.Tx) error {
+		// keys are names, so presets are ordered by names
+		return tx.Bucket(presetsBucket).ForEach(func(_, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var preset model.Preset
+			if err := json.Unmarshal(value, &preset); err != nil {
+				return err
+			}
+			presets = append(presets, &preset)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return presets, nil
+}
+
+func (r *Repository) SavePreset(ctx context.Context, version int, preset model.Preset) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := getPreset(tx, preset.Name)
+		if err != nil {
+			return err
+		}
+		if version == 0 && stored != nil {
+			return errors.RaceCondition
+		}
+		if version != 0 && (stored == nil || stored.Version != version) {
+			return errors.RaceCondition
+		}
+
+		return put(tx.Bucket(presetsBucket), preset.Name, preset)
+	})
+
+	return toServiceError(err)
+}
+
+func (r *Repository) DeletePreset(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(presetsBucket)
+		if bucket.Get([]byte(name)) == nil {
+			return errors.NotFound
+		}
+		return bucket.Delete([]byte(name))
+	})
+
+	return toServiceError(err)
+}
+
+func getPreset(tx *bolt.Tx, name string) (*model.Preset, error) {
+	value := tx.Bucket(presetsBucket).Get([]byte(name))
+	if value == nil {
+		return nil, nil
+	}
+
+	var preset model.Preset
+	if err := json.Unmarshal(value, &preset); err != nil {
+		return nil, err
+	}
+
+	return &preset, nil
+}
+
+func (r *Repository) GetWatermark(ctx context.Context, id string) (*model.Watermark, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var watermark *model.Watermark
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		watermark, err = getWatermark(tx, id)
+		if err == nil && watermark == nil {
+			err = errors.NotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return watermark, nil
+}
+
+func (r *Repository) ListWatermarks(ctx context.Context) ([]*model.Watermark, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var watermarks []*model.Watermark
+	err := r.db.View(func(tx *bolt.Tx) error {
+		// keys are IDs, so watermarks are ordered by IDs
+		return tx.Bucket(watermarksBucket).ForEach(func(_, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var watermark model.Watermark
+			if err := json.Unmarshal(value, &watermark); err != nil {
+				return err
+			}
+			watermarks = append(watermarks, &watermark)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return watermarks, nil
+}
+
+func (r *Repository) SaveWatermark(ctx context.Context, version int, watermark model.Watermark) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := getWatermark(tx, watermark.ID)
+		if err != nil {
+			return err
+		}
+		if version == 0 && stored != nil {
+			return errors.RaceCondition
+		}
+		if version != 0 && (stored == nil || stored.Version != version) {
+			return errors.RaceCondition
+		}
+
+		return put(tx.Bucket(watermarksBucket), watermark.ID, watermark)
+	})
+
+	return toServiceError(err)
+}
+
+func (r *Repository) DeleteWatermark(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(watermarksBucket)
+		if bucket.Get([]byte(id)) == nil {
+			return errors.NotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+
+	return toServiceError(err)
+}
+
+func getWatermark(tx *bolt.Tx, id string) (*model.Watermark, error) {
+	value := tx.Bucket(watermarksBucket).Get([]byte(id))
+	if value == nil {
+		return nil, nil
+	}
+
+	var watermark model.Watermark
+	if err := json.Unmarshal(value, &watermark); err != nil {
+		return nil, err
+	}
+
+	return &watermark, nil
+}
+
+func toServiceError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if serviceErr, ok := err.(errors.ServiceError); ok {
+		return serviceErr
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+
+	log.Error(err)
+
+	return errors.Internal
+}
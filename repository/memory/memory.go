@@ -0,0 +1,542 @@
+// Package memory keeps images, jobs and presets in memory with the same semantics as the Mongo repository.
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+)
+
+type Repository struct {
+	mu         sync.RWMutex
+	images     map[string]*model.Image
+	jobs       map[string]*model.Job
+	presets    map[string]*model.Preset
+	watermarks map[string]*model.Watermark
+}
+
+func New() *Repository {
+	return &Repository{
+		images:     make(map[string]*model.Image),
+		jobs:       make(map[string]*model.Job),
+		presets:    make(map[string]*model.Preset),
+		watermarks: make(map[string]*model.Watermark),
+	}
+}
+
+func (r *Repository) Ping() error {
+	return nil
+}
+
+func (r *Repository) Get(ctx context.Context, id string) (*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	image, ok := r.images[id]
+	if !ok {
+		return nil, errors.NotFound
+	}
+
+	return copyImage(image), nil
+}
+
+func (r *Repository) List(ctx context.Context, filter model.ImageFilter, sort model.ImageSort, limit, offset int) ([]*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	images := r.find(filter, func(a, b *model.Image) bool {
+		if sort.Desc {
+			a, b = b, a
+		}
+		if sort.SortField() == model.ImageSortSize && a.Size != b.Size {
+			return a.Size < b.Size
+		}
+		if sort.SortField() == model.ImageSortUploadAt && !a.UploadAt.Equal(b.UploadAt) {
+			return a.UploadAt.Before(b.UploadAt)
+		}
+
+		return a.ID < b.ID
+	})
+
+	return page(images, limit, offset), nil
+}
+
+func (r *Repository) ListAfter(ctx context.Context, filter model.ImageFilter, after *model.ImageCursor, limit int) ([]*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	images := r.find(filter, func(a, b *model.Image) bool {
+		return cursorLess(model.CursorOf(a), model.CursorOf(b))
+	})
+	if after != nil {
+		images = images[sort.Search(len(images), func(i int) bool {
+			return cursorLess(*after, model.CursorOf(images[i]))
+		}):]
+	}
+
+	return page(images, limit, 0), nil
+}
+
+func (r *Repository) Count(ctx context.Context, filter model.ImageFilter) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(r.find(filter, nil)), nil
+}
+
+func (r *Repository) find(filter model.ImageFilter, less func(a, b *model.Image) bool) []*model.Image {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var images []*model.Image
+	for _, image := range r.images {
+		if matches(image, filter) {
+			images = append(images, copyImage(image))
+		}
+	}
+	if less != nil {
+		sort.Slice(images, func(i, j int) bool {
+			return less(images[i], images[j])
+		})
+	}
+
+	return images
+}
+
+func matches(image *model.Image, filter model.ImageFilter) bool {
+	switch {
+	case image.Deleted:
+		return false
+	case filter.MimeType != "" && image.MimeType != filter.MimeType:
+		return false
+	case !strings.HasPrefix(image.ClientName, filter.ClientNamePrefix):
+		return false
+	case !filter.UploadedAfter.IsZero() && image.UploadAt.Before(filter.UploadedAfter):
+		return false
+	case !filter.UploadedBefore.IsZero() && !image.UploadAt.Before(filter.UploadedBefore):
+		return false
+	}
+
+	return true
+}
+
+func cursorLess(a, b model.ImageCursor) bool {
+	if !a.UploadAt.Equal(b.UploadAt) {
+		return a.UploadAt.Before(b.UploadAt)
+	}
+
+	return a.ID < b.ID
+}
+
+// page doesn't limit images if the limit is zero like Mongo.
+func page(images []*model.Image, limit, offset int) []*model.Image {
+	if offset >= len(images) {
+		return nil
+	}
+	images = images[offset:]
+	if limit > 0 && limit < len(images) {
+		images = images[:limit]
+	}
+
+	return images
+}
+
+func (r *Repository) ListPresetImages(ctx context.Context, preset, after string, limit int) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []string
+	for _, image := range r.images {
+		if image.Deleted || image.ID <= after {
+			continue
+		}
+		for _, size := range image.Sizes {
+			if size.Preset == preset {
+				ids = append(ids, image.ID)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) ListTrashed(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []string
+	for _, image := range r.images {
+		if image.Deleted || len(image.Trash) > 0 {
+			ids = append(ids, image.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+func (r *Repository) GetShared(ctx context.Context, hash string) (*model.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if image := r.shared(hash); image != nil {
+		return copyImage(image), nil
+	}
+
+	return nil, errors.NotFound
+}
+
+// shared has to be called with the lock held.
+func (r *Repository) shared(hash string) *model.Image {
+	for _, image := range r.images {
+		if image.Shared && image.Hash == hash {
+			return image
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) Referenced(ctx context.Context, path string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, image := range r.images {
+		if image.Deleted {
+			continue
+		}
+		if image.Path == path {
+			return true, nil
+		}
+		for _, size := range image.Sizes {
+			if size.Path == path {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (r *Repository) Save(ctx context.Context, version int, image model.Image) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.images[image.ID]
+	if version == 0 && ok {
+		return errors.RaceCondition
+	}
+	if version != 0 && (!ok || stored.Version != version) {
+		return errors.RaceCondition
+	}
+	// at most one image is shared per hash like with the unique index of the Mongo repository
+	if image.Shared {
+		if shared := r.shared(image.Hash); shared != nil && shared.ID != image.ID {
+			return errors.RaceCondition
+		}
+	}
+
+	r.images[image.ID] = copyImage(&image)
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.images[id]
+	if !ok || stored.Version != version {
+		return errors.RaceCondition
+	}
+	delete(r.images, id)
+
+	return nil
+}
+
+func (r *Repository) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, errors.NotFound
+	}
+
+	return copyJob(job), nil
+}
+
+func (r *Repository) SaveJob(ctx context.Context, job model.Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[job.ID] = copyJob(&job)
+
+	return nil
+}
+
+func (r *Repository) ListUnfinishedJobs(ctx context.Context) ([]*model.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var jobs []*model.Job
+	for _, job := range r.jobs {
+		if job.Status == model.JobStatusPending || job.Status == model.JobStatusRunning {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs, nil
+}
+
+func (r *Repository) GetPreset(ctx context.Context, name string) (*model.Preset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preset, ok := r.presets[name]
+	if !ok {
+		return nil, errors.NotFound
+	}
+
+	return copyPreset(preset), nil
+}
+
+func (r *Repository) ListPresets(ctx context.Context) ([]*model.Preset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var presets []*model.Preset
+	for _, preset := range r.presets {
+		presets = append(presets, copyPreset(preset))
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+
+	return presets, nil
+}
+
+func (r *Repository) SavePreset(ctx context.Context, version int, preset model.Preset) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.presets[preset.Name]
+	if version == 0 && ok {
+		return errors.RaceCondition
+	}
+	if version != 0 && (!ok || stored.Version != version) {
+		return errors.RaceCondition
+	}
+	r.presets[preset.Name] = copyPreset(&preset)
+
+	return nil
+}
+
+func (r *Repository) DeletePreset(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.presets[name]; !ok {
+		return errors.NotFound
+	}
+	delete(r.presets, name)
+
+	return nil
+}
+
+func (r *Repository) GetWatermark(ctx context.Context, id string) (*model.Watermark, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	watermark, ok := r.watermarks[id]
+	if !ok {
+		return nil, errors.NotFound
+	}
+
+	return copyWatermark(watermark), nil
+}
+
+func (r *Repository) ListWatermarks(ctx context.Context) ([]*model.Watermark, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var watermarks []*model.Watermark
+	for _, watermark := range r.watermarks {
+		watermarks = append(watermarks, copyWatermark(watermark))
+	}
+	sort.Slice(watermarks, func(i, j int) bool {
+		return watermarks[i].ID < watermarks[j].ID
+	})
+
+	return watermarks, nil
+}
+
+func (r *Repository) SaveWatermark(ctx context.Context, version int, watermark model.Watermark) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.watermarks[watermark.ID]
+	if version == 0 && ok {
+		return errors.RaceCondition
+	}
+	if version != 0 && (!ok || stored.Version != version) {
+		return errors.RaceCondition
+	}
+	r.watermarks[watermark.ID] = copyWatermark(&watermark)
+
+	return nil
+}
+
+func (r *Repository) DeleteWatermark(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.watermarks[id]; !ok {
+		return errors.NotFound
+	}
+	delete(r.watermarks, id)
+
+	return nil
+}
+
+func copyImage(image *model.Image) *model.Image {
+	res := *image
+	res.Sizes = append([]model.Size{}, image.Sizes...)
+	for i := range res.Sizes {
+		res.Sizes[i].Operations = copyOperations(res.Sizes[i].Operations)
+	}
+	if image.Trash != nil {
+		res.Trash = append([]string{}, image.Trash...)
+	}
+	if image.Exif != nil {
+		exif := *image.Exif
+		res.Exif = &exif
+	}
+	if image.FocalPoint != nil {
+		point := *image.FocalPoint
+		res.FocalPoint = &point
+	}
+
+	return &res
+}
+
+func copyJob(job *model.Job) *model.Job {
+	res := *job
+	if job.Sizes != nil {
+		res.Sizes = append([]model.SizeRequest{}, job.Sizes...)
+		for i := range res.Sizes {
+			res.Sizes[i].Operations = copyOperations(res.Sizes[i].Operations)
+		}
+	}
+	if job.Paths != nil {
+		res.Paths = append([]string{}, job.Paths...)
+	}
+
+	return &res
+}
+
+func copyPreset(preset *model.Preset) *model.Preset {
+	res := *preset
+	res.Size.Operations = copyOperations(preset.Size.Operations)
+	return &res
+}
+
+func copyOperations(operations []model.Operation) []model.Operation {
+	if operations == nil {
+		return nil
+	}
+
+	return append([]model.Operation{}, operations...)
+}
+
+func copyWatermark(watermark *model.Watermark) *model.Watermark {
+	res := *watermark
+	return &res
+}
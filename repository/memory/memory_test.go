@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serviceerrors "github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/portey/image-resizer/service/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+
+	image := model.Image{
+		ID:       "id",
+		UploadAt: time.Now(),
+		Path:     "v1path",
+		Sizes:    []model.Size{{Path: "v1path", Width: 100, Height: 100}},
+		Version:  1,
+	}
+
+	err := repo.Save(ctx, 0, image)
+	assert.NoError(t, err)
+
+	// conflict error: inserting an image which already exists
+	err = repo.Save(ctx, 0, image)
+	assert.Equal(t, serviceerrors.RaceCondition, err)
+
+	// saving new version
+	image.Version++
+	image.Path = "v2path"
+	err = repo.Save(ctx, 1, image)
+	assert.NoError(t, err)
+
+	// conflict error: saving a version which already exists
+	err = repo.Save(ctx, 1, image)
+	assert.Equal(t, serviceerrors.RaceCondition, err)
+
+	// conflict error: the image was deleted meanwhile
+	err = repo.Save(ctx, 1, model.Image{ID: "missing", Version: 2})
+	assert.Equal(t, serviceerrors.RaceCondition, err)
+
+	res, err := repo.Get(ctx, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, &image, res)
+}
+
+func TestRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+
+	_, err := repo.Get(ctx, "id")
+	assert.Equal(t, serviceerrors.NotFound, err)
+
+	image := model.Image{
+		ID: "id",
+		Sizes: []model.Size{{
+			Path:       "path",
+			Width:      100,
+			Height:     100,
+			Operations: []model.Operation{{Name: model.OperationBlur, Value: 2}},
+		}},
+		Version: 1,
+		Metadata: model.Metadata{
+			Exif: &model.Exif{Make: "Camera"},
+		},
+	}
+	assert.NoError(t, repo.Save(ctx, 0, image))
+
+	// returned images don't share anything with stored ones
+	res, err := repo.Get(ctx, "id")
+	assert.NoError(t, err)
+	res.Sizes[0].Path = "changed"
+	res.Exif.Make = "changed"
+	res.Sizes[0].Operations[0].Value = 5
+	image.Sizes[0].Width = 200
+	image.Sizes[0].Operations[0].Name = model.OperationSharpen
+
+	res, err = repo.Get(ctx, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "path", res.Sizes[0].Path)
+	assert.Equal(t, 100, res.Sizes[0].Width)
+	assert.Equal(t, "Camera", res.Exif.Make)
+	assert.Equal(t, []model.Operation{{Name: model.OperationBlur, Value: 2}}, res.Sizes[0].Operations)
+
+	// canceled requests fail like requests to a real database
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repo.Get(canceled, "id")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestRepository_List(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+
+	now := time.Now()
+	for _, image := range []model.Image{
+		{ID: "a", ClientName: "cat.jpg", MimeType: "image/jpeg", Size: 30, UploadAt: now},
+		{ID: "b", ClientName: "dog.png", MimeType: "image/png", Size: 10, UploadAt: now.Add(time.Second)},
+		{ID: "c", ClientName: "cat.png", MimeType: "image/png", Size: 20, UploadAt: now},
+		{ID: "d", ClientName: "cat.gif", MimeType: "image/png", Size: 40, UploadAt: now, Deleted: true},
+	} {
+		image.Version = 1
+		assert.NoError(t, repo.Save(ctx, 0, image))
+	}
+
+	ids := func(images []*model.Image) []string {
+		var res []string
+		for _, image := range images {
+			res = append(res, image.ID)
+		}
+		return res
+	}
+
+	f := func(filter model.ImageFilter, sort model.ImageSort, limit, offset int, expected []string) {
+		images, err := repo.List(ctx, filter, sort, limit, offset)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ids(images))
+
+		if limit == 0 && offset == 0 {
+			count, err := repo.Count(ctx, filter)
+			assert.NoError(t, err)
+			assert.Equal(t, len(expected), count)
+		}
+	}
+
+	f(model.ImageFilter{}, model.ImageSort{}, 0, 0, []string{"a", "c", "b"})
+	f(model.ImageFilter{}, model.ImageSort{Desc: true}, 0, 0, []string{"b", "c", "a"})
+	f(model.ImageFilter{}, model.ImageSort{Field: model.ImageSortSize}, 0, 0, []string{"b", "c", "a"})
+	f(model.ImageFilter{}, model.ImageSort{}, 2, 1, []string{"c", "b"})
+	f(model.ImageFilter{}, model.ImageSort{}, 1, 5, nil)
+	f(model.ImageFilter{MimeType: "image/png"}, model.ImageSort{}, 0, 0, []string{"c", "b"})
+	f(model.ImageFilter{ClientNamePrefix: "cat"}, model.ImageSort{}, 0, 0, []string{"a", "c"})
+	f(model.ImageFilter{UploadedAfter: now.Add(time.Second)}, model.ImageSort{}, 0, 0, []string{"b"})
+	f(model.ImageFilter{UploadedBefore: now.Add(time.Second)}, model.ImageSort{}, 0, 0, []string{"a", "c"})
+
+	images, err := repo.ListAfter(ctx, model.ImageFilter{}, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, ids(images))
+
+	cursor := model.CursorOf(images[1])
+	images, err = repo.ListAfter(ctx, model.ImageFilter{}, &cursor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, ids(images))
+}
+
+func TestRepository_Shared(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+
+	image := model.Image{
+		ID:      "id",
+		Path:    "original",
+		Sizes:   []model.Size{{Path: "variant"}},
+		Hash:    "hash",
+		Shared:  true,
+		Version: 1,
+	}
+	assert.NoError(t, repo.Save(ctx, 0, image))
+
+	res, err := repo.GetShared(ctx, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "id", res.ID)
+
+	_, err = repo.GetShared(ctx, "other")
+	assert.Equal(t, serviceerrors.NotFound, err)
+
+	// only one image is shared per hash
+	err = repo.Save(ctx, 0, model.Image{ID: "other", Hash: "hash", Shared: true, Version: 1})
+	assert.Equal(t, serviceerrors.RaceCondition, err)
+
+	for path, expected := range map[string]bool{"original": true, "variant": true, "missing": false} {
+		referenced, err := repo.Referenced(ctx, path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, referenced, path)
+	}
+
+	// deleted images don't reference objects
+	image.MarkDeleted()
+	image.Version++
+	assert.NoError(t, repo.Save(ctx, 1, image))
+
+	referenced, err := repo.Referenced(ctx, "original")
+	assert.NoError(t, err)
+	assert.False(t, referenced)
+}
+
+func TestRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+
+	assert.NoError(t, repo.Save(ctx, 0, model.Image{ID: "id", Version: 1}))
+
+	err := repo.Delete(ctx, "id", 2)
+	assert.Equal(t, serviceerrors.RaceCondition, err)
+
+	err = repo.Delete(ctx, "id", 1)
+	assert.NoError(t, err)
+
+	_, err = repo.Get(ctx, "id")
+	assert.Equal(t, serviceerrors.NotFound, err)
+
+	err = repo.Delete(ctx, "id", 1)
+	assert.Equal(t, serviceerrors.RaceCondition, err)
+}
+
+func TestRepository_Conformance(t *testing.T) {
+	servicetest.TestRepository(t, func(t *testing.T) (service.Repository, func()) {
+		return New(), func() {}
+	})
+}
+
+func TestJobRepository_Conformance(t *testing.T) {
+	servicetest.TestJobRepository(t, func(t *testing.T) (service.JobRepository, func()) {
+		return New(), func() {}
+	})
+}
+
+func TestPresetRepository_Conformance(t *testing.T) {
+	servicetest.TestPresetRepository(t, func(t *testing.T) (service.PresetRepository, func()) {
+		return New(), func() {}
+	})
+}
+
+func TestWatermarkRepository_Conformance(t *testing.T) {
+	servicetest.TestWatermarkRepository(t, func(t *testing.T) (service.WatermarkRepository, func()) {
+		return New(), func() {}
+	})
+}
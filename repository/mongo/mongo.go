@@ -2,21 +2,33 @@ package mongo
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/portey/image-resizer/errors"
 	"github.com/portey/image-resizer/model"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const collection = "images"
+const (
+	collection           = "images"
+	jobsCollection       = "jobs"
+	presetsCollection    = "presets"
+	watermarksCollection = "watermarks"
+
+	duplicateKeyCode = 11000
+)
 
 type Repository struct {
 	client     *mongo.Client
 	collection *mongo.Collection
+	jobs       *mongo.Collection
+	presets    *mongo.Collection
+	watermarks *mongo.Collection
 }
 
 func New(ctx context.Context, uri, database string) (*Repository, error) {
@@ -41,10 +53,47 @@ func New(ctx context.Context, uri, database string) (*Repository, error) {
 		}
 	}()
 
-	return &Repository{
+	repo := &Repository{
 		client:     client,
 		collection: client.Database(database).Collection(collection),
-	}, nil
+		jobs:       client.Database(database).Collection(jobsCollection),
+		presets:    client.Database(database).Collection(presetsCollection),
+		watermarks: client.Database(database).Collection(watermarksCollection),
+	}
+
+	if err := repo.createIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *Repository) createIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "uploadAt", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "size", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "mimeType", Value: 1}, {Key: "uploadAt", Value: 1}}},
+		{Keys: bson.D{{Key: "clientName", Value: 1}}},
+		{Keys: bson.D{{Key: "sizes.preset", Value: 1}}},
+		{Keys: bson.D{{Key: "path", Value: 1}}},
+		{Keys: bson.D{{Key: "sizes.path", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "hash", Value: 1}},
+			// deduplicated uploads of the same content racing each other insert the shared image once
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{{Key: "shared", Value: true}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.jobs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+	})
+
+	return err
 }
 
 func (r *Repository) Ping() error {
@@ -68,12 +117,56 @@ func (r *Repository) Get(ctx context.Context, id string) (*model.Image, error) {
 	return &i, nil
 }
 
-func (r *Repository) List(ctx context.Context, limit, offset int) ([]*model.Image, error) {
+func (r *Repository) List(ctx context.Context, filter model.ImageFilter, sort model.ImageSort, limit, offset int) ([]*model.Image, error) {
+	direction := 1
+	if sort.Desc {
+		direction = -1
+	}
+
 	findOptions := options.Find()
 	findOptions.SetLimit(int64(limit))
 	findOptions.SetSkip(int64(offset))
+	findOptions.SetSort(bson.D{
+		{Key: string(sort.SortField()), Value: direction},
+		{Key: "_id", Value: direction},
+	})
+
+	return r.find(ctx, listFilter(filter), findOptions)
+}
 
-	cur, err := r.collection.Find(ctx, bson.D{}, findOptions)
+func (r *Repository) ListAfter(ctx context.Context, filter model.ImageFilter, after *model.ImageCursor, limit int) ([]*model.Image, error) {
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{
+		{Key: "uploadAt", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	query := listFilter(filter)
+	if after != nil {
+		query = append(query, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "uploadAt", Value: bson.D{{Key: "$gt", Value: after.UploadAt}}}},
+			bson.D{
+				{Key: "uploadAt", Value: after.UploadAt},
+				{Key: "_id", Value: bson.D{{Key: "$gt", Value: after.ID}}},
+			},
+		}})
+	}
+
+	return r.find(ctx, query, findOptions)
+}
+
+func (r *Repository) Count(ctx context.Context, filter model.ImageFilter) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, listFilter(filter))
+	if err != nil {
+		return 0, toServiceError(err)
+	}
+
+	return int(count), nil
+}
+
+func (r *Repository) find(ctx context.Context, filter bson.D, findOptions *options.FindOptions) ([]*model.Image, error) {
+	cur, err := r.collection.Find(ctx, filter, findOptions)
 	if err != nil {
 		return nil, toServiceError(err)
 	}
@@ -99,6 +192,30 @@ func (r *Repository) List(ctx context.Context, limit, offset int) ([]*model.Imag
 	return elems, nil
 }
 
+func listFilter(filter model.ImageFilter) bson.D {
+	res := bson.D{{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}}}
+	if filter.MimeType != "" {
+		res = append(res, bson.E{Key: "mimeType", Value: filter.MimeType})
+	}
+	if filter.ClientNamePrefix != "" {
+		// anchored regexp without options is served by the index
+		res = append(res, bson.E{Key: "clientName", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.ClientNamePrefix)}})
+	}
+
+	uploadAt := bson.D{}
+	if !filter.UploadedAfter.IsZero() {
+		uploadAt = append(uploadAt, bson.E{Key: "$gte", Value: filter.UploadedAfter})
+	}
+	if !filter.UploadedBefore.IsZero() {
+		uploadAt = append(uploadAt, bson.E{Key: "$lt", Value: filter.UploadedBefore})
+	}
+	if len(uploadAt) > 0 {
+		res = append(res, bson.E{Key: "uploadAt", Value: uploadAt})
+	}
+
+	return res
+}
+
 func (r *Repository) Save(ctx context.Context, version int, image model.Image) error {
 	filter := bson.D{
 		{Key: "_id", Value: image.ID},
@@ -110,18 +227,331 @@ func (r *Repository) Save(ctx context.Context, version int, image model.Image) e
 		return toServiceError(err)
 	}
 
-	updateResult, err := r.collection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: image}}, options.Update())
+	// the document is replaced, so fields which are emptied, e.g. the trash, are removed
+	updateResult, err := r.collection.ReplaceOne(ctx, filter, image)
+	if err != nil {
+		return toServiceError(err)
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return errors.RaceCondition
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id string, version int) error {
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "version", Value: version},
+	}
+
+	deleteResult, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return toServiceError(err)
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return errors.RaceCondition
+	}
+
+	return nil
+}
+
+func (r *Repository) ListPresetImages(ctx context.Context, preset, after string, limit int) ([]string, error) {
+	filter := bson.D{
+		{Key: "sizes.preset", Value: preset},
+		{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}},
+		{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	return r.findIDs(ctx, filter, findOptions)
+}
+
+func (r *Repository) ListTrashed(ctx context.Context) ([]string, error) {
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "deleted", Value: true}},
+			bson.D{{Key: "trash.0", Value: bson.D{{Key: "$exists", Value: true}}}},
+		}},
+	}
+
+	return r.findIDs(ctx, filter, options.Find())
+}
+
+func (r *Repository) findIDs(ctx context.Context, filter bson.D, findOptions *options.FindOptions) ([]string, error) {
+	cur, err := r.collection.Find(ctx, filter, findOptions.SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	var ids []string
+	for cur.Next(ctx) {
+		var elem struct {
+			ID string `bson:"_id"`
+		}
+		if err := cur.Decode(&elem); err != nil {
+			return nil, toServiceError(err)
+		}
+		ids = append(ids, elem.ID)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	if err := cur.Close(ctx); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) GetShared(ctx context.Context, hash string) (*model.Image, error) {
+	filter := bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "shared", Value: true},
+	}
+
+	res := r.collection.FindOne(ctx, filter)
+	if res.Err() != nil {
+		return nil, toServiceError(res.Err())
+	}
+
+	var i model.Image
+	if err := res.Decode(&i); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return &i, nil
+}
+
+func (r *Repository) Referenced(ctx context.Context, path string) (bool, error) {
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "path", Value: path}},
+			bson.D{{Key: "sizes.path", Value: path}},
+		}},
+		{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, toServiceError(err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *Repository) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	res := r.jobs.FindOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if res.Err() != nil {
+		return nil, toServiceError(res.Err())
+	}
+
+	var j model.Job
+	if err := res.Decode(&j); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return &j, nil
+}
+
+func (r *Repository) SaveJob(ctx context.Context, job model.Job) error {
+	_, err := r.jobs.ReplaceOne(ctx, bson.D{{Key: "_id", Value: job.ID}}, job, options.Replace().SetUpsert(true))
+
+	return toServiceError(err)
+}
+
+func (r *Repository) ListUnfinishedJobs(ctx context.Context) ([]*model.Job, error) {
+	filter := bson.D{{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{
+		model.JobStatusPending,
+		model.JobStatusRunning,
+	}}}}}
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	cur, err := r.jobs.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	var elems []*model.Job
+	for cur.Next(ctx) {
+		var elem model.Job
+		if err := cur.Decode(&elem); err != nil {
+			return nil, toServiceError(err)
+		}
+		elems = append(elems, &elem)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	if err := cur.Close(ctx); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return elems, nil
+}
+
+func (r *Repository) GetPreset(ctx context.Context, name string) (*model.Preset, error) {
+	res := r.presets.FindOne(ctx, bson.D{{Key: "_id", Value: name}})
+	if res.Err() != nil {
+		return nil, toServiceError(res.Err())
+	}
+
+	var p model.Preset
+	if err := res.Decode(&p); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return &p, nil
+}
+
+func (r *Repository) ListPresets(ctx context.Context) ([]*model.Preset, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cur, err := r.presets.Find(ctx, bson.D{}, findOptions)
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	var elems []*model.Preset
+	for cur.Next(ctx) {
+		var elem model.Preset
+		if err := cur.Decode(&elem); err != nil {
+			return nil, toServiceError(err)
+		}
+		elems = append(elems, &elem)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	if err := cur.Close(ctx); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return elems, nil
+}
+
+func (r *Repository) SavePreset(ctx context.Context, version int, preset model.Preset) error {
+	if version == 0 {
+		_, err := r.presets.InsertOne(ctx, preset)
+		return toServiceError(err)
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: preset.Name},
+		{Key: "version", Value: version},
+	}
+
+	updateResult, err := r.presets.ReplaceOne(ctx, filter, preset)
+	if err != nil {
+		return toServiceError(err)
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return errors.RaceCondition
+	}
+
+	return nil
+}
+
+func (r *Repository) DeletePreset(ctx context.Context, name string) error {
+	deleteResult, err := r.presets.DeleteOne(ctx, bson.D{{Key: "_id", Value: name}})
+	if err != nil {
+		return toServiceError(err)
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return errors.NotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) GetWatermark(ctx context.Context, id string) (*model.Watermark, error) {
+	res := r.watermarks.FindOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if res.Err() != nil {
+		return nil, toServiceError(res.Err())
+	}
+
+	var w model.Watermark
+	if err := res.Decode(&w); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return &w, nil
+}
+
+func (r *Repository) ListWatermarks(ctx context.Context) ([]*model.Watermark, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cur, err := r.watermarks.Find(ctx, bson.D{}, findOptions)
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	var elems []*model.Watermark
+	for cur.Next(ctx) {
+		var elem model.Watermark
+		if err := cur.Decode(&elem); err != nil {
+			return nil, toServiceError(err)
+		}
+		elems = append(elems, &elem)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	if err := cur.Close(ctx); err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return elems, nil
+}
+
+func (r *Repository) SaveWatermark(ctx context.Context, version int, watermark model.Watermark) error {
+	if version == 0 {
+		_, err := r.watermarks.InsertOne(ctx, watermark)
+		return toServiceError(err)
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: watermark.ID},
+		{Key: "version", Value: version},
+	}
+
+	updateResult, err := r.watermarks.ReplaceOne(ctx, filter, watermark)
 	if err != nil {
 		return toServiceError(err)
 	}
 
-	if updateResult.ModifiedCount == 0 {
+	if updateResult.MatchedCount == 0 {
 		return errors.RaceCondition
 	}
 
 	return nil
 }
 
+func (r *Repository) DeleteWatermark(ctx context.Context, id string) error {
+	deleteResult, err := r.watermarks.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return toServiceError(err)
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return errors.NotFound
+	}
+
+	return nil
+}
+
 func toServiceError(err error) error {
 	if err == nil {
 		return nil
@@ -131,6 +561,15 @@ func toServiceError(err error) error {
 		return errors.NotFound
 	}
 
+	// a concurrent insert of the same document
+	if writeErr, ok := err.(mongo.WriteException); ok {
+		for _, e := range writeErr.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return errors.RaceCondition
+			}
+		}
+	}
+
 	log.Error(err)
 
 	return errors.Internal
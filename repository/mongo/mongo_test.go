@@ -3,11 +3,14 @@ package mongo
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	serviceerrors "github.com/portey/image-resizer/errors"
 	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/portey/image-resizer/service/servicetest"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
@@ -134,12 +137,93 @@ func TestRepository_List(t *testing.T) {
 	err = repo.Save(ctx, 0, image)
 	assert.NoError(t, err)
 
-	res, err := repo.List(ctx, 100, 0)
+	res, err := repo.List(ctx, model.ImageFilter{}, model.ImageSort{}, 100, 0)
 	assert.NoError(t, err)
 	assert.Len(t, res, 1)
 	assert.Equal(t, image.ID, res[0].ID)
 
-	res, err = repo.List(ctx, 100, 1)
+	res, err = repo.List(ctx, model.ImageFilter{}, model.ImageSort{}, 100, 1)
 	assert.NoError(t, err)
 	assert.Len(t, res, 0)
 }
+
+func TestRepository_Indexes(t *testing.T) {
+	if os.Getenv("INTEGRATION_TEST") != "YES" {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo, cleanup := newIsolatedRepository(t)
+	defer cleanup()
+
+	// existing indexes are kept as is
+	assert.NoError(t, repo.createIndexes(ctx))
+
+	cursor, err := repo.collection.Indexes().List(ctx)
+	assert.NoError(t, err)
+	var indexes []bson.M
+	assert.NoError(t, cursor.All(ctx, &indexes))
+
+	byName := make(map[string]bson.M, len(indexes))
+	for _, index := range indexes {
+		byName[index["name"].(string)] = index
+	}
+	assert.Contains(t, byName, "uploadAt_1__id_1")
+	assert.Contains(t, byName, "size_1__id_1")
+	assert.Contains(t, byName, "sizes.path_1")
+	// only shared images are unique by the hash
+	assert.Equal(t, true, byName["hash_1"]["unique"])
+	assert.Equal(t, bson.M{"shared": true}, byName["hash_1"]["partialFilterExpression"])
+}
+
+func TestRepository_Conformance(t *testing.T) {
+	if os.Getenv("INTEGRATION_TEST") != "YES" {
+		t.Skip()
+	}
+
+	servicetest.TestRepository(t, func(t *testing.T) (service.Repository, func()) {
+		return newIsolatedRepository(t)
+	})
+}
+
+func TestJobRepository_Conformance(t *testing.T) {
+	if os.Getenv("INTEGRATION_TEST") != "YES" {
+		t.Skip()
+	}
+
+	servicetest.TestJobRepository(t, func(t *testing.T) (service.JobRepository, func()) {
+		return newIsolatedRepository(t)
+	})
+}
+
+func TestPresetRepository_Conformance(t *testing.T) {
+	if os.Getenv("INTEGRATION_TEST") != "YES" {
+		t.Skip()
+	}
+
+	servicetest.TestPresetRepository(t, func(t *testing.T) (service.PresetRepository, func()) {
+		return newIsolatedRepository(t)
+	})
+}
+
+func TestWatermarkRepository_Conformance(t *testing.T) {
+	if os.Getenv("INTEGRATION_TEST") != "YES" {
+		t.Skip()
+	}
+
+	servicetest.TestWatermarkRepository(t, func(t *testing.T) (service.WatermarkRepository, func()) {
+		return newIsolatedRepository(t)
+	})
+}
+
+// newIsolatedRepository returns a repository of its own database, so checks start empty.
+func newIsolatedRepository(t *testing.T) (*Repository, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	repo, err := New(ctx, uri, database+"_"+strings.Replace(uuid.NewV4().String(), "-", "", -1))
+	assert.NoError(t, err)
+
+	return repo, func() {
+		assert.NoError(t, repo.collection.Database().Drop(context.Background()))
+		cancel()
+	}
+}
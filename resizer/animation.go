@@ -0,0 +1,217 @@
+package resizer
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+)
+
+const (
+	gifExtension  = 0x21
+	gifDescriptor = 0x2c
+	gifTrailer    = 0x3b
+
+	maxPaletteSize = 256
+)
+
+var errLastFrame = errors.New("last frame")
+
+type animation struct {
+	image.Image
+	gif *gif.GIF
+}
+
+func newAnimation(g *gif.GIF) *animation {
+	a := &animation{gif: g}
+	a.Image = a.frame(0)
+
+	return a
+}
+
+// frame returns the last frame for indexes past the last one.
+func (a *animation) frame(n int) image.Image {
+	if a.Image != nil && n == 0 {
+		return a.Image
+	}
+	if last := len(a.gif.Image) - 1; n > last {
+		n = last
+	}
+
+	var res image.Image
+	_ = a.frames(func(i int, canvas *image.NRGBA) error {
+		if i < n {
+			return nil
+		}
+		res = imaging.Clone(canvas)
+		return errLastFrame
+	})
+
+	return res
+}
+
+// frames reuses the canvas, so the function mustn't keep it.
+func (a *animation) frames(fn func(i int, canvas *image.NRGBA) error) error {
+	canvas := image.NewNRGBA(image.Rect(0, 0, a.gif.Config.Width, a.gif.Config.Height))
+	var previous *image.NRGBA
+	for i, frame := range a.gif.Image {
+		var disposal byte
+		if i < len(a.gif.Disposal) {
+			disposal = a.gif.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = imaging.Clone(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if err := fn(i, canvas); err != nil {
+			return err
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return nil
+}
+
+// encodeAnimation keeps delays and disposal methods of the frames, the rendered frames cover the whole canvas,
+// so the disposal leaves the same canvas for the next frame as it does in the original.
+func encodeAnimation(
+	ctx context.Context,
+	a *animation,
+	output io.Writer,
+	keepColors bool,
+	render func(frame image.Image) (image.Image, error),
+) error {
+	res := &gif.GIF{
+		Image:     make([]*image.Paletted, 0, len(a.gif.Image)),
+		Delay:     a.gif.Delay,
+		Disposal:  a.gif.Disposal,
+		LoopCount: a.gif.LoopCount,
+	}
+
+	err := a.frames(func(i int, canvas *image.NRGBA) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rendered, err := render(canvas)
+		if err != nil {
+			return err
+		}
+
+		bounds := rendered.Bounds()
+		frame := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), framePalette(a.gif.Image[i].Palette, keepColors))
+		draw.FloydSteinberg.Draw(frame, frame.Bounds(), rendered, bounds.Min)
+		res.Image = append(res.Image, frame)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return gif.EncodeAll(output, res)
+}
+
+func framePalette(source color.Palette, keepColors bool) color.Palette {
+	p := source
+	if !keepColors {
+		p = palette.WebSafe
+	}
+
+	for _, c := range p {
+		if _, _, _, a := c.RGBA(); a == 0 {
+			return p
+		}
+	}
+	if len(p) < maxPaletteSize {
+		return append(p[:len(p):len(p)], color.Transparent)
+	}
+
+	return p
+}
+
+func keepsColors(request model.SizeRequest) bool {
+	if request.ResizeMode() == model.ResizeModePad || request.Watermark != "" {
+		return false
+	}
+	for _, operation := range request.Pipeline() {
+		if operation.Name != model.OperationResize {
+			return false
+		}
+	}
+
+	return true
+}
+
+func countFrames(data []byte) (int, error) {
+	const (
+		headerSize     = 6
+		screenSize     = 7
+		descriptorSize = 9
+	)
+
+	pos := headerSize + screenSize
+	if len(data) < pos {
+		return 0, io.ErrUnexpectedEOF
+	}
+	pos += colorTableSize(data[pos-3])
+
+	frames := 0
+	for pos < len(data) {
+		block := data[pos]
+		pos++
+
+		switch block {
+		case gifExtension:
+			// the label precedes sub-blocks
+			pos++
+		case gifDescriptor:
+			if pos+descriptorSize > len(data) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			// the LZW minimum code size follows the local colour table
+			pos += descriptorSize + colorTableSize(data[pos+descriptorSize-1]) + 1
+			frames++
+		case gifTrailer:
+			return frames, nil
+		default:
+			return 0, errors.New("gif: unknown block")
+		}
+
+		// sub-blocks are terminated by an empty one
+		for {
+			if pos >= len(data) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			size := int(data[pos])
+			pos += size + 1
+			if size == 0 {
+				break
+			}
+		}
+	}
+
+	return 0, io.ErrUnexpectedEOF
+}
+
+func colorTableSize(fields byte) int {
+	if fields&0x80 == 0 {
+		return 0
+	}
+
+	return 3 * (1 << ((fields & 0x07) + 1))
+}
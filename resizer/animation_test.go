@@ -0,0 +1,197 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	red   = color.RGBA{R: 0xff, A: 0xff}
+	green = color.RGBA{G: 0xff, A: 0xff}
+	blue  = color.RGBA{B: 0xff, A: 0xff}
+)
+
+// animatedGIF returns a 40x20 animation: the red canvas, the green right half which is cleared after it's displayed
+// and the blue top left corner.
+func animatedGIF(t *testing.T) []byte {
+	p := color.Palette{red, green, blue, color.Transparent}
+	frame := func(rect image.Rectangle, index uint8) *image.Paletted {
+		img := image.NewPaletted(rect, p)
+		for i := range img.Pix {
+			img.Pix[i] = index
+		}
+		return img
+	}
+
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image: []*image.Paletted{
+			frame(image.Rect(0, 0, 40, 20), 0),
+			frame(image.Rect(20, 0, 40, 20), 1),
+			frame(image.Rect(0, 0, 10, 10), 2),
+		},
+		Delay:    []int{10, 20, 30},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone},
+	})
+	assert.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func assertColor(t *testing.T, expected color.Color, img image.Image, x, y int) {
+	er, eg, eb, ea := expected.RGBA()
+	ar, ag, ab, aa := img.At(img.Bounds().Min.X+x, img.Bounds().Min.Y+y).RGBA()
+	if ea == 0 {
+		assert.Zero(t, aa, "alpha at %d,%d", x, y)
+		return
+	}
+	assert.Equal(t, [4]uint32{er, eg, eb, ea}, [4]uint32{ar, ag, ab, aa}, "colour at %d,%d", x, y)
+}
+
+func TestResizer_ResizeAnimation(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	original, err := r.Decode(ctx, bytes.NewReader(animatedGIF(t)))
+	assert.NoError(t, err)
+	// the first frame is displayed as a still image
+	assert.Equal(t, image.Rect(0, 0, 40, 20), original.Bounds())
+	assertColor(t, red, original, 30, 10)
+
+	var output bytes.Buffer
+	assert.NoError(t, r.Resize(ctx, original, &output, model.SizeRequest{Width: 20, Height: 10, Format: model.FormatGIF}))
+
+	resized, err := gif.DecodeAll(&output)
+	assert.NoError(t, err)
+	assert.Len(t, resized.Image, 3)
+	assert.Equal(t, []int{10, 20, 30}, resized.Delay)
+	assert.Equal(t, []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone}, resized.Disposal)
+	for _, frame := range resized.Image {
+		assert.Equal(t, image.Rect(0, 0, 20, 10), frame.Bounds())
+	}
+	assertColor(t, red, resized.Image[0], 15, 5)
+	assertColor(t, green, resized.Image[1], 15, 5)
+	assertColor(t, red, resized.Image[1], 5, 5)
+	// the green half is cleared by the disposal
+	assertColor(t, color.Transparent, resized.Image[2], 15, 5)
+	assertColor(t, blue, resized.Image[2], 1, 1)
+}
+
+func TestResizer_ResizeAnimationPoster(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	original, err := r.Decode(ctx, bytes.NewReader(animatedGIF(t)))
+	assert.NoError(t, err)
+
+	f := func(request model.SizeRequest, x, y int, expected color.Color) {
+		var output bytes.Buffer
+		assert.NoError(t, r.Resize(ctx, original, &output, request))
+
+		img, err := imaging.Decode(&output)
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 20, 10), img.Bounds())
+		assertColor(t, expected, img, x, y)
+	}
+
+	f(model.SizeRequest{Width: 20, Height: 10, Format: model.FormatPNG}, 15, 5, red)
+	f(model.SizeRequest{Width: 20, Height: 10, Format: model.FormatPNG, Frame: 1}, 15, 5, green)
+	// frames past the last one render the last frame
+	f(model.SizeRequest{Width: 20, Height: 10, Format: model.FormatPNG, Frame: 5}, 15, 5, color.Transparent)
+	f(model.SizeRequest{Width: 20, Height: 10, Format: model.FormatPNG, Frame: 5}, 1, 1, blue)
+}
+
+func TestResizer_ResizeAnimationSmart(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	original, err := r.Decode(ctx, bytes.NewReader(animatedGIF(t)))
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	assert.NoError(t, r.Resize(ctx, original, &output, model.SizeRequest{
+		Width:  10,
+		Height: 10,
+		Mode:   model.ResizeModeFill,
+		Anchor: model.AnchorSmart,
+		Format: model.FormatGIF,
+	}))
+
+	resized, err := gif.DecodeAll(&output)
+	assert.NoError(t, err)
+	assert.Len(t, resized.Image, 3)
+	// the flat first frame places the window in the centre for all frames
+	assertColor(t, red, resized.Image[1], 2, 5)
+	assertColor(t, green, resized.Image[1], 7, 5)
+}
+
+func TestResizer_EditAnimation(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	original, err := r.Decode(ctx, bytes.NewReader(animatedGIF(t)))
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	assert.NoError(t, r.Edit(ctx, original, &output, model.Edit{Rotate: 90}, model.FormatGIF))
+
+	edited, err := gif.DecodeAll(&output)
+	assert.NoError(t, err)
+	assert.Len(t, edited.Image, 3)
+	assert.Equal(t, []int{10, 20, 30}, edited.Delay)
+	assert.Equal(t, image.Rect(0, 0, 20, 40), edited.Image[1].Bounds())
+	// the right half is at the bottom after the clockwise rotation
+	assertColor(t, green, edited.Image[1], 10, 30)
+	assertColor(t, blue, edited.Image[2], 15, 5)
+}
+
+func TestResizer_StripMetadataAnimation(t *testing.T) {
+	r := New(0)
+
+	var output bytes.Buffer
+	err := r.StripMetadata(context.Background(), bytes.NewReader(animatedGIF(t)), &output,
+		model.FormatGIF, model.MetadataPolicyStripAll, model.MetadataMethodReencode)
+	assert.NoError(t, err)
+
+	stripped, err := gif.DecodeAll(&output)
+	assert.NoError(t, err)
+	assert.Len(t, stripped.Image, 3)
+	assert.Equal(t, []int{10, 20, 30}, stripped.Delay)
+}
+
+func TestResizer_AnimationPixelsLimit(t *testing.T) {
+	ctx := context.Background()
+	content := animatedGIF(t)
+
+	// the limit covers the canvases of all frames
+	_, err := New(40*20*2).Decode(ctx, bytes.NewReader(content))
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	original, err := New(40*20*3).Decode(ctx, bytes.NewReader(content))
+	assert.NoError(t, err)
+
+	err = New(40*20*3).Resize(ctx, original, &bytes.Buffer{}, model.SizeRequest{Width: 80, Height: 40, Format: model.FormatGIF})
+	assert.Equal(t, errors.ImageTooLarge, err)
+}
+
+func TestCountFrames(t *testing.T) {
+	content := animatedGIF(t)
+
+	frames, err := countFrames(content)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, frames)
+
+	_, err = countFrames(content[:len(content)-10])
+	assert.Error(t, err)
+	_, err = countFrames(content[:8])
+	assert.Error(t, err)
+}
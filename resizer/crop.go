@@ -0,0 +1,169 @@
+package resizer
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+)
+
+const analysisSize = 256
+
+func smartFill(img image.Image, width, height int, focal *model.FocalPoint) *image.NRGBA {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return imaging.New(width, height, image.Transparent)
+	}
+	cropped := imaging.Crop(img, smartWindow(img, width, height, focal).Add(bounds.Min))
+
+	return imaging.Resize(cropped, width, height, imaging.Lanczos)
+}
+
+// smartFocus lets frames of an animation be cropped alike.
+func smartFocus(img image.Image, width, height int) *model.FocalPoint {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil
+	}
+	window := smartWindow(img, width, height, nil)
+
+	return &model.FocalPoint{
+		X: float64(window.Min.X+window.Dx()/2) / float64(bounds.Dx()),
+		Y: float64(window.Min.Y+window.Dy()/2) / float64(bounds.Dy()),
+	}
+}
+
+func smartWindow(img image.Image, width, height int, focal *model.FocalPoint) image.Rectangle {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	// the window covers the whole image along one axis and slides along the other one
+	horizontal := srcWidth*height > srcHeight*width
+	window := srcWidth
+	if horizontal {
+		window = roundDiv(srcHeight*width, height)
+	} else {
+		window = roundDiv(srcWidth*height, width)
+	}
+	window = max(window, 1)
+
+	var offset int
+	switch {
+	case horizontal && focal != nil:
+		offset = centredOffset(focal.X*float64(srcWidth), window, srcWidth)
+	case focal != nil:
+		offset = centredOffset(focal.Y*float64(srcHeight), window, srcHeight)
+	default:
+		offset = busiestOffset(img, window, horizontal)
+	}
+
+	if horizontal {
+		return image.Rect(offset, 0, offset+window, srcHeight)
+	}
+
+	return image.Rect(0, offset, srcWidth, offset+window)
+}
+
+func centredOffset(point float64, window, length int) int {
+	return clamp(int(point+0.5)-window/2, 0, length-window)
+}
+
+// busiestOffset prefers the centre among windows with equal energy, e.g. of flat images.
+func busiestOffset(img image.Image, window int, horizontal bool) int {
+	bounds := img.Bounds()
+	length := bounds.Dy()
+	if horizontal {
+		length = bounds.Dx()
+	}
+	if window >= length {
+		return 0
+	}
+
+	small := imaging.Fit(img, analysisSize, analysisSize, imaging.Box)
+	energy := edgeEnergy(small, horizontal)
+	scale := float64(len(energy)) / float64(length)
+	smallWindow := clamp(int(float64(window)*scale+0.5), 1, len(energy))
+
+	var sum int64
+	for _, e := range energy[:smallWindow] {
+		sum += e
+	}
+	centre := len(energy) - smallWindow
+	best, bestSum := 0, sum
+	for offset := 1; offset+smallWindow <= len(energy); offset++ {
+		sum += energy[offset+smallWindow-1] - energy[offset-1]
+		if sum > bestSum || sum == bestSum && abs(2*offset-centre) < abs(2*best-centre) {
+			best, bestSum = offset, sum
+		}
+	}
+
+	return clamp(int(float64(best)/scale+0.5), 0, length-window)
+}
+
+func edgeEnergy(img *image.NRGBA, horizontal bool) []int64 {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	luminance := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := img.Pix[y*img.Stride+x*4:]
+			luminance[y*width+x] = (299*int(p[0]) + 587*int(p[1]) + 114*int(p[2])) * int(p[3]) / 255000
+		}
+	}
+
+	var energy []int64
+	if horizontal {
+		energy = make([]int64, width)
+	} else {
+		energy = make([]int64, height)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			l := luminance[y*width+x]
+			var gradient int
+			if x+1 < width {
+				gradient += abs(luminance[y*width+x+1] - l)
+			}
+			if y+1 < height {
+				gradient += abs(luminance[(y+1)*width+x] - l)
+			}
+			if horizontal {
+				energy[x] += int64(gradient)
+			} else {
+				energy[y] += int64(gradient)
+			}
+		}
+	}
+
+	return energy
+}
+
+func roundDiv(a, b int) int {
+	return (2*a + b) / (2 * b)
+}
+
+func clamp(value, min, max int) int {
+	if value > max {
+		value = max
+	}
+	if value < min {
+		value = min
+	}
+
+	return value
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
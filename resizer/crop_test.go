@@ -0,0 +1,113 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkered(width, height int, detail image.Rectangle) *image.NRGBA {
+	img := imaging.New(width, height, color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	for y := detail.Min.Y; y < detail.Max.Y; y++ {
+		for x := detail.Min.X; x < detail.Max.X; x++ {
+			if (x/4+y/4)%2 == 0 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+
+	return img
+}
+
+// flat reports whether the rendered image is plain gray, i.e. the detail is cropped out.
+func flat(img image.Image) bool {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			if r>>8 < 100 || r>>8 > 156 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func TestResizer_ResizeSmart(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	resize := func(original image.Image, request model.SizeRequest) image.Image {
+		request.Mode = model.ResizeModeFill
+		request.Anchor = model.AnchorSmart
+		request.Format = model.FormatPNG
+
+		output := bytes.Buffer{}
+		assert.NoError(t, r.Resize(ctx, original, &output, request))
+
+		img, err := imaging.Decode(&output)
+		assert.NoError(t, err)
+		assert.Equal(t, request.Width, img.Bounds().Dx())
+		assert.Equal(t, request.Height, img.Bounds().Dy())
+
+		return img
+	}
+
+	// the detail on the right is kept, the centre crop loses it
+	wide := checkered(400, 100, image.Rect(320, 0, 400, 100))
+	assert.False(t, flat(resize(wide, model.SizeRequest{Width: 100, Height: 100})))
+	assert.True(t, flat(imaging.Fill(wide, 100, 100, imaging.Center, imaging.Lanczos)))
+
+	// the detail at the top of the tall image
+	tall := checkered(100, 400, image.Rect(0, 0, 100, 60))
+	assert.False(t, flat(resize(tall, model.SizeRequest{Width: 100, Height: 50})))
+
+	// the focal point wins over the detail
+	focal := resize(wide, model.SizeRequest{Width: 100, Height: 100, FocalPoint: &model.FocalPoint{X: 0.1, Y: 0.5}})
+	assert.True(t, flat(focal))
+
+	// the focal point near the edge is clamped to the image
+	edge := resize(wide, model.SizeRequest{Width: 100, Height: 100, FocalPoint: &model.FocalPoint{X: 1, Y: 0.5}})
+	assert.False(t, flat(edge))
+
+	// the same aspect ratio only scales the image
+	same := resize(wide, model.SizeRequest{Width: 200, Height: 50})
+	assert.False(t, flat(same))
+}
+
+func TestBusiestOffset(t *testing.T) {
+	f := func(img image.Image, window int, horizontal bool, expected int) {
+		assert.Equal(t, expected, busiestOffset(img, window, horizontal))
+	}
+
+	// flat images are cropped at the centre
+	f(checkered(400, 100, image.Rectangle{}), 100, true, 150)
+	f(checkered(100, 400, image.Rectangle{}), 100, false, 150)
+	// the window fits the whole detail
+	f(checkered(400, 100, image.Rect(0, 0, 80, 100)), 100, true, 0)
+	f(checkered(400, 100, image.Rect(320, 0, 400, 100)), 100, true, 300)
+	// the window covers the whole image
+	f(checkered(100, 100, image.Rect(0, 0, 50, 50)), 100, true, 0)
+}
+
+func TestSmartFocus(t *testing.T) {
+	f := func(img image.Image, width, height int) {
+		window := smartWindow(img, width, height, nil)
+		assert.Equal(t, window, smartWindow(img, width, height, smartFocus(img, width, height)))
+	}
+
+	// the focal point places the window where the edges do
+	f(checkered(400, 100, image.Rect(320, 0, 400, 100)), 100, 100)
+	f(checkered(101, 400, image.Rect(0, 30, 101, 90)), 50, 25)
+	f(checkered(400, 100, image.Rectangle{}), 33, 10)
+	f(checkered(100, 100, image.Rectangle{}), 100, 100)
+}
@@ -0,0 +1,68 @@
+package resizer
+
+import (
+	"context"
+	"image"
+	"io"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+)
+
+// originalJPEGQuality is high enough to keep repeated edits from degrading derived originals.
+const originalJPEGQuality = 95
+
+func (r *Resizer) Edit(ctx context.Context, img image.Image, output io.Writer, edit model.Edit, format model.Format) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if a, ok := img.(*animation); ok {
+		if format == model.FormatGIF {
+			return editAnimation(ctx, a, output, edit)
+		}
+		img = a.Image
+	}
+
+	return toServiceErr(imaging.Encode(output, applyEdit(img, edit), formats[format], imaging.JPEGQuality(originalJPEGQuality)))
+}
+
+func editAnimation(ctx context.Context, a *animation, output io.Writer, edit model.Edit) error {
+	err := encodeAnimation(ctx, a, output, true, func(frame image.Image) (image.Image, error) {
+		return applyEdit(frame, edit), nil
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		return toServiceErr(err)
+	}
+
+	return nil
+}
+
+func applyEdit(img image.Image, edit model.Edit) image.Image {
+	// imaging rotates counter-clockwise
+	switch edit.Rotate {
+	case 90:
+		img = imaging.Rotate270(img)
+	case 180:
+		img = imaging.Rotate180(img)
+	case 270:
+		img = imaging.Rotate90(img)
+	}
+
+	switch edit.Flip {
+	case model.FlipAxisHorizontal:
+		img = imaging.FlipH(img)
+	case model.FlipAxisVertical:
+		img = imaging.FlipV(img)
+	}
+
+	if crop := edit.Crop; crop != nil {
+		rect := image.Rect(crop.X, crop.Y, crop.X+crop.Width, crop.Y+crop.Height)
+		img = imaging.Crop(img, rect.Add(img.Bounds().Min))
+	}
+
+	return img
+}
@@ -0,0 +1,59 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizer_Edit(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	// the red pixel marks the top left corner
+	original := imaging.New(4, 2, color.White)
+	original.Set(0, 0, color.NRGBA{R: 255, A: 255})
+
+	edit := func(edit model.Edit) image.Image {
+		output := bytes.Buffer{}
+		assert.NoError(t, r.Edit(ctx, original, &output, edit, model.FormatPNG))
+
+		img, err := imaging.Decode(&output)
+		assert.NoError(t, err)
+
+		return img
+	}
+	f := func(e model.Edit, size, red image.Point) {
+		img := edit(e)
+		assert.Equal(t, size, img.Bounds().Size(), e)
+
+		r, g, _, _ := img.At(red.X, red.Y).RGBA()
+		assert.Equal(t, [2]uint32{0xffff, 0}, [2]uint32{r, g}, e)
+	}
+
+	f(model.Edit{Rotate: 90}, image.Pt(2, 4), image.Pt(1, 0))
+	f(model.Edit{Rotate: 180}, image.Pt(4, 2), image.Pt(3, 1))
+	f(model.Edit{Rotate: 270}, image.Pt(2, 4), image.Pt(0, 3))
+	f(model.Edit{Flip: model.FlipAxisHorizontal}, image.Pt(4, 2), image.Pt(3, 0))
+	f(model.Edit{Flip: model.FlipAxisVertical}, image.Pt(4, 2), image.Pt(0, 1))
+	f(model.Edit{Crop: &model.Rect{Width: 2, Height: 1}}, image.Pt(2, 1), image.Pt(0, 0))
+
+	// the crop is limited to the original
+	assert.Equal(t, image.Pt(1, 1), edit(model.Edit{Crop: &model.Rect{X: 3, Y: 1, Width: 5, Height: 5}}).Bounds().Size())
+}
+
+func TestResizer_EditCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	output := bytes.Buffer{}
+	err := New(0).Edit(ctx, imaging.New(10, 10, color.White), &output, model.Edit{Rotate: 90}, model.FormatJPEG)
+	assert.Equal(t, context.Canceled, err)
+	assert.Zero(t, output.Len())
+}
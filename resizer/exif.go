@@ -0,0 +1,262 @@
+package resizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/portey/image-resizer/model"
+)
+
+const (
+	markerSOI  = 0xd8
+	markerSOS  = 0xda
+	markerAPP1 = 0xe1
+
+	tagMake             = 0x010f
+	tagModel            = 0x0110
+	tagOrientation      = 0x0112
+	tagSoftware         = 0x0131
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagExposureTime     = 0x829a
+	tagFNumber          = 0x829d
+	tagISO              = 0x8827
+	tagDateTimeOriginal = 0x9003
+	tagFocalLength      = 0x920a
+	tagLensModel        = 0xa434
+
+	typeASCII    = 2
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+
+	ifdEntrySize = 12
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+var typeSizes = map[uint16]uint64{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+func jpegExif(data []byte) []byte {
+	if len(data) < 2 || data[0] != 0xff || data[1] != markerSOI {
+		return nil
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xff {
+			return nil
+		}
+		marker := data[pos+1]
+		if marker == 0xff {
+			// fill byte
+			pos++
+			continue
+		}
+		if marker == markerSOS {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		segment := data[pos+4 : end]
+		if marker == markerAPP1 && bytes.HasPrefix(segment, exifHeader) {
+			return segment[len(exifHeader):]
+		}
+		pos = end
+	}
+
+	return nil
+}
+
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	// value is the offset of the value or the value itself if it fits into 4 bytes
+	value []byte
+}
+
+func newTIFFReader(data []byte) *tiffReader {
+	if len(data) < 8 {
+		return nil
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+	if order.Uint16(data[2:]) != 42 {
+		return nil
+	}
+
+	return &tiffReader{data: data, order: order}
+}
+
+func (r *tiffReader) ifd(offset uint32) []ifdEntry {
+	if uint64(offset)+2 > uint64(len(r.data)) {
+		return nil
+	}
+
+	count := int(r.order.Uint16(r.data[offset:]))
+	entries := make([]ifdEntry, 0, count)
+	for i := 0; i < count; i++ {
+		pos := int(offset) + 2 + i*ifdEntrySize
+		if pos+ifdEntrySize > len(r.data) {
+			break
+		}
+		entries = append(entries, ifdEntry{
+			tag:   r.order.Uint16(r.data[pos:]),
+			typ:   r.order.Uint16(r.data[pos+2:]),
+			count: r.order.Uint32(r.data[pos+4:]),
+			value: r.data[pos+8 : pos+12],
+		})
+	}
+
+	return entries
+}
+
+func (r *tiffReader) bytes(e ifdEntry) []byte {
+	size, ok := typeSizes[e.typ]
+	if !ok {
+		return nil
+	}
+	size *= uint64(e.count)
+
+	if size <= 4 {
+		return e.value[:size]
+	}
+	offset := uint64(r.order.Uint32(e.value))
+	if offset+size > uint64(len(r.data)) {
+		return nil
+	}
+
+	return r.data[offset : offset+size]
+}
+
+func (r *tiffReader) string(e ifdEntry) string {
+	if e.typ != typeASCII {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(r.bytes(e)), "\x00"))
+}
+
+func (r *tiffReader) uint(e ifdEntry) (uint32, bool) {
+	value := r.bytes(e)
+	switch {
+	case e.typ == typeShort && len(value) >= 2:
+		return uint32(r.order.Uint16(value)), true
+	case e.typ == typeLong && len(value) >= 4:
+		return r.order.Uint32(value), true
+	}
+
+	return 0, false
+}
+
+func (r *tiffReader) rational(e ifdEntry) (uint32, uint32, bool) {
+	value := r.bytes(e)
+	if e.typ != typeRational || len(value) < 8 {
+		return 0, 0, false
+	}
+
+	return r.order.Uint32(value), r.order.Uint32(value[4:]), true
+}
+
+func (r *tiffReader) float(e ifdEntry) float64 {
+	num, denom, ok := r.rational(e)
+	if !ok || denom == 0 {
+		return 0
+	}
+
+	return math.Round(float64(num)/float64(denom)*100) / 100
+}
+
+func readExif(data []byte) (int, *model.Exif) {
+	r := newTIFFReader(data)
+	if r == nil {
+		return 0, nil
+	}
+
+	var (
+		orientation int
+		exif        model.Exif
+		exifIFD     uint32
+	)
+	for _, e := range r.ifd(r.order.Uint32(data[4:])) {
+		switch e.tag {
+		case tagMake:
+			exif.Make = r.string(e)
+		case tagModel:
+			exif.Model = r.string(e)
+		case tagSoftware:
+			exif.Software = r.string(e)
+		case tagOrientation:
+			if value, ok := r.uint(e); ok && value >= 1 && value <= 8 {
+				orientation = int(value)
+			}
+		case tagExifIFD:
+			exifIFD, _ = r.uint(e)
+		case tagGPSIFD:
+			// stripped originals keep an empty GPS IFD
+			if offset, ok := r.uint(e); ok {
+				exif.HasGPS = len(r.ifd(offset)) > 0
+			}
+		}
+	}
+
+	if exifIFD != 0 {
+		for _, e := range r.ifd(exifIFD) {
+			switch e.tag {
+			case tagExposureTime:
+				if num, denom, ok := r.rational(e); ok && denom != 0 {
+					exif.ExposureTime = formatExposure(num, denom)
+				}
+			case tagFNumber:
+				exif.FNumber = r.float(e)
+			case tagISO:
+				if value, ok := r.uint(e); ok {
+					exif.ISO = int(value)
+				}
+			case tagDateTimeOriginal:
+				exif.DateTimeOriginal = r.string(e)
+			case tagFocalLength:
+				exif.FocalLength = r.float(e)
+			case tagLensModel:
+				exif.LensModel = r.string(e)
+			}
+		}
+	}
+
+	if exif == (model.Exif{}) {
+		return orientation, nil
+	}
+
+	return orientation, &exif
+}
+
+func formatExposure(num, denom uint32) string {
+	if num == 0 {
+		return "0"
+	}
+	if num < denom {
+		return "1/" + strconv.FormatFloat(math.Round(float64(denom)/float64(num)), 'f', -1, 64)
+	}
+
+	return strconv.FormatFloat(float64(num)/float64(denom), 'f', -1, 64)
+}
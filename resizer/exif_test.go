@@ -0,0 +1,142 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+func buildTIFF(ifd0, exifIFD, gpsIFD []testEntry) []byte {
+	order := binary.BigEndian
+	ifdSize := func(entries []testEntry) int { return 2 + len(entries)*ifdEntrySize + 4 }
+
+	ifd0 = append(ifd0, testEntry{tag: tagExifIFD, typ: typeLong, count: 1}, testEntry{tag: tagGPSIFD, typ: typeLong, count: 1})
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset + ifdSize(exifIFD)
+	dataOffset := gpsOffset + ifdSize(gpsIFD)
+	ifd0[len(ifd0)-2].value = uint32Bytes(uint32(exifOffset))
+	ifd0[len(ifd0)-1].value = uint32Bytes(uint32(gpsOffset))
+
+	var data []byte
+	writeIFD := func(buf *bytes.Buffer, entries []testEntry) {
+		_ = binary.Write(buf, order, uint16(len(entries)))
+		for _, e := range entries {
+			_ = binary.Write(buf, order, e.tag)
+			_ = binary.Write(buf, order, e.typ)
+			_ = binary.Write(buf, order, e.count)
+			value := e.value
+			if len(value) > 4 {
+				offset := dataOffset + len(data)
+				data = append(data, value...)
+				value = uint32Bytes(uint32(offset))
+			}
+			buf.Write(append(value, make([]byte, 4-len(value))...))
+		}
+		_ = binary.Write(buf, order, uint32(0))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("MM")
+	_ = binary.Write(&buf, order, uint16(42))
+	_ = binary.Write(&buf, order, uint32(8))
+	writeIFD(&buf, ifd0)
+	writeIFD(&buf, exifIFD)
+	writeIFD(&buf, gpsIFD)
+	buf.Write(data)
+
+	return buf.Bytes()
+}
+
+func uint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func rational(num, denom uint32) []byte {
+	return append(uint32Bytes(num), uint32Bytes(denom)...)
+}
+
+func ascii(value string) []byte {
+	return append([]byte(value), 0)
+}
+
+func withExif(jpeg, tiff []byte) []byte {
+	segment := append(append([]byte{}, exifHeader...), tiff...)
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(len(segment)+2))
+
+	res := append([]byte{}, jpeg[:2]...)
+	res = append(res, 0xff, markerAPP1)
+	res = append(res, length...)
+	res = append(res, segment...)
+
+	return append(res, jpeg[2:]...)
+}
+
+func TestResizer_DecodeMetadataExif(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, imaging.Encode(&buf, imaging.New(30, 20, color.White), imaging.JPEG))
+
+	tiff := buildTIFF([]testEntry{
+		{tag: tagMake, typ: typeASCII, count: 6, value: ascii("Canon")},
+		{tag: tagModel, typ: typeASCII, count: 9, value: ascii("EOS 80D ")},
+		{tag: tagOrientation, typ: typeShort, count: 1, value: []byte{0, 6}},
+	}, []testEntry{
+		{tag: tagExposureTime, typ: typeRational, count: 1, value: rational(10, 1250)},
+		{tag: tagFNumber, typ: typeRational, count: 1, value: rational(28, 10)},
+		{tag: tagISO, typ: typeShort, count: 1, value: []byte{0, 200}},
+		{tag: tagDateTimeOriginal, typ: typeASCII, count: 20, value: ascii("2020:05:01 10:00:00")},
+		{tag: tagFocalLength, typ: typeRational, count: 1, value: rational(50, 1)},
+	}, []testEntry{
+		{tag: tagGPSLatitudeRef, typ: typeASCII, count: 2, value: ascii("N")},
+	})
+
+	metadata, err := New(0).DecodeMetadata(context.Background(), bytes.NewReader(withExif(buf.Bytes(), tiff)))
+	assert.NoError(t, err)
+	assert.Equal(t, model.Metadata{
+		Format:      model.FormatJPEG,
+		Width:       20,
+		Height:      30,
+		Orientation: 6,
+		ColorModel:  model.ColorModelYCbCr,
+		Exif: &model.Exif{
+			Make:             "Canon",
+			Model:            "EOS 80D",
+			DateTimeOriginal: "2020:05:01 10:00:00",
+			ExposureTime:     "1/125",
+			FNumber:          2.8,
+			ISO:              200,
+			FocalLength:      50,
+			HasGPS:           true,
+		},
+	}, metadata)
+}
+
+func TestReadExif_Malformed(t *testing.T) {
+	f := func(data []byte) {
+		assert.NotPanics(t, func() {
+			readExif(jpegExif(data))
+			readExif(data)
+		})
+	}
+
+	f(nil)
+	f([]byte{0xff, 0xd8})
+	f([]byte{0xff, 0xd8, 0xff, 0xe1, 0xff, 0xff, 'E', 'x'})
+	f([]byte("MM\x00\x2a\xff\xff\xff\xff"))
+	f([]byte("II\x2a\x00\x08\x00\x00\x00\xff\xff\x01\x01\x02\x00\xff\xff\xff\xff\xff\xff\xff\xff"))
+}
@@ -0,0 +1,96 @@
+package resizer
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+)
+
+var effects = map[model.OperationName]func(img image.Image, value float64) *image.NRGBA{
+	model.OperationBlur:       imaging.Blur,
+	model.OperationSharpen:    imaging.Sharpen,
+	model.OperationGamma:      imaging.AdjustGamma,
+	model.OperationContrast:   imaging.AdjustContrast,
+	model.OperationBrightness: imaging.AdjustBrightness,
+	model.OperationSaturation: imaging.AdjustSaturation,
+	model.OperationGrayscale: func(img image.Image, _ float64) *image.NRGBA {
+		return imaging.Grayscale(img)
+	},
+	model.OperationInvert: func(img image.Image, _ float64) *image.NRGBA {
+		return imaging.Invert(img)
+	},
+}
+
+var radiusEffects = map[model.OperationName]bool{
+	model.OperationBlur:    true,
+	model.OperationSharpen: true,
+}
+
+// prescaleFactor is how many times the image downscaled for effects placed before the resize
+// exceeds the requested size on each side, so the resize still has pixels to sample.
+const prescaleFactor = 2
+
+// transform checks the context between operations. Effects can't be interrupted, so effects placed before
+// the resize are applied to the image downscaled close to the requested size.
+func transform(ctx context.Context, img image.Image, request model.SizeRequest) (image.Image, error) {
+	resized, prescaled := false, false
+	scale := 1.0
+	for _, operation := range request.Pipeline() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if operation.Name == model.OperationResize {
+			var err error
+			if img, err = resize(img, request); err != nil {
+				return nil, err
+			}
+			resized = true
+			continue
+		}
+
+		effect, ok := effects[operation.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown operation %q", operation.Name)
+		}
+		if !resized && !prescaled {
+			img, scale = prescale(img, request.Width, request.Height)
+			prescaled = true
+		}
+
+		value := operation.Value
+		if radiusEffects[operation.Name] {
+			value *= scale
+		}
+		img = effect(img, value)
+	}
+
+	if request.Watermark != "" {
+		if request.Overlay == nil {
+			return nil, fmt.Errorf("watermark %q isn't loaded", request.Watermark)
+		}
+		img = composite(img, request.Overlay)
+	}
+
+	return img, nil
+}
+
+func prescale(img image.Image, width, height int) (image.Image, float64) {
+	bounds := img.Bounds()
+	scale := math.Max(
+		float64(width*prescaleFactor)/float64(bounds.Dx()),
+		float64(height*prescaleFactor)/float64(bounds.Dy()),
+	)
+	if scale >= 1 {
+		return img, 1
+	}
+
+	scaledWidth := int(math.Ceil(float64(bounds.Dx()) * scale))
+	scaledHeight := int(math.Ceil(float64(bounds.Dy()) * scale))
+
+	return imaging.Resize(img, scaledWidth, scaledHeight, imaging.Lanczos), scale
+}
@@ -0,0 +1,88 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizer_ResizeOperations(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+	original := imaging.New(100, 50, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+
+	resize := func(operations ...model.Operation) color.NRGBA {
+		output := bytes.Buffer{}
+		request := model.SizeRequest{Width: 20, Height: 10, Format: model.FormatPNG, Operations: operations}
+		assert.NoError(t, r.Resize(ctx, original, &output, request))
+
+		img, err := imaging.Decode(&output)
+		assert.NoError(t, err)
+		assert.Equal(t, image.Pt(20, 10), img.Bounds().Size())
+
+		return color.NRGBAModel.Convert(img.At(5, 5)).(color.NRGBA)
+	}
+
+	assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 255}, resize())
+	assert.Equal(t, color.NRGBA{R: 55, G: 155, B: 205, A: 255}, resize(model.Operation{Name: model.OperationInvert}))
+
+	gray := resize(model.Operation{Name: model.OperationGrayscale})
+	assert.Equal(t, gray.R, gray.G)
+	assert.Equal(t, gray.G, gray.B)
+
+	// effects are applied in order, before or after the resize
+	assert.Equal(t,
+		resize(model.Operation{Name: model.OperationInvert}, model.Operation{Name: model.OperationGrayscale}),
+		resize(model.Operation{Name: model.OperationInvert}, model.Operation{Name: model.OperationResize}, model.Operation{Name: model.OperationGrayscale}),
+	)
+	assert.NotEqual(t,
+		resize(model.Operation{Name: model.OperationBrightness, Value: 50}, model.Operation{Name: model.OperationInvert}),
+		resize(model.Operation{Name: model.OperationInvert}, model.Operation{Name: model.OperationBrightness, Value: 50}),
+	)
+
+	bright := resize(model.Operation{Name: model.OperationBrightness, Value: 20})
+	assert.Greater(t, bright.G, uint8(100))
+	dark := resize(model.Operation{Name: model.OperationGamma, Value: 0.5})
+	assert.Less(t, dark.G, uint8(100))
+	saturated := resize(model.Operation{Name: model.OperationSaturation, Value: -100})
+	assert.Equal(t, saturated.R, saturated.B)
+	// flat images aren't changed by filters
+	assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 255}, resize(
+		model.Operation{Name: model.OperationBlur, Value: 2},
+		model.Operation{Name: model.OperationSharpen, Value: 1},
+	))
+}
+
+func TestResizer_ResizeOperationsFailed(t *testing.T) {
+	r := New(0)
+	original := imaging.New(100, 50, color.White)
+	request := model.SizeRequest{Width: 20, Height: 10, Operations: []model.Operation{{Name: "sepia"}}}
+
+	err := r.Resize(context.Background(), original, &bytes.Buffer{}, request)
+	assert.Equal(t, errors.Internal, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = r.Resize(ctx, original, &bytes.Buffer{}, request)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPrescale(t *testing.T) {
+	original := imaging.New(1000, 400, color.White)
+
+	// effects before the resize are applied to the image covering twice the requested size
+	img, scale := prescale(original, 20, 20)
+	assert.Equal(t, image.Pt(100, 40), img.Bounds().Size())
+	assert.Equal(t, 0.1, scale)
+
+	img, scale = prescale(original, 600, 100)
+	assert.Equal(t, original, img)
+	assert.Equal(t, 1.0, scale)
+}
@@ -1,30 +1,328 @@
 package resizer
 
 import (
+	"bytes"
 	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
 	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
 
 	"github.com/disintegration/imaging"
 	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
 	log "github.com/sirupsen/logrus"
 )
 
+var anchors = map[model.Anchor]imaging.Anchor{
+	model.AnchorCenter:      imaging.Center,
+	model.AnchorTopLeft:     imaging.TopLeft,
+	model.AnchorTop:         imaging.Top,
+	model.AnchorTopRight:    imaging.TopRight,
+	model.AnchorLeft:        imaging.Left,
+	model.AnchorRight:       imaging.Right,
+	model.AnchorBottomLeft:  imaging.BottomLeft,
+	model.AnchorBottom:      imaging.Bottom,
+	model.AnchorBottomRight: imaging.BottomRight,
+}
+
+var formats = map[model.Format]imaging.Format{
+	model.FormatJPEG: imaging.JPEG,
+	model.FormatPNG:  imaging.PNG,
+	model.FormatGIF:  imaging.GIF,
+	model.FormatBMP:  imaging.BMP,
+	model.FormatTIFF: imaging.TIFF,
+}
+
+var compressions = map[model.Compression]png.CompressionLevel{
+	model.CompressionDefault:   png.DefaultCompression,
+	model.CompressionNone:      png.NoCompression,
+	model.CompressionBestSpeed: png.BestSpeed,
+	model.CompressionBest:      png.BestCompression,
+}
+
 type Resizer struct {
+	// maxPixels limits the number of pixels of decoded images, zero doesn't limit
+	maxPixels int
+}
+
+func New(maxPixels int) *Resizer {
+	return &Resizer{maxPixels: maxPixels}
+}
+
+// Decode decodes the image after its dimensions are checked by the header,
+// so images exceeding the pixels limit aren't decoded into memory.
+func (r *Resizer) Decode(ctx context.Context, data io.Reader) (image.Image, error) {
+	var header bytes.Buffer
+	config, name, err := image.DecodeConfig(io.TeeReader(data, &header))
+	if err != nil {
+		return nil, toServiceErr(err)
+	}
+	if err := r.checkPixels(config.Width, config.Height, 1); err != nil {
+		return nil, err
+	}
+	if model.Format(name) == model.FormatGIF {
+		return r.decodeGIF(io.MultiReader(&header, data), config)
+	}
+
+	img, err := imaging.Decode(io.MultiReader(&header, data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, toServiceErr(err)
+	}
+
+	return img, nil
+}
+
+// decodeGIF renders every frame on the whole canvas, so the pixels limit covers the canvases of all frames.
+func (r *Resizer) decodeGIF(data io.Reader, config image.Config) (image.Image, error) {
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		return nil, toServiceErr(err)
+	}
+	frames, err := countFrames(content)
+	if err != nil {
+		return nil, toServiceErr(err)
+	}
+	if frames <= 1 {
+		img, err := imaging.Decode(bytes.NewReader(content))
+		if err != nil {
+			return nil, toServiceErr(err)
+		}
+
+		return img, nil
+	}
+	if err := r.checkPixels(config.Width, config.Height, frames); err != nil {
+		return nil, err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(content))
+	if err != nil {
+		return nil, toServiceErr(err)
+	}
+
+	return newAnimation(g), nil
+}
+
+func (r *Resizer) checkPixels(width, height, frames int) error {
+	if r.maxPixels > 0 && int64(width)*int64(height)*int64(frames) > int64(r.maxPixels) {
+		log.Debug("image is too large ", width, "x", height, "x", frames)
+		return errors.ImageTooLarge
+	}
+
+	return nil
+}
+
+func (r *Resizer) DecodeMetadata(ctx context.Context, data io.Reader) (model.Metadata, error) {
+	var header bytes.Buffer
+	config, name, err := image.DecodeConfig(io.TeeReader(data, &header))
+	if err != nil {
+		log.Debug("can't decode image config ", err)
+		return model.Metadata{}, errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+
+	format := model.Format(name)
+	if format.MimeType() == "" {
+		return model.Metadata{}, errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+	if err := r.checkPixels(config.Width, config.Height, 1); err != nil {
+		return model.Metadata{}, err
+	}
+
+	metadata := model.Metadata{
+		Format: format,
+		Width:  config.Width,
+		Height: config.Height,
+	}
+	switch format {
+	case model.FormatPNG:
+		metadata.ColorModel, metadata.HasAlpha = pngColorModel(header.Bytes(), config)
+	case model.FormatJPEG:
+		metadata.ColorModel, metadata.HasAlpha = colorModel(config.ColorModel)
+		// the EXIF segment precedes the frame header, so it's read together with the config
+		metadata.Orientation, metadata.Exif = readExif(jpegExif(header.Bytes()))
+	default:
+		metadata.ColorModel, metadata.HasAlpha = colorModel(config.ColorModel)
+	}
+	if metadata.Orientation >= 5 {
+		// the original is rotated by 90 degrees when it's displayed
+		metadata.Width, metadata.Height = metadata.Height, metadata.Width
+	}
+
+	return metadata, nil
 }
 
-func New() *Resizer {
-	return &Resizer{}
+func colorModel(m color.Model) (model.ColorModel, bool) {
+	if palette, ok := m.(color.Palette); ok {
+		for _, c := range palette {
+			if _, _, _, a := c.RGBA(); a != 0xffff {
+				return model.ColorModelPaletted, true
+			}
+		}
+
+		return model.ColorModelPaletted, false
+	}
+
+	switch m {
+	case color.RGBAModel, color.RGBA64Model, color.NRGBAModel, color.NRGBA64Model:
+		return model.ColorModelRGBA, true
+	case color.AlphaModel, color.Alpha16Model:
+		return model.ColorModelGrayAlpha, true
+	case color.GrayModel, color.Gray16Model:
+		return model.ColorModelGray, false
+	case color.YCbCrModel:
+		return model.ColorModelYCbCr, false
+	case color.NYCbCrAModel:
+		return model.ColorModelYCbCr, true
+	case color.CMYKModel:
+		return model.ColorModelCMYK, false
+	}
+
+	return "", false
+}
+
+// pngColorModel reads the colour type of the header, the decoder reports colour types without alpha
+// as RGBA and gray with alpha as NRGBA.
+func pngColorModel(header []byte, config image.Config) (model.ColorModel, bool) {
+	// signature, IHDR length and type, width, height and bit depth precede the colour type
+	const colorTypeOffset = 25
+	if len(header) <= colorTypeOffset {
+		return colorModel(config.ColorModel)
+	}
+
+	switch header[colorTypeOffset] {
+	case 0:
+		return model.ColorModelGray, false
+	case 2:
+		return model.ColorModelRGB, false
+	case 4:
+		return model.ColorModelGrayAlpha, true
+	case 6:
+		return model.ColorModelRGBA, true
+	}
+
+	return colorModel(config.ColorModel)
 }
 
-func (r *Resizer) Resize(ctx context.Context, data io.Reader, output io.Writer, width, height int) error {
-	img, err := imaging.Decode(data, imaging.AutoOrientation(true))
+// Resize doesn't modify the original, so it's safe to resize the same original concurrently.
+func (r *Resizer) Resize(ctx context.Context, img image.Image, output io.Writer, request model.SizeRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if a, ok := img.(*animation); ok {
+		if request.OutputFormat() == model.FormatGIF {
+			return r.resizeAnimation(ctx, a, output, request)
+		}
+		img = a.frame(request.PosterFrame())
+	}
+
+	resized, err := transform(ctx, img, request)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
 		return toServiceErr(err)
 	}
 
-	resized := imaging.Resize(img, width, height, imaging.Lanczos)
+	return toServiceErr(encode(output, resized, request))
+}
+
+// resizeAnimation places smart crops by the first frame, so all frames are cropped alike.
+func (r *Resizer) resizeAnimation(ctx context.Context, a *animation, output io.Writer, request model.SizeRequest) error {
+	if err := r.checkPixels(request.Width, request.Height, len(a.gif.Image)); err != nil {
+		return err
+	}
+	if request.ResizeMode() == model.ResizeModeFill && request.CropAnchor() == model.AnchorSmart && request.FocalPoint == nil {
+		request.FocalPoint = smartFocus(a.Image, request.Width, request.Height)
+	}
+
+	err := encodeAnimation(ctx, a, output, keepsColors(request), func(frame image.Image) (image.Image, error) {
+		return transform(ctx, frame, request)
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		return toServiceErr(err)
+	}
+
+	return nil
+}
+
+func encode(output io.Writer, img image.Image, request model.SizeRequest) error {
+	var options []imaging.EncodeOption
+	switch request.OutputFormat() {
+	case model.FormatJPEG:
+		options = append(options, imaging.JPEGQuality(request.JPEGQuality()))
+	case model.FormatPNG:
+		options = append(options, imaging.PNGCompressionLevel(compressions[request.PNGCompression()]))
+	}
+
+	return imaging.Encode(output, img, formats[request.OutputFormat()], options...)
+}
+
+func resize(img image.Image, request model.SizeRequest) (image.Image, error) {
+	switch request.ResizeMode() {
+	case model.ResizeModeFit:
+		return fit(img, request.Width, request.Height), nil
+	case model.ResizeModeFill:
+		if request.CropAnchor() == model.AnchorSmart {
+			return smartFill(img, request.Width, request.Height, request.FocalPoint), nil
+		}
+
+		return imaging.Fill(img, request.Width, request.Height, anchors[request.CropAnchor()], imaging.Lanczos), nil
+	case model.ResizeModePad:
+		background, err := parseHexColor(request.PadBackground())
+		if err != nil {
+			return nil, err
+		}
+
+		return imaging.PasteCenter(
+			imaging.New(request.Width, request.Height, background),
+			fit(img, request.Width, request.Height),
+		), nil
+	case model.ResizeModeLimit:
+		return imaging.Fit(img, request.Width, request.Height, imaging.Lanczos), nil
+	default:
+		return imaging.Resize(img, request.Width, request.Height, imaging.Lanczos), nil
+	}
+}
+
+// fit upscales images smaller than the box unlike imaging.Fit.
+func fit(img image.Image, width, height int) *image.NRGBA {
+	bounds := img.Bounds()
+	if bounds.Dx()*height > bounds.Dy()*width {
+		return imaging.Resize(img, width, 0, imaging.Lanczos)
+	}
+
+	return imaging.Resize(img, 0, height, imaging.Lanczos)
+}
+
+func parseHexColor(hex string) (color.Color, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, err
+	}
+	if len(hex) != 6 {
+		return nil, strconv.ErrSyntax
+	}
 
-	return toServiceErr(imaging.Encode(output, resized, imaging.PNG))
+	return color.NRGBA{
+		R: uint8(value >> 16),
+		G: uint8(value >> 8),
+		B: uint8(value),
+		A: 0xff,
+	}, nil
 }
 
 func toServiceErr(err error) error {
@@ -3,22 +3,29 @@ package resizer
 import (
 	"bytes"
 	"context"
+	"image"
+	"image/color"
 	"os"
 	"testing"
 
 	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestResizer_Resize(t *testing.T) {
-	r := New()
+	r := New(0)
 	ctx := context.Background()
 
 	file, err := os.Open("./fixtures/image.jpg")
 	assert.NoError(t, err)
 
+	original, err := r.Decode(ctx, file)
+	assert.NoError(t, err)
+
 	fileWriter := bytes.Buffer{}
-	err = r.Resize(ctx, file, &fileWriter, 200, 100)
+	err = r.Resize(ctx, original, &fileWriter, model.SizeRequest{Width: 200, Height: 100})
 	assert.NoError(t, err)
 
 	img, err := imaging.Decode(bytes.NewReader(fileWriter.Bytes()), imaging.AutoOrientation(true))
@@ -29,3 +36,138 @@ func TestResizer_Resize(t *testing.T) {
 	err = imaging.Save(img, "./fixtures/small.jpg")
 	assert.NoError(t, err)
 }
+
+func TestResizer_ResizeModes(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	original, err := imaging.Open("./fixtures/image.jpg", imaging.AutoOrientation(true))
+	assert.NoError(t, err)
+	originalWidth, originalHeight := original.Bounds().Dx(), original.Bounds().Dy()
+
+	resize := func(request model.SizeRequest) (int, int) {
+		output := bytes.Buffer{}
+		err := r.Resize(ctx, original, &output, request)
+		assert.NoError(t, err)
+
+		img, err := imaging.Decode(&output)
+		assert.NoError(t, err)
+
+		return img.Bounds().Dx(), img.Bounds().Dy()
+	}
+	f := func(request model.SizeRequest, width, height int) {
+		actualWidth, actualHeight := resize(request)
+		assert.Equal(t, width, actualWidth, request.Mode)
+		assert.Equal(t, height, actualHeight, request.Mode)
+	}
+
+	f(model.SizeRequest{Width: 100, Height: 100, Mode: model.ResizeModeStretch}, 100, 100)
+	f(model.SizeRequest{Width: 100, Height: 100, Mode: model.ResizeModeFill}, 100, 100)
+	f(model.SizeRequest{Width: 100, Height: 100, Mode: model.ResizeModeFill, Anchor: model.AnchorTopLeft}, 100, 100)
+	f(model.SizeRequest{Width: 100, Height: 100, Mode: model.ResizeModePad, Background: "#000"}, 100, 100)
+
+	// limit never upscales
+	box := 2 * (originalWidth + originalHeight)
+	f(model.SizeRequest{Width: box, Height: box, Mode: model.ResizeModeLimit}, originalWidth, originalHeight)
+
+	// fit upscales keeping the aspect ratio
+	width, height := resize(model.SizeRequest{Width: box, Height: box, Mode: model.ResizeModeFit})
+	assert.True(t, width == box || height == box)
+	assert.True(t, width <= box && height <= box)
+	assert.InDelta(t, float64(originalWidth)/float64(originalHeight), float64(width)/float64(height), 0.01)
+}
+
+func TestResizer_DecodeInvalid(t *testing.T) {
+	_, err := New(0).Decode(context.Background(), bytes.NewReader([]byte("not an image")))
+	assert.Equal(t, errors.Internal, err)
+}
+
+func TestResizer_DecodeMetadata(t *testing.T) {
+	f := func(img image.Image, format imaging.Format, expected model.Metadata) {
+		var buf bytes.Buffer
+		assert.NoError(t, imaging.Encode(&buf, img, format))
+
+		actual, err := New(0).DecodeMetadata(context.Background(), &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	f(imaging.New(30, 20, color.White), imaging.JPEG, model.Metadata{
+		Format: model.FormatJPEG, Width: 30, Height: 20, ColorModel: model.ColorModelYCbCr,
+	})
+	f(imaging.New(30, 20, color.White), imaging.PNG, model.Metadata{
+		Format: model.FormatPNG, Width: 30, Height: 20, ColorModel: model.ColorModelRGB,
+	})
+	f(imaging.New(30, 20, color.Transparent), imaging.PNG, model.Metadata{
+		Format: model.FormatPNG, Width: 30, Height: 20, ColorModel: model.ColorModelRGBA, HasAlpha: true,
+	})
+	f(image.NewGray(image.Rect(0, 0, 30, 20)), imaging.PNG, model.Metadata{
+		Format: model.FormatPNG, Width: 30, Height: 20, ColorModel: model.ColorModelGray,
+	})
+
+	_, err := New(0).DecodeMetadata(context.Background(), bytes.NewReader([]byte("not an image")))
+	assert.Equal(t, errors.InvalidParams{{Param: "Content", Message: "image"}}, err)
+}
+
+func TestResizer_PixelsLimit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, imaging.Encode(&buf, imaging.New(101, 100, color.White), imaging.PNG))
+	content := buf.Bytes()
+
+	_, err := New(10000).DecodeMetadata(context.Background(), bytes.NewReader(content))
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	_, err = New(10000).Decode(context.Background(), bytes.NewReader(content))
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	img, err := New(10100).Decode(context.Background(), bytes.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, 101, img.Bounds().Dx())
+}
+
+func TestResizer_ResizeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	output := bytes.Buffer{}
+	err := New(0).Resize(ctx, imaging.New(100, 100, color.White), &output, model.SizeRequest{Width: 10, Height: 10})
+	assert.Equal(t, context.Canceled, err)
+	assert.Zero(t, output.Len())
+}
+
+func TestParseHexColor(t *testing.T) {
+	c, err := parseHexColor("#ff8000")
+	assert.NoError(t, err)
+	r, g, b, _ := c.RGBA()
+	assert.Equal(t, []uint32{0xffff, 0x8080, 0}, []uint32{r, g, b})
+
+	c, err = parseHexColor("#fff")
+	assert.NoError(t, err)
+	r, g, b, _ = c.RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+
+	_, err = parseHexColor("#ff80")
+	assert.Error(t, err)
+}
+
+func TestResizer_ResizeFormats(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+
+	original, err := imaging.Open("./fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	f := func(request model.SizeRequest, format string) {
+		output := bytes.Buffer{}
+		err := r.Resize(ctx, original, &output, request)
+		assert.NoError(t, err)
+
+		_, actualFormat, err := image.DecodeConfig(&output)
+		assert.NoError(t, err)
+		assert.Equal(t, format, actualFormat)
+	}
+
+	f(model.SizeRequest{Width: 100, Height: 100, Format: model.FormatJPEG, Quality: 10}, "jpeg")
+	f(model.SizeRequest{Width: 100, Height: 100, Format: model.FormatPNG, Compression: model.CompressionBest}, "png")
+	f(model.SizeRequest{Width: 100, Height: 100, Format: model.FormatGIF}, "gif")
+}
@@ -0,0 +1,416 @@
+package resizer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image/gif"
+	"io"
+	"io/ioutil"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+)
+
+const (
+	markerRST0  = 0xd0
+	markerRST7  = 0xd7
+	markerEOI   = 0xd9
+	markerAPP0  = 0xe0
+	markerAPP2  = 0xe2
+	markerAPP14 = 0xee
+	markerAPP15 = 0xef
+	markerCOM   = 0xfe
+
+	// maxMetadataChunk limits PNG metadata chunks read into memory, larger chunks are dropped
+	maxMetadataChunk = 1 << 16
+)
+
+var (
+	xmpHeader         = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	xmpExtendedHeader = []byte("http://ns.adobe.com/xmp/extension/\x00")
+	xmpKeyword        = []byte("XML:com.adobe.xmp\x00")
+	mpfHeader         = []byte("MPF\x00")
+	// rawProfileKeywords are keywords of text chunks with hex encoded EXIF and XMP written by ImageMagick and ExifTool
+	rawProfileKeywords = [][]byte{
+		[]byte("Raw profile type exif\x00"),
+		[]byte("Raw profile type APP1\x00"),
+		[]byte("Raw profile type xmp\x00"),
+	}
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+)
+
+func (r *Resizer) StripMetadata(
+	ctx context.Context,
+	data io.Reader,
+	output io.Writer,
+	format model.Format,
+	policy model.MetadataPolicy,
+	method model.MetadataMethod,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !policy.Strips() {
+		_, err := io.Copy(output, data)
+		return stripErr(err)
+	}
+
+	switch method.For(format) {
+	case model.MetadataMethodRewrite:
+		if format == model.FormatJPEG {
+			return stripErr(stripJPEG(bufio.NewReader(data), output, policy))
+		}
+		return stripErr(stripPNG(bufio.NewReader(data), output, policy))
+	default:
+		return r.reencode(ctx, data, output, format)
+	}
+}
+
+func (r *Resizer) reencode(ctx context.Context, data io.Reader, output io.Writer, format model.Format) error {
+	img, err := r.Decode(ctx, data)
+	if err != nil {
+		return err
+	}
+	if a, ok := img.(*animation); ok {
+		// the decoded frames are encoded as they are, so the animation is kept
+		return toServiceErr(gif.EncodeAll(output, a.gif))
+	}
+
+	imagingFormat, ok := formats[format]
+	if !ok {
+		return errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+
+	return toServiceErr(imaging.Encode(output, img, imagingFormat, imaging.JPEGQuality(model.DefaultJPEGQuality)))
+}
+
+// stripJPEG drops the MPF index and everything after the EOI marker of the primary image,
+// i.e. secondary images of MPF originals which have EXIF of their own.
+func stripJPEG(data *bufio.Reader, writer io.Writer, policy model.MetadataPolicy) error {
+	var soi [2]byte
+	if _, err := io.ReadFull(data, soi[:]); err != nil {
+		return err
+	}
+	if soi[0] != 0xff || soi[1] != markerSOI {
+		return errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+	output := bufio.NewWriter(writer)
+	if _, err := output.Write(soi[:]); err != nil {
+		return err
+	}
+
+	marker, err := readMarker(data)
+	for err == nil && marker != markerEOI {
+		var segment []byte
+		if segment, err = readJPEGSegment(data); err != nil {
+			break
+		}
+		if segment, keep := stripJPEGSegment(marker, segment, policy); keep {
+			if err = writeJPEGSegment(output, marker, segment); err != nil {
+				break
+			}
+		}
+
+		if marker == markerSOS {
+			marker, err = copyScanData(data, output)
+		} else {
+			marker, err = readMarker(data)
+		}
+	}
+	if err == nil {
+		_, err = output.Write([]byte{0xff, markerEOI})
+	}
+	if err == nil {
+		err = output.Flush()
+	}
+
+	return err
+}
+
+func readJPEGSegment(data *bufio.Reader) ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(data, length[:]); err != nil {
+		return nil, err
+	}
+	size := int(binary.BigEndian.Uint16(length[:]))
+	if size < 2 {
+		return nil, errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+	segment := make([]byte, size-2)
+	if _, err := io.ReadFull(data, segment); err != nil {
+		return nil, err
+	}
+
+	return segment, nil
+}
+
+// copyScanData keeps stuffed bytes and restart markers, a truncated scan ends the image.
+func copyScanData(data *bufio.Reader, output *bufio.Writer) (byte, error) {
+	for {
+		b, err := data.ReadByte()
+		if err == io.EOF {
+			return markerEOI, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if b != 0xff {
+			if err := output.WriteByte(b); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		next, err := data.ReadByte()
+		for err == nil && next == 0xff {
+			next, err = data.ReadByte()
+		}
+		if err == io.EOF {
+			return markerEOI, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if next != 0 && (next < markerRST0 || next > markerRST7) {
+			return next, nil
+		}
+		if _, err := output.Write([]byte{0xff, next}); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func readMarker(data *bufio.Reader) (byte, error) {
+	prefix, err := data.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if prefix != 0xff {
+		return 0, errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+
+	for {
+		marker, err := data.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if marker != 0xff {
+			return marker, nil
+		}
+	}
+}
+
+func stripJPEGSegment(marker byte, segment []byte, policy model.MetadataPolicy) ([]byte, bool) {
+	isExif := marker == markerAPP1 && bytes.HasPrefix(segment, exifHeader)
+	isXMP := marker == markerAPP1 && (bytes.HasPrefix(segment, xmpHeader) || bytes.HasPrefix(segment, xmpExtendedHeader))
+	if marker == markerAPP2 && bytes.HasPrefix(segment, mpfHeader) {
+		// the index of secondary images which are dropped
+		return nil, false
+	}
+
+	if policy == model.MetadataPolicyStripGPS {
+		if isExif {
+			stripGPS(segment[len(exifHeader):])
+		}
+		return segment, !isXMP
+	}
+
+	switch {
+	case isExif:
+		// the orientation is kept, otherwise the image is displayed rotated
+		orientation, _ := readExif(segment[len(exifHeader):])
+		if orientation <= 1 {
+			return nil, false
+		}
+		return append(append([]byte{}, exifHeader...), orientationTIFF(orientation)...), true
+	case marker == markerAPP0, marker == markerAPP2, marker == markerAPP14:
+		// JFIF, ICC profile and Adobe colour transform are needed to display the image
+		return segment, true
+	case marker >= markerAPP0 && marker <= markerAPP15, marker == markerCOM:
+		return nil, false
+	}
+
+	return segment, true
+}
+
+func writeJPEGSegment(output io.Writer, marker byte, segment []byte) error {
+	header := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(segment)+2))
+	if _, err := output.Write(header); err != nil {
+		return err
+	}
+
+	_, err := output.Write(segment)
+	return err
+}
+
+// stripGPS leaves the GPS IFD empty, so offsets of other IFDs stay valid.
+func stripGPS(data []byte) {
+	r := newTIFFReader(data)
+	if r == nil {
+		return
+	}
+
+	for _, e := range r.ifd(r.order.Uint32(data[4:])) {
+		if e.tag != tagGPSIFD {
+			continue
+		}
+		offset, ok := r.uint(e)
+		if !ok {
+			continue
+		}
+
+		if uint64(offset)+2 > uint64(len(data)) {
+			continue
+		}
+		entries := r.ifd(offset)
+		for _, gps := range entries {
+			zero(r.bytes(gps))
+		}
+		zero(data[offset+2 : int(offset)+2+len(entries)*ifdEntrySize])
+		r.order.PutUint16(data[offset:], 0)
+	}
+}
+
+func orientationTIFF(orientation int) []byte {
+	data := make([]byte, 8+2+ifdEntrySize+4)
+	copy(data, "MM\x00\x2a")
+	binary.BigEndian.PutUint32(data[4:], 8)
+	binary.BigEndian.PutUint16(data[8:], 1)
+	binary.BigEndian.PutUint16(data[10:], tagOrientation)
+	binary.BigEndian.PutUint16(data[12:], typeShort)
+	binary.BigEndian.PutUint32(data[14:], 1)
+	binary.BigEndian.PutUint16(data[18:], uint16(orientation))
+
+	return data
+}
+
+func stripPNG(data *bufio.Reader, output io.Writer, policy model.MetadataPolicy) error {
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(data, signature); err != nil {
+		return err
+	}
+	if !bytes.Equal(signature, pngSignature) {
+		return errors.InvalidParams{{Param: "Content", Message: "image"}}
+	}
+	if _, err := output.Write(signature); err != nil {
+		return err
+	}
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(data, header[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		length := int64(binary.BigEndian.Uint32(header[:]))
+		chunkType := string(header[4:])
+
+		keep, inspect := pngChunkPolicy(chunkType, policy)
+		if !keep || (inspect && length > maxMetadataChunk) {
+			if _, err := io.CopyN(ioutil.Discard, data, length+4); err != nil {
+				return err
+			}
+			continue
+		}
+		if !inspect {
+			if _, err := output.Write(header[:]); err != nil {
+				return err
+			}
+			if _, err := io.CopyN(output, data, length+4); err != nil {
+				return err
+			}
+			continue
+		}
+
+		chunk := make([]byte, length+4)
+		if _, err := io.ReadFull(data, chunk); err != nil {
+			return err
+		}
+		chunk = chunk[:length]
+		switch chunkType {
+		case "eXIf":
+			stripGPS(chunk)
+		case "iTXt":
+			if bytes.HasPrefix(chunk, xmpKeyword) || isRawProfile(chunk) {
+				continue
+			}
+		case "tEXt", "zTXt":
+			if isRawProfile(chunk) {
+				continue
+			}
+		}
+		if err := writePNGChunk(output, chunkType, chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// pngChunkPolicy returns whether the chunk is kept and whether its content has to be inspected.
+func pngChunkPolicy(chunkType string, policy model.MetadataPolicy) (bool, bool) {
+	if policy == model.MetadataPolicyStripAll {
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+			return false, false
+		}
+		return true, false
+	}
+
+	switch chunkType {
+	case "eXIf", "iTXt", "tEXt", "zTXt":
+		return true, true
+	}
+	return true, false
+}
+
+func isRawProfile(chunk []byte) bool {
+	for _, keyword := range rawProfileKeywords {
+		if bytes.HasPrefix(chunk, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writePNGChunk(output io.Writer, chunkType string, chunk []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(chunk)))
+	copy(header[4:], chunkType)
+
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[4:])
+	_, _ = crc.Write(chunk)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	for _, part := range [][]byte{header, chunk, footer} {
+		if _, err := output.Write(part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func zero(data []byte) {
+	for i := range data {
+		data[i] = 0
+	}
+}
+
+// stripErr keeps errors of the service, e.g. the upload limit exceeded while the original is read.
+func stripErr(err error) error {
+	switch err.(type) {
+	case nil, errors.ServiceError, errors.InvalidParams:
+		return err
+	}
+
+	return toServiceErr(err)
+}
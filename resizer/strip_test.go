@@ -0,0 +1,182 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tagGPSLatitudeRef      = 0x0001
+	tagGPSLatitude         = 0x0002
+	tagGPSProcessingMethod = 0x001b
+	typeUndefined          = 7
+)
+
+var (
+	latitude = append(append(rational(50, 1), rational(27, 1)...), rational(1234, 100)...)
+	// processingMethod is UNDEFINED text stored out of the IFD entry
+	processingMethod = []byte("ASCII\x00\x00\x00GPS-NETWORK")
+)
+
+func gpsTIFF(orientation byte) []byte {
+	return buildTIFF([]testEntry{
+		{tag: tagMake, typ: typeASCII, count: 6, value: ascii("Canon")},
+		{tag: tagOrientation, typ: typeShort, count: 1, value: []byte{0, orientation}},
+	}, []testEntry{
+		{tag: tagISO, typ: typeShort, count: 1, value: []byte{0, 200}},
+	}, []testEntry{
+		{tag: tagGPSLatitudeRef, typ: typeASCII, count: 2, value: ascii("N")},
+		{tag: tagGPSLatitude, typ: typeRational, count: 3, value: latitude},
+		{tag: tagGPSProcessingMethod, typ: typeUndefined, count: uint32(len(processingMethod)), value: processingMethod},
+	})
+}
+
+func withSegment(jpeg []byte, marker byte, segment []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(jpeg[:2])
+	_ = writeJPEGSegment(&buf, marker, segment)
+	buf.Write(jpeg[2:])
+
+	return buf.Bytes()
+}
+
+func withChunk(png []byte, chunkType string, chunk []byte) []byte {
+	const ihdrEnd = 8 + 8 + 13 + 4
+
+	var buf bytes.Buffer
+	buf.Write(png[:ihdrEnd])
+	_ = writePNGChunk(&buf, chunkType, chunk)
+	buf.Write(png[ihdrEnd:])
+
+	return buf.Bytes()
+}
+
+func encodeTest(t *testing.T, format imaging.Format) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, imaging.Encode(&buf, imaging.New(30, 20, color.White), format))
+
+	return buf.Bytes()
+}
+
+func strip(t *testing.T, data []byte, format model.Format, policy model.MetadataPolicy, method model.MetadataMethod) []byte {
+	var output bytes.Buffer
+	assert.NoError(t, New(0).StripMetadata(context.Background(), bytes.NewReader(data), &output, format, policy, method))
+
+	return output.Bytes()
+}
+
+func TestResizer_StripMetadataJPEG(t *testing.T) {
+	original := encodeTest(t, imaging.JPEG)
+	original = withSegment(original, markerCOM, []byte("comment"))
+	original = withSegment(original, markerAPP1, append(append([]byte{}, xmpHeader...), "<x:xmpmeta/>"...))
+	original = withExif(original, gpsTIFF(6))
+
+	f := func(policy model.MetadataPolicy, method model.MetadataMethod, expected model.Metadata, comment bool) {
+		res := strip(t, original, model.FormatJPEG, policy, method)
+
+		metadata, err := New(0).DecodeMetadata(context.Background(), bytes.NewReader(res))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, metadata)
+		assert.False(t, bytes.Contains(res, latitude))
+		assert.False(t, bytes.Contains(res, []byte("GPS-NETWORK")))
+		assert.False(t, bytes.Contains(res, xmpHeader))
+		assert.Equal(t, comment, bytes.Contains(res, []byte("comment")))
+
+		img, err := imaging.Decode(bytes.NewReader(res), imaging.AutoOrientation(true))
+		assert.NoError(t, err)
+		assert.Equal(t, 20, img.Bounds().Dx())
+		assert.Equal(t, 30, img.Bounds().Dy())
+	}
+
+	f(model.MetadataPolicyStripGPS, model.MetadataMethodRewrite, model.Metadata{
+		Format:      model.FormatJPEG,
+		Width:       20,
+		Height:      30,
+		Orientation: 6,
+		ColorModel:  model.ColorModelYCbCr,
+		Exif:        &model.Exif{Make: "Canon", ISO: 200},
+	}, true)
+	f(model.MetadataPolicyStripAll, model.MetadataMethodRewrite, model.Metadata{
+		Format:      model.FormatJPEG,
+		Width:       20,
+		Height:      30,
+		Orientation: 6,
+		ColorModel:  model.ColorModelYCbCr,
+	}, false)
+	f(model.MetadataPolicyStripGPS, model.MetadataMethodReencode, model.Metadata{
+		Format:     model.FormatJPEG,
+		Width:      20,
+		Height:     30,
+		ColorModel: model.ColorModelYCbCr,
+	}, false)
+}
+
+func TestResizer_StripMetadataMPF(t *testing.T) {
+	// the secondary image of the MPF original follows the EOI marker of the primary one
+	original := withExif(encodeTest(t, imaging.JPEG), gpsTIFF(1))
+	original = withSegment(original, markerAPP2, append(append([]byte{}, mpfHeader...), "index"...))
+	original = append(original, withExif(encodeTest(t, imaging.JPEG), gpsTIFF(1))...)
+
+	for _, policy := range []model.MetadataPolicy{model.MetadataPolicyStripGPS, model.MetadataPolicyStripAll} {
+		res := strip(t, original, model.FormatJPEG, policy, model.MetadataMethodRewrite)
+
+		assert.False(t, bytes.Contains(res, latitude), policy)
+		assert.False(t, bytes.Contains(res, []byte("GPS-NETWORK")), policy)
+		assert.False(t, bytes.Contains(res, mpfHeader), policy)
+		assert.Equal(t, []byte{0xff, markerEOI}, res[len(res)-2:], policy)
+
+		img, err := imaging.Decode(bytes.NewReader(res))
+		assert.NoError(t, err)
+		assert.Equal(t, 30, img.Bounds().Dx())
+	}
+}
+
+func TestResizer_StripMetadataPNG(t *testing.T) {
+	original := encodeTest(t, imaging.PNG)
+	original = withChunk(original, "tEXt", []byte("Comment\x00comment"))
+	original = withChunk(original, "iTXt", append(append([]byte{}, xmpKeyword...), "\x00\x00\x00\x00<x:xmpmeta/>"...))
+	original = withChunk(original, "eXIf", gpsTIFF(1))
+	original = withChunk(original, "zTXt", []byte("Raw profile type exif\x00\x00compressed"))
+
+	f := func(policy model.MetadataPolicy, exif, comment bool) {
+		res := strip(t, original, model.FormatPNG, policy, model.MetadataMethodRewrite)
+
+		img, err := imaging.Decode(bytes.NewReader(res))
+		assert.NoError(t, err)
+		assert.Equal(t, 30, img.Bounds().Dx())
+		assert.False(t, bytes.Contains(res, latitude))
+		assert.False(t, bytes.Contains(res, []byte("GPS-NETWORK")))
+		assert.False(t, bytes.Contains(res, xmpKeyword))
+		assert.False(t, bytes.Contains(res, []byte("Raw profile type")))
+		assert.Equal(t, exif, bytes.Contains(res, []byte("Canon")))
+		assert.Equal(t, comment, bytes.Contains(res, []byte("comment")))
+	}
+
+	f(model.MetadataPolicyStripGPS, true, true)
+	f(model.MetadataPolicyStripAll, false, false)
+}
+
+func TestResizer_StripMetadataKeep(t *testing.T) {
+	original := withExif(encodeTest(t, imaging.JPEG), gpsTIFF(1))
+
+	assert.Equal(t, original, strip(t, original, model.FormatJPEG, model.MetadataPolicyKeep, model.MetadataMethodRewrite))
+}
+
+func TestStripGPS_Malformed(t *testing.T) {
+	f := func(data []byte) {
+		assert.NotPanics(t, func() {
+			stripGPS(append([]byte{}, data...))
+		})
+	}
+
+	f(nil)
+	f([]byte("MM\x00\x2a\xff\xff\xff\xff"))
+	f([]byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x88\x25\x00\x04\x00\x00\x00\x01\xff\xff\xff\xf0"))
+	f([]byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x88\x25\x00\x04\x00\x00\x00\x01\x00\x00\x00\x08"))
+}
@@ -0,0 +1,48 @@
+package resizer
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/model"
+)
+
+var positions = map[model.Anchor][2]float64{
+	model.AnchorCenter:      {0.5, 0.5},
+	model.AnchorTopLeft:     {0, 0},
+	model.AnchorTop:         {0.5, 0},
+	model.AnchorTopRight:    {1, 0},
+	model.AnchorLeft:        {0, 0.5},
+	model.AnchorRight:       {1, 0.5},
+	model.AnchorBottomLeft:  {0, 1},
+	model.AnchorBottom:      {0.5, 1},
+	model.AnchorBottomRight: {1, 1},
+}
+
+func composite(img image.Image, overlay *model.Overlay) *image.NRGBA {
+	bounds := img.Bounds()
+	width := max(int(float64(bounds.Dx())*overlay.Scale+0.5), 1)
+	mark := imaging.Resize(overlay.Image, width, 0, imaging.Lanczos)
+	size := mark.Bounds().Size()
+
+	if !overlay.Tile {
+		position := positions[overlay.Position]
+		point := image.Pt(
+			int(position[0]*float64(bounds.Dx()-size.X)+0.5),
+			int(position[1]*float64(bounds.Dy()-size.Y)+0.5),
+		)
+
+		return imaging.Overlay(img, mark, bounds.Min.Add(point), overlay.Opacity)
+	}
+
+	// tiles are drawn to a single layer, so the opacity is applied once
+	layer := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y += size.Y {
+		for x := 0; x < bounds.Dx(); x += size.X {
+			draw.Draw(layer, image.Rect(x, y, x+size.X, y+size.Y), mark, image.Point{}, draw.Src)
+		}
+	}
+
+	return imaging.Overlay(img, layer, bounds.Min, overlay.Opacity)
+}
@@ -0,0 +1,58 @@
+package resizer
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizer_ResizeWatermark(t *testing.T) {
+	r := New(0)
+	ctx := context.Background()
+	original := imaging.New(400, 200, color.White)
+	mark := imaging.New(10, 5, color.Black)
+
+	resize := func(overlay model.Overlay) image.Image {
+		output := bytes.Buffer{}
+		request := model.SizeRequest{Width: 100, Height: 50, Format: model.FormatPNG, Watermark: "mark", Overlay: &overlay}
+		assert.NoError(t, r.Resize(ctx, original, &output, request))
+
+		img, err := imaging.Decode(&output)
+		assert.NoError(t, err)
+		assert.Equal(t, image.Pt(100, 50), img.Bounds().Size())
+
+		return img
+	}
+	gray := func(img image.Image, x, y int) uint8 {
+		return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+	}
+
+	// the overlay is 20x10 at the bottom right corner
+	img := resize(model.Overlay{Image: mark, Position: model.AnchorBottomRight, Opacity: 1, Scale: 0.2})
+	assert.Equal(t, uint8(0), gray(img, 85, 45))
+	assert.Equal(t, uint8(255), gray(img, 75, 45))
+	assert.Equal(t, uint8(255), gray(img, 85, 35))
+
+	img = resize(model.Overlay{Image: mark, Position: model.AnchorTopLeft, Opacity: 0.5, Scale: 0.2})
+	assert.InDelta(t, 128, gray(img, 5, 5), 1)
+	assert.Equal(t, uint8(255), gray(img, 50, 25))
+
+	// tiles cover the whole size
+	img = resize(model.Overlay{Image: mark, Opacity: 1, Scale: 0.2, Tile: true})
+	for _, p := range []image.Point{{0, 0}, {50, 25}, {99, 49}} {
+		assert.Equal(t, uint8(0), gray(img, p.X, p.Y), p)
+	}
+}
+
+func TestResizer_ResizeWatermarkNotLoaded(t *testing.T) {
+	request := model.SizeRequest{Width: 100, Height: 50, Watermark: "mark"}
+	err := New(0).Resize(context.Background(), imaging.New(400, 200, color.White), &bytes.Buffer{}, request)
+	assert.Equal(t, errors.Internal, err)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"math/rand"
+	"testing"
+
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_UploadAnimation(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	// noisy frames aren't compressed below the minimal upload size
+	random := rand.New(rand.NewSource(1))
+	animation := &gif.GIF{Delay: []int{10, 20, 30}, Disposal: []byte{gif.DisposalNone, gif.DisposalNone, gif.DisposalNone}}
+	for range animation.Delay {
+		frame := image.NewPaletted(image.Rect(0, 0, 64, 64), palette.WebSafe)
+		for i := range frame.Pix {
+			frame.Pix[i] = uint8(random.Intn(len(palette.WebSafe)))
+		}
+		animation.Image = append(animation.Image, frame)
+	}
+	var content bytes.Buffer
+	assert.NoError(t, gif.EncodeAll(&content, animation))
+
+	uploaded, err := srv.Upload(ctx, model.ImageUpload{
+		Content:        bytes.NewReader(content.Bytes()),
+		Filename:       "image.gif",
+		Size:           int64(content.Len()),
+		MimeType:       "image/gif",
+		MetadataPolicy: model.MetadataPolicyStripAll,
+	}, []model.SizeRequest{
+		{Width: 32, Height: 32},
+		{Width: 32, Height: 32, Format: model.FormatJPEG, Frame: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.FormatGIF, uploaded.Format)
+	assert.Len(t, uploaded.Sizes, 2)
+	assert.Equal(t, model.FormatGIF, uploaded.Sizes[0].Format)
+	assert.Equal(t, 2, uploaded.Sizes[1].Frame)
+
+	decode := func(path string) *gif.GIF {
+		reader, err := storage.Read(ctx, path)
+		assert.NoError(t, err)
+		res, err := gif.DecodeAll(reader)
+		assert.NoError(t, err)
+		return res
+	}
+
+	// the stripped original and the GIF size keep all frames
+	assert.Len(t, decode(uploaded.Path).Image, 3)
+	resized := decode(uploaded.Sizes[0].Path)
+	assert.Len(t, resized.Image, 3)
+	assert.Equal(t, []int{10, 20, 30}, resized.Delay)
+	assert.Equal(t, image.Rect(0, 0, 32, 32), resized.Image[0].Bounds())
+
+	rotated, err := srv.RotateImage(ctx, uploaded.ID, 90)
+	assert.NoError(t, err)
+	assert.Len(t, decode(rotated.Path).Image, 3)
+	assert.Len(t, decode(rotated.Sizes[0].Path).Image, 3)
+}
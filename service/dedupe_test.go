@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_UploadDedupe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	sum := sha256.Sum256([]byte("Some content"))
+	hash := hex.EncodeToString(sum[:])
+	shared := &model.Image{ID: "shared", Path: "some/path/shared.png", MimeType: "image/png", Hash: hash, Shared: true, Version: 2}
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Upload(gomock.Eq(ctx), gomock.Any(), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "some/path/copy.png", err
+		}).
+		Times(2)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/shared.png")).
+		Return(strings.NewReader("Some content"), nil).
+		Times(2)
+	// the copy stored by the upload which lost the race
+	storage.EXPECT().
+		Delete(gomock.Eq(ctx), gomock.Eq("some/path/copy.png")).
+		Return(nil)
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		Return(model.Metadata{Format: model.FormatPNG}, nil).
+		AnyTimes()
+
+	repo := mock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().
+			GetShared(gomock.Eq(ctx), gomock.Eq(hash)).
+			Return(nil, errors.NotFound),
+		repo.EXPECT().
+			GetShared(gomock.Eq(ctx), gomock.Eq(hash)).
+			Return(shared, nil),
+		repo.EXPECT().
+			GetShared(gomock.Eq(ctx), gomock.Eq(hash)).
+			Return(nil, errors.NotFound),
+		repo.EXPECT().
+			GetShared(gomock.Eq(ctx), gomock.Eq(hash)).
+			Return(shared, nil),
+	)
+	gomock.InOrder(
+		repo.EXPECT().
+			Save(gomock.Eq(ctx), gomock.Eq(0), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, i model.Image) error {
+				assert.Equal(t, hash, i.Hash)
+				assert.True(t, i.Shared)
+				return nil
+			}),
+		repo.EXPECT().
+			Save(gomock.Eq(ctx), gomock.Eq(2), gomock.Any()).
+			Return(nil),
+		// a concurrent upload of the same content saved the shared image first
+		repo.EXPECT().
+			Save(gomock.Eq(ctx), gomock.Eq(0), gomock.Any()).
+			Return(errors.RaceCondition),
+		repo.EXPECT().
+			Save(gomock.Eq(ctx), gomock.Eq(2), gomock.Any()).
+			Return(nil),
+	)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("shared")).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			copied := *shared
+			return &copied, nil
+		}).
+		Times(2)
+	repo.EXPECT().
+		Referenced(gomock.Eq(ctx), gomock.Eq("some/path/copy.png")).
+		Return(false, nil)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 1, Limits{}, MetadataConfig{})
+	upload := func() model.ImageUpload {
+		return model.ImageUpload{
+			Content:  strings.NewReader("Some content"),
+			Filename: "original.png",
+			Size:     1000,
+			MimeType: "image/png",
+			Dedupe:   true,
+		}
+	}
+
+	// the first upload of the content
+	image, err := srv.Upload(ctx, upload(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "some/path/copy.png", image.Path)
+
+	// the same content is returned as the shared image without storing a copy
+	image, err = srv.Upload(ctx, upload(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", image.ID)
+
+	// the upload which lost the race returns the shared image
+	image, err = srv.Upload(ctx, upload(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", image.ID)
+}
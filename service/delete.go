@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	cleanupRetryDelay    = 10 * time.Second
+	cleanupMaxRetryDelay = 10 * time.Minute
+)
+
+var errRecentlyWritten = stderrors.New("unreferenced objects were written recently")
+
+func (s *ImageService) DeleteImage(ctx context.Context, id string) error {
+	for i := 0; ; i++ {
+		image, err := s.getImage(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		image.MarkDeleted()
+		version := image.Version
+		image.Version++
+		err = s.repo.Save(ctx, version, *image)
+		if err == errors.RaceCondition && i < saveRetries {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		s.scheduleCleanup(ctx, id)
+
+		return nil
+	}
+}
+
+func (s *ImageService) DeleteSize(ctx context.Context, id string, width, height int) (*model.Image, error) {
+	for i := 0; ; i++ {
+		image, err := s.getImage(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// the job of a pending size would add it again
+		if image.HasPendingSize(width, height) {
+			return nil, errors.InvalidParams{{Param: "size", Message: "pending"}}
+		}
+		if removed := image.RemoveSizes(width, height); len(removed) == 0 {
+			return nil, errors.NotFound
+		}
+		version := image.Version
+		image.Version++
+		err = s.repo.Save(ctx, version, *image)
+		if err == errors.RaceCondition && i < saveRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.scheduleCleanup(ctx, id)
+
+		return image, nil
+	}
+}
+
+func (s *ImageService) scheduleCleanup(ctx context.Context, imageID string) {
+	now := time.Now()
+	job := model.Job{
+		ID:        uuid.NewV4().String(),
+		Kind:      model.JobKindCleanup,
+		ImageID:   imageID,
+		Status:    model.JobStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.jobs.SaveJob(ctx, job); err != nil {
+		log.Error("can't save cleanup job of image ", imageID, " ", err)
+		return
+	}
+
+	s.enqueue(job.ID)
+}
+
+func (s *ImageService) cleanup(ctx context.Context, imageID string) error {
+	image, err := s.repo.Get(ctx, imageID)
+	if err == errors.NotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	removed, removeErr := s.removeObjects(ctx, image.Trash)
+
+	for i := 0; ; i++ {
+		image.EmptyTrash(removed)
+		if image.Deleted && len(image.Trash) == 0 {
+			err = s.repo.Delete(ctx, image.ID, image.Version)
+		} else {
+			version := image.Version
+			image.Version++
+			err = s.repo.Save(ctx, version, *image)
+		}
+		if err != errors.RaceCondition || i >= saveRetries {
+			break
+		}
+
+		if image, err = s.repo.Get(ctx, imageID); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return removeErr
+}
+
+// removeObjects returns paths which don't need to be removed anymore. Variants are content-addressed and
+// an identical render of an upload which isn't saved yet isn't referenced, so objects written within
+// the grace period are kept and errRecentlyWritten is returned unless other objects failed.
+func (s *ImageService) removeObjects(ctx context.Context, paths []string) ([]string, error) {
+	var (
+		removed   []string
+		removeErr error
+		recent    bool
+	)
+	for _, path := range paths {
+		referenced, err := s.repo.Referenced(ctx, path)
+		if err == nil && !referenced {
+			var keep bool
+			keep, err = s.recentlyWritten(ctx, path)
+			if keep {
+				recent = true
+				continue
+			}
+			if err == nil {
+				err = s.storage.Delete(ctx, path)
+			}
+		}
+		if err != nil {
+			removeErr = err
+			continue
+		}
+		removed = append(removed, path)
+	}
+	if removeErr == nil && recent {
+		removeErr = errRecentlyWritten
+	}
+
+	return removed, removeErr
+}
+
+func (s *ImageService) recentlyWritten(ctx context.Context, path string) (bool, error) {
+	if s.limits.CleanupGracePeriod <= 0 {
+		return false, nil
+	}
+
+	modified, err := s.storage.Modified(ctx, path)
+	if err == errors.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return time.Since(modified) < s.limits.CleanupGracePeriod, nil
+}
+
+func (s *ImageService) discardUpload(ctx context.Context, image *model.Image) {
+	var paths []string
+	if image.Path != "" {
+		paths = append(paths, image.Path)
+	}
+	for _, size := range image.Sizes {
+		if size.Path != "" {
+			paths = append(paths, size.Path)
+		}
+	}
+
+	removed, err := s.removeObjects(ctx, paths)
+	if err == nil {
+		return
+	}
+	if err != errRecentlyWritten {
+		log.Error("can't remove objects of discarded image ", image.ID, " ", err)
+	}
+
+	s.scheduleRemoval(ctx, without(paths, removed))
+}
+
+func (s *ImageService) scheduleRemoval(ctx context.Context, paths []string) {
+	now := time.Now()
+	job := model.Job{
+		ID:        uuid.NewV4().String(),
+		Kind:      model.JobKindRemove,
+		Paths:     paths,
+		Status:    model.JobStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.jobs.SaveJob(ctx, job); err != nil {
+		log.Error("can't save removal job of ", paths, " ", err)
+		return
+	}
+
+	s.enqueue(job.ID)
+}
+
+// removePaths keeps objects within the grace period after the job is created, e.g. replaced overlays read by running renders.
+func (s *ImageService) removePaths(ctx context.Context, job *model.Job) error {
+	if time.Since(job.CreatedAt) < s.limits.CleanupGracePeriod {
+		return errRecentlyWritten
+	}
+
+	removed, err := s.removeObjects(ctx, job.Paths)
+	job.Paths = without(job.Paths, removed)
+
+	return err
+}
+
+func without(paths, removed []string) []string {
+	kept := make([]string, 0, len(paths))
+	for _, path := range paths {
+		isRemoved := false
+		for _, r := range removed {
+			if r == path {
+				isRemoved = true
+				break
+			}
+		}
+		if !isRemoved {
+			kept = append(kept, path)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"image"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	"github.com/portey/image-resizer/service/mock"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_DeleteSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stored := &model.Image{
+		ID:       "id",
+		Path:     "some/path/test.png",
+		MimeType: "image/png",
+		Version:  1,
+	}
+	stored.AddSize("some/resized/small.png", 10, model.SizeRequest{Width: 100, Height: 100})
+	stored.AddSize("some/resized/big.png", 10, model.SizeRequest{Width: 200, Height: 200})
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Delete(gomock.Eq(ctx), gomock.Eq("some/resized/small.png")).
+		Return(nil)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Referenced(gomock.Eq(ctx), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			copied := *stored
+			return &copied, nil
+		}).
+		Times(3)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, version int, i model.Image) error {
+			assert.Equal(t, stored.Version, version)
+			stored = &i
+
+			return nil
+		}).
+		Times(2)
+
+	jobs := mock.NewMockJobRepository(ctrl)
+	jobs.EXPECT().
+		SaveJob(gomock.Eq(ctx), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job model.Job) error {
+			assert.Equal(t, model.JobKindCleanup, job.Kind)
+			assert.Equal(t, "id", job.ImageID)
+
+			return nil
+		})
+
+	srv := New(storage, nil, repo, jobs, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	i, err := srv.DeleteSize(ctx, "id", 100, 100)
+	assert.NoError(t, err)
+	assert.Len(t, i.Sizes, 1)
+	assert.Equal(t, "some/resized/big.png", i.Sizes[0].Path)
+	assert.Equal(t, []string{"some/resized/small.png"}, stored.Trash)
+
+	// unknown size
+	_, err = srv.DeleteSize(ctx, "id", 300, 300)
+	assert.Equal(t, errors.NotFound, err)
+
+	err = srv.cleanup(ctx, "id")
+	assert.NoError(t, err)
+	assert.Empty(t, stored.Trash)
+	assert.Len(t, stored.Sizes, 1)
+}
+
+func TestImageService_DeleteImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stored := &model.Image{
+		ID:       "id",
+		Path:     "some/path/test.png",
+		MimeType: "image/png",
+		Version:  1,
+	}
+	stored.AddSize("some/resized/small.png", 10, model.SizeRequest{Width: 100, Height: 100})
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Delete(gomock.Eq(ctx), gomock.Eq("some/path/test.png")).
+		Return(nil)
+	// the first removal of the size fails, so the cleanup is retried
+	gomock.InOrder(
+		storage.EXPECT().
+			Delete(gomock.Eq(ctx), gomock.Eq("some/resized/small.png")).
+			Return(stderrors.New("storage is unavailable")),
+		storage.EXPECT().
+			Delete(gomock.Eq(ctx), gomock.Eq("some/resized/small.png")).
+			Return(nil),
+	)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Referenced(gomock.Eq(ctx), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			copied := *stored
+			return &copied, nil
+		}).
+		Times(4)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, version int, i model.Image) error {
+			assert.Equal(t, stored.Version, version)
+			stored = &i
+
+			return nil
+		}).
+		Times(2)
+	repo.EXPECT().
+		Delete(gomock.Eq(ctx), gomock.Eq("id"), gomock.Eq(3)).
+		Return(nil)
+
+	jobs := mock.NewMockJobRepository(ctrl)
+	jobs.EXPECT().
+		SaveJob(gomock.Eq(ctx), gomock.Any()).
+		Return(nil)
+
+	srv := New(storage, nil, repo, jobs, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	err := srv.DeleteImage(ctx, "id")
+	assert.NoError(t, err)
+	assert.True(t, stored.Deleted)
+	assert.Len(t, stored.Trash, 2)
+
+	// deleted image is hidden
+	_, err = srv.getImage(ctx, "id")
+	assert.Equal(t, errors.NotFound, err)
+
+	err = srv.cleanup(ctx, "id")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"some/resized/small.png"}, stored.Trash)
+
+	err = srv.cleanup(ctx, "id")
+	assert.NoError(t, err)
+}
+
+func TestImageService_CleanupShared(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stored := &model.Image{
+		ID:      "id",
+		Path:    "some/path/test.png",
+		Version: 1,
+		Trash:   []string{"100_100/shared.png", "200_200/own.png"},
+	}
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Delete(gomock.Eq(ctx), gomock.Eq("200_200/own.png")).
+		Return(nil)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		Return(stored, nil)
+	// an identical render of another image shares the object
+	repo.EXPECT().
+		Referenced(gomock.Eq(ctx), gomock.Eq("100_100/shared.png")).
+		Return(true, nil)
+	repo.EXPECT().
+		Referenced(gomock.Eq(ctx), gomock.Eq("200_200/own.png")).
+		Return(false, nil)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, i model.Image) error {
+			assert.Empty(t, i.Trash)
+			return nil
+		})
+
+	srv := New(storage, nil, repo, nil, nil, nil, 1, Limits{}, MetadataConfig{})
+
+	assert.NoError(t, srv.cleanup(ctx, "id"))
+}
+
+type referencedHook struct {
+	*repositorymemory.Repository
+	hook func(path string)
+}
+
+func (r *referencedHook) Referenced(ctx context.Context, path string) (bool, error) {
+	if r.hook != nil {
+		r.hook(path)
+	}
+
+	return r.Repository.Referenced(ctx, path)
+}
+
+func TestImageService_CleanupRenderedAgain(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	repo := &referencedHook{Repository: repositorymemory.New()}
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, repo, 1, Limits{CleanupGracePeriod: 50 * time.Millisecond}, MetadataConfig{})
+
+	upload := func() *model.Image {
+		image, err := srv.Upload(ctx, model.ImageUpload{
+			Content:  bytes.NewReader(content),
+			Filename: "image.jpg",
+			Size:     int64(len(content)),
+			MimeType: "image/jpeg",
+		}, []model.SizeRequest{{Width: 100, Height: 100}})
+		assert.NoError(t, err)
+		return image
+	}
+
+	image := upload()
+	variant := image.Sizes[0].Path
+	rendered, err := storage.Read(ctx, variant)
+	assert.NoError(t, err)
+	render, err := ioutil.ReadAll(rendered)
+	assert.NoError(t, err)
+
+	_, err = srv.DeleteSize(ctx, image.ID, 100, 100)
+	assert.NoError(t, err)
+	time.Sleep(60 * time.Millisecond)
+
+	// another image renders the same variant between the check and the removal, it isn't saved yet
+	repo.hook = func(path string) {
+		_, err := storage.UploadResized(ctx, bytes.NewReader(render), 100, 100, model.FormatJPEG)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, errRecentlyWritten, srv.cleanup(ctx, image.ID))
+	repo.hook = nil
+	assert.Contains(t, storage.Keys(), variant)
+
+	other := upload()
+	assert.Equal(t, variant, other.Sizes[0].Path)
+
+	// the saved image refers to the variant, so it's kept after the grace period
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, srv.cleanup(ctx, image.ID))
+	assert.Contains(t, storage.Keys(), variant)
+	saved, err := repo.Get(ctx, image.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, saved.Trash)
+
+	// unreferenced variants written before the grace period are removed
+	_, err = srv.DeleteSize(ctx, other.ID, 100, 100)
+	assert.NoError(t, err)
+	assert.NoError(t, srv.cleanup(ctx, other.ID))
+	assert.NotContains(t, storage.Keys(), variant)
+}
+
+type failingJobs struct {
+	*repositorymemory.Repository
+	failing bool
+}
+
+func (r *failingJobs) SaveJob(ctx context.Context, job model.Job) error {
+	if r.failing {
+		return errors.Internal
+	}
+
+	return r.Repository.SaveJob(ctx, job)
+}
+
+func TestImageService_SweepTrash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	repo := repositorymemory.New()
+	jobs := &failingJobs{Repository: repo}
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, jobs, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	image, err := srv.Upload(ctx, model.ImageUpload{
+		Content:  bytes.NewReader(content),
+		Filename: "image.jpg",
+		Size:     int64(len(content)),
+		MimeType: "image/jpeg",
+	}, []model.SizeRequest{{Width: 100, Height: 100}})
+	assert.NoError(t, err)
+
+	// the image is deleted even if its cleanup isn't scheduled
+	jobs.failing = true
+	assert.NoError(t, srv.DeleteImage(ctx, image.ID))
+	jobs.failing = false
+
+	// the image is hidden already, its objects are removed by the cleanup scheduled on start
+	_, err = srv.Image(ctx, image.ID)
+	assert.Equal(t, errors.NotFound, err)
+
+	var wg sync.WaitGroup
+	srv.RunJobs(ctx, &wg, 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(storage.Keys()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Empty(t, storage.Keys())
+	_, err = repo.Get(ctx, image.ID)
+	assert.Equal(t, errors.NotFound, err)
+
+	cancel()
+	wg.Wait()
+}
+
+type failingResize struct {
+	*resizer.Resizer
+	width int
+}
+
+func (r *failingResize) Resize(ctx context.Context, img image.Image, output io.Writer, request model.SizeRequest) error {
+	if request.Width == r.width {
+		return errors.Internal
+	}
+
+	return r.Resizer.Resize(ctx, img, output, request)
+}
+
+func TestImageService_UploadFails(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	memory := repositorymemory.New()
+	repo := &failingImages{Repository: memory}
+	storage := storagememory.New()
+	resize := &failingResize{Resizer: resizer.New(0)}
+	srv := New(storage, resize, repo, memory, memory, memory, 2, Limits{}, MetadataConfig{})
+
+	upload := func() error {
+		_, err := srv.Upload(ctx, model.ImageUpload{
+			Content:  bytes.NewReader(content),
+			Filename: "image.jpg",
+			Size:     int64(len(content)),
+			MimeType: "image/jpeg",
+		}, []model.SizeRequest{{Width: 100, Height: 100}, {Width: 200, Height: 200}})
+		return err
+	}
+
+	// neither the original nor the stored sibling is left if a size fails
+	resize.width = 200
+	assert.Equal(t, errors.Internal, upload())
+	resize.width = 0
+	assert.Empty(t, storage.Keys())
+
+	// the original and the sizes are removed if the image isn't saved
+	repo.failing = true
+	assert.Equal(t, errors.Internal, upload())
+	repo.failing = false
+	assert.Empty(t, storage.Keys())
+
+	assert.NoError(t, upload())
+	assert.Len(t, storage.Keys(), 3)
+}
+
+func TestImageService_ResizeSaveFails(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	memory := repositorymemory.New()
+	repo := &failingImages{Repository: memory}
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, memory, memory, memory, 1, Limits{}, MetadataConfig{})
+
+	image, err := srv.Upload(ctx, model.ImageUpload{
+		Content:  bytes.NewReader(content),
+		Filename: "image.jpg",
+		Size:     int64(len(content)),
+		MimeType: "image/jpeg",
+	}, []model.SizeRequest{{Width: 100, Height: 100}})
+	assert.NoError(t, err)
+	stored := storage.Keys()
+	assert.Len(t, stored, 2)
+
+	// the renders of the resize which isn't saved are removed, the stored objects are kept
+	repo.failing = true
+	_, err = srv.Resize(ctx, image.ID, []model.SizeRequest{{Width: 100, Height: 100}, {Width: 50, Height: 50}})
+	assert.Equal(t, errors.Internal, err)
+	repo.failing = false
+	assert.ElementsMatch(t, stored, storage.Keys())
+}
+
+type resizeHook struct {
+	*resizer.Resizer
+	hook func()
+}
+
+func (r *resizeHook) Resize(ctx context.Context, img image.Image, output io.Writer, request model.SizeRequest) error {
+	if r.hook != nil {
+		r.hook()
+	}
+
+	return r.Resizer.Resize(ctx, img, output, request)
+}
+
+func TestImageService_DeletePendingSize(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	resize := &resizeHook{Resizer: resizer.New(0)}
+	srv := New(storage, resize, repo, repo, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	image, err := srv.UploadAsync(ctx, model.ImageUpload{
+		Content:  bytes.NewReader(content),
+		Filename: "image.jpg",
+		Size:     int64(len(content)),
+		MimeType: "image/jpeg",
+	}, []model.SizeRequest{{Width: 100, Height: 100}})
+	assert.NoError(t, err)
+
+	// the size can't be deleted while its job runs, the job would add it again
+	resize.hook = func() {
+		_, err := srv.DeleteSize(ctx, image.ID, 100, 100)
+		assert.Equal(t, errors.InvalidParams{{Param: "size", Message: "pending"}}, err)
+	}
+	srv.runJob(ctx, image.JobID)
+	resize.hook = nil
+
+	// the resized size is deleted once the job finishes
+	deleted, err := srv.DeleteSize(ctx, image.ID, 100, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted.Sizes)
+}
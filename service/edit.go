@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+)
+
+func (s *ImageService) RotateImage(ctx context.Context, id string, degrees int) (*model.Image, error) {
+	return s.editOriginal(ctx, id, model.Edit{Rotate: degrees})
+}
+
+func (s *ImageService) FlipImage(ctx context.Context, id string, axis model.FlipAxis) (*model.Image, error) {
+	return s.editOriginal(ctx, id, model.Edit{Flip: axis})
+}
+
+func (s *ImageService) CropImage(ctx context.Context, id string, rect model.Rect) (*model.Image, error) {
+	return s.editOriginal(ctx, id, model.Edit{Crop: &rect})
+}
+
+func (s *ImageService) editOriginal(ctx context.Context, id string, edit model.Edit) (*model.Image, error) {
+	if err := s.validateParams(edit); len(err) > 0 {
+		return nil, err
+	}
+
+	for i := 0; ; i++ {
+		image, err := s.getImage(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		image, err = s.storeEdited(ctx, image, edit)
+		if err != nil {
+			return nil, err
+		}
+
+		version := image.Version
+		image.Version++
+		err = s.repo.Save(ctx, version, *image)
+		if err == errors.RaceCondition && i < saveRetries {
+			s.discardUpload(ctx, image)
+			continue
+		}
+		if err != nil {
+			s.discardUpload(ctx, image)
+			return nil, err
+		}
+
+		s.scheduleCleanup(ctx, id)
+
+		return image, nil
+	}
+}
+
+func (s *ImageService) storeEdited(ctx context.Context, image *model.Image, edit model.Edit) (*model.Image, error) {
+	reader, err := s.storage.Read(ctx, image.Path)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.resizer.Decode(ctx, reader)
+	closeReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := original.Bounds()
+	if edit.Crop != nil && !edit.Crop.Within(bounds.Dx(), bounds.Dy()) {
+		return nil, errors.InvalidParams{{Param: "Crop", Message: "bounds"}}
+	}
+
+	format := image.Format
+	if format == "" {
+		format = model.FormatFromMimeType(image.MimeType)
+	}
+
+	edited := s.newSpool()
+	defer closeSpool(edited)
+
+	hash := sha256.New()
+	if err := s.resizer.Edit(ctx, original, io.MultiWriter(edited, hash), edit, format); err != nil {
+		return nil, err
+	}
+	metadata, err := s.resizer.DecodeMetadata(ctx, edited.Reader())
+	if err != nil {
+		return nil, err
+	}
+	path, err := s.storage.Upload(ctx, edited.Reader(), metadata.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	image.FocalPoint = edit.FocalPoint(image.FocalPoint, bounds.Dx(), bounds.Dy())
+	image.ReplaceOriginal(path, edited.Size(), hex.EncodeToString(hash.Sum(nil)), metadata)
+	stale := image.RemoveReadySizes()
+
+	image, err = s.doResize(ctx, image, edited.Reader(), stale)
+	if err != nil {
+		// the sizes stored before the failure are discarded by doResize
+		s.discardUpload(ctx, &model.Image{Path: path})
+		return nil, err
+	}
+
+	return image, nil
+}
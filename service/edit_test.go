@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_EditOriginal(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, nil, 1, Limits{}, MetadataConfig{})
+
+	upload := func() *model.Image {
+		image, err := srv.Upload(ctx, model.ImageUpload{
+			Content:  bytes.NewReader(content),
+			Filename: "image.jpg",
+			Size:     int64(len(content)),
+			MimeType: "image/jpeg",
+			Dedupe:   true,
+		}, []model.SizeRequest{
+			{Width: 100, Height: 100},
+			{Width: 50, Height: 50, Mode: model.ResizeModeFill, Anchor: model.AnchorSmart},
+		})
+		assert.NoError(t, err)
+		return image
+	}
+
+	uploaded := upload()
+	_, err = srv.SetFocalPoint(ctx, uploaded.ID, model.FocalPoint{X: 0.25, Y: 0.1})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.cleanup(ctx, uploaded.ID))
+	uploaded, err = srv.Image(ctx, uploaded.ID)
+	assert.NoError(t, err)
+
+	rotated, err := srv.RotateImage(ctx, uploaded.ID, 90)
+	assert.NoError(t, err)
+	assert.Equal(t, uploaded.Version+1, rotated.Version)
+	assert.Equal(t, uploaded.Height, rotated.Width)
+	assert.Equal(t, uploaded.Width, rotated.Height)
+	assert.NotEqual(t, uploaded.Path, rotated.Path)
+	assert.NotEqual(t, uploaded.Hash, rotated.Hash)
+	assert.False(t, rotated.Shared)
+	assert.Equal(t, &model.FocalPoint{X: 0.9, Y: 0.25}, rotated.FocalPoint)
+	// sizes are rendered from the rotated original
+	assert.Len(t, rotated.Sizes, 2)
+	for n, size := range rotated.Sizes {
+		assert.Equal(t, uploaded.Sizes[n].Width, size.Width)
+		assert.NotEqual(t, uploaded.Sizes[n].Path, size.Path)
+	}
+
+	// the previous original and sizes are removed
+	assert.NoError(t, srv.cleanup(ctx, uploaded.ID))
+	assert.Len(t, storage.Keys(), 3)
+
+	// the edited image isn't returned for the content uploaded before
+	assert.NotEqual(t, uploaded.ID, upload().ID)
+
+	cropped, err := srv.CropImage(ctx, uploaded.ID, model.Rect{X: 10, Y: 20, Width: 30, Height: 40})
+	assert.NoError(t, err)
+	assert.Equal(t, 30, cropped.Width)
+	assert.Equal(t, 40, cropped.Height)
+	// the focal point is cropped out
+	assert.Nil(t, cropped.FocalPoint)
+
+	flipped, err := srv.FlipImage(ctx, uploaded.ID, model.FlipAxisHorizontal)
+	assert.NoError(t, err)
+	assert.Equal(t, cropped.Version+1, flipped.Version)
+	assert.Equal(t, 30, flipped.Width)
+}
+
+func TestImageService_EditOriginalInvalid(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, nil, 1, Limits{}, MetadataConfig{})
+
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+	image, err := srv.Upload(ctx, model.ImageUpload{
+		Content:  bytes.NewReader(content),
+		Filename: "image.jpg",
+		Size:     int64(len(content)),
+		MimeType: "image/jpeg",
+	}, nil)
+	assert.NoError(t, err)
+
+	_, err = srv.RotateImage(ctx, image.ID, 45)
+	assert.Equal(t, errors.InvalidParams{{Param: "Rotate", Message: "oneof"}}, err)
+
+	_, err = srv.FlipImage(ctx, image.ID, "diagonal")
+	assert.Equal(t, errors.InvalidParams{{Param: "Flip", Message: "oneof"}}, err)
+
+	_, err = srv.CropImage(ctx, image.ID, model.Rect{Width: 0, Height: 10})
+	assert.Equal(t, errors.InvalidParams{{Param: "Width", Message: "required"}}, err)
+
+	_, err = srv.CropImage(ctx, image.ID, model.Rect{X: image.Width, Width: 10, Height: 10})
+	assert.Equal(t, errors.InvalidParams{{Param: "Crop", Message: "bounds"}}, err)
+
+	_, err = srv.RotateImage(ctx, "unknown", 90)
+	assert.Equal(t, errors.NotFound, err)
+
+	// nothing is changed
+	saved, err := srv.Image(ctx, image.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Version, saved.Version)
+	assert.Len(t, storage.Keys(), 1)
+}
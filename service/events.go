@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/portey/image-resizer/model"
+)
+
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan *model.Image]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{
+		subscribers: map[string]map[chan *model.Image]struct{}{},
+	}
+}
+
+// subscribe sends the image returned by current at once unless it's nil, so an event published
+// before the subscription isn't missed.
+func (b *broker) subscribe(ctx context.Context, imageID string, current func() *model.Image) <-chan *model.Image {
+	ch := make(chan *model.Image, 1)
+
+	b.mu.Lock()
+	if b.subscribers[imageID] == nil {
+		b.subscribers[imageID] = map[chan *model.Image]struct{}{}
+	}
+	b.subscribers[imageID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		if image := current(); image != nil {
+			b.mu.Lock()
+			if _, ok := b.subscribers[imageID][ch]; ok {
+				select {
+				case ch <- image:
+				default:
+					// an image published meanwhile is newer
+				}
+			}
+			b.mu.Unlock()
+		}
+
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers[imageID], ch)
+		if len(b.subscribers[imageID]) == 0 {
+			delete(b.subscribers, imageID)
+		}
+		b.mu.Unlock()
+
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (b *broker) publish(image *model.Image) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[image.ID] {
+		select {
+		case ch <- image:
+		default:
+			// a slow subscriber misses the event rather than blocking the job runner
+		}
+	}
+}
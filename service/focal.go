@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+)
+
+func (s *ImageService) SetFocalPoint(ctx context.Context, id string, point model.FocalPoint) (*model.Image, error) {
+	if err := s.validateParams(point); len(err) > 0 {
+		return nil, err
+	}
+
+	for i := 0; ; i++ {
+		image, err := s.getImage(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := image.Sizes
+		stale := image.RemoveSmartSizes()
+		image.FocalPoint = &point
+		if len(stale) > 0 {
+			reader, err := s.storage.Read(ctx, image.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			image, err = s.doResize(ctx, image, reader, stale)
+			closeReader(reader)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		version := image.Version
+		image.Version++
+		err = s.repo.Save(ctx, version, *image)
+		if err == errors.RaceCondition && i < saveRetries {
+			s.discardUpload(ctx, renderedSizes(image, previous))
+			continue
+		}
+		if err != nil {
+			s.discardUpload(ctx, renderedSizes(image, previous))
+			return nil, err
+		}
+
+		if len(image.Trash) > 0 {
+			s.scheduleCleanup(ctx, id)
+		}
+
+		return image, nil
+	}
+}
+
+func renderedSizes(image *model.Image, previous []model.Size) *model.Image {
+	kept := make(map[string]bool, len(previous))
+	for _, size := range previous {
+		kept[size.Path] = true
+	}
+
+	rendered := &model.Image{ID: image.ID}
+	for _, size := range image.Sizes {
+		if !kept[size.Path] {
+			rendered.Sizes = append(rendered.Sizes, size)
+		}
+	}
+
+	return rendered
+}
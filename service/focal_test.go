@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_SetFocalPoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	smart := model.SizeRequest{Width: 100, Height: 100, Mode: model.ResizeModeFill, Anchor: model.AnchorSmart}
+	stored := &model.Image{
+		ID:       "id",
+		Path:     "some/path/test.png",
+		MimeType: "image/png",
+		Version:  1,
+	}
+	stored.AddSize("some/resized/smart.png", 10, smart.WithSourceFormat(stored.MimeType))
+	stored.AddSize("some/resized/stretch.png", 10, model.SizeRequest{Width: 100, Height: 100})
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/test.png")).
+		DoAndReturn(func(context.Context, string) (io.Reader, error) {
+			return strings.NewReader("Some content"), nil
+		}).
+		Times(2)
+	// the retry renders the same variant again, it's content addressed
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(100), gomock.Eq(100), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			assert.NoError(t, err)
+
+			return "some/resized/focal.png", nil
+		}).
+		Times(2)
+	// only the render of the unsaved attempt is discarded, the original and the kept size stay
+	storage.EXPECT().
+		Delete(gomock.Any(), gomock.Eq("some/resized/focal.png")).
+		Return(nil)
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Any(), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil).
+		Times(2)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, request model.SizeRequest) error {
+			// the smart size is cropped around the new focal point
+			assert.Equal(t, model.AnchorSmart, request.Anchor)
+			assert.Equal(t, &model.FocalPoint{X: 0.25, Y: 0.75}, request.FocalPoint)
+			_, err := out.Write([]byte("resized"))
+
+			return err
+		}).
+		Times(2)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			copied := *stored
+			copied.Sizes = append([]model.Size{}, stored.Sizes...)
+			return &copied, nil
+		}).
+		Times(2)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		Return(errors.RaceCondition)
+	repo.EXPECT().
+		Referenced(gomock.Any(), gomock.Eq("some/resized/focal.png")).
+		Return(false, nil)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, i model.Image) error {
+			stored = &i
+			return nil
+		})
+
+	jobs := mock.NewMockJobRepository(ctrl)
+	jobs.EXPECT().
+		SaveJob(gomock.Eq(ctx), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job model.Job) error {
+			assert.Equal(t, model.JobKindCleanup, job.Kind)
+			return nil
+		})
+
+	srv := New(storage, resizer, repo, jobs, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	i, err := srv.SetFocalPoint(ctx, "id", model.FocalPoint{X: 0.25, Y: 0.75})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.FocalPoint{X: 0.25, Y: 0.75}, i.FocalPoint)
+	assert.Equal(t, 2, i.Version)
+	assert.Equal(t, []string{"some/resized/stretch.png", "some/resized/focal.png"}, []string{i.Sizes[0].Path, i.Sizes[1].Path})
+	assert.Equal(t, []string{"some/resized/smart.png"}, stored.Trash)
+}
+
+func TestImageService_SetFocalPointInvalid(t *testing.T) {
+	srv := New(nil, nil, nil, nil, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	f := func(point model.FocalPoint, expected errors.InvalidParams) {
+		_, err := srv.SetFocalPoint(context.Background(), "id", point)
+		assert.Equal(t, expected, err)
+	}
+
+	f(model.FocalPoint{X: -0.1, Y: 0.5}, errors.InvalidParams{{Param: "X", Message: "min"}})
+	f(model.FocalPoint{X: 0.5, Y: 1.1}, errors.InvalidParams{{Param: "Y", Message: "max"}})
+}
+
+func TestImageService_SmartAnchorRequiresFill(t *testing.T) {
+	srv := New(nil, nil, nil, nil, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	_, err := srv.Resize(context.Background(), "id", []model.SizeRequest{{Width: 100, Height: 100, Mode: model.ResizeModeFit, Anchor: model.AnchorSmart}})
+	assert.Equal(t, errors.InvalidParams{{Param: "Anchor", Message: "mode=fill"}}, err)
+
+	_, err = srv.Resize(context.Background(), "id", []model.SizeRequest{{Width: 100, Height: 100, Anchor: model.AnchorSmart}})
+	assert.Equal(t, errors.InvalidParams{{Param: "Anchor", Message: "mode=fill"}}, err)
+}
+
+func TestImageService_SetFocalPointWithoutSmartSizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		Return(&model.Image{ID: "id", Path: "some/path/test.png", MimeType: "image/png", Version: 1}, nil)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		Return(nil)
+
+	// nothing is rendered or removed
+	srv := New(mock.NewMockStorage(ctrl), mock.NewMockResizer(ctrl), repo, nil, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	i, err := srv.SetFocalPoint(ctx, "id", model.FocalPoint{X: 0, Y: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.FocalPoint{X: 0, Y: 1}, i.FocalPoint)
+	assert.Empty(t, i.Trash)
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	jobQueueSize    = 1024
+	jobScanInterval = time.Minute
+	saveRetries     = 3
+)
+
+func (s *ImageService) UploadAsync(ctx context.Context, upload model.ImageUpload, sizes []model.SizeRequest) (*model.Image, error) {
+	image, existing, err := s.uploadOriginal(ctx, upload, sizes, nil)
+	if err != nil {
+		return nil, err
+	}
+	version := 0
+	if existing {
+		// the same content is uploaded already, missing sizes are added to the shared image
+		version = image.Version
+		image.Version++
+	}
+
+	now := time.Now()
+	job := model.Job{
+		ID:        uuid.NewV4().String(),
+		ImageID:   image.ID,
+		Status:    model.JobStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, size := range sizes {
+		size := size.WithSourceFormat(image.MimeType)
+		image.AddPendingSize(size)
+		job.Sizes = append(job.Sizes, size)
+	}
+	image.JobID = job.ID
+
+	discard := func() {
+		if !existing {
+			s.discardUpload(ctx, &model.Image{ID: image.ID, Path: image.Path})
+		}
+	}
+	if err := s.jobs.SaveJob(ctx, job); err != nil {
+		discard()
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, version, *image); err != nil {
+		// e.g. a concurrent deduplicated upload of the same content won, the upload can be retried
+		discard()
+
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+		job.UpdatedAt = time.Now()
+		if err := s.jobs.SaveJob(ctx, job); err != nil {
+			log.Error("can't save job of unsaved image ", job.ID, " ", err)
+		}
+
+		return nil, err
+	}
+
+	s.enqueue(job.ID)
+
+	return image, nil
+}
+
+func (s *ImageService) Job(ctx context.Context, id string) (*model.Job, error) {
+	return s.jobs.GetJob(ctx, id)
+}
+
+// Subscribe receives the image at once if its job is finished already, so a job which finished before
+// the subscription isn't missed.
+func (s *ImageService) Subscribe(ctx context.Context, imageID string) <-chan *model.Image {
+	return s.events.subscribe(ctx, imageID, func() *model.Image {
+		return s.processedImage(ctx, imageID)
+	})
+}
+
+func (s *ImageService) processedImage(ctx context.Context, imageID string) *model.Image {
+	image, err := s.getImage(ctx, imageID)
+	if err != nil || image.JobID == "" {
+		return nil
+	}
+
+	job, err := s.jobs.GetJob(ctx, image.JobID)
+	if err != nil || !job.Finished() {
+		return nil
+	}
+
+	return image
+}
+
+func (s *ImageService) RunJobs(ctx context.Context, wg *sync.WaitGroup, workers int) {
+	log.Info("job runner: begin run")
+	s.lifetime = ctx
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					s.processJob(ctx, id)
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(jobScanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.queueOverflowed(ctx)
+			}
+		}
+	}()
+
+	unfinished, err := s.jobs.ListUnfinishedJobs(ctx)
+	if err != nil {
+		log.Error("job runner: can't list unfinished jobs ", err)
+		return
+	}
+
+	go func() {
+		for _, job := range unfinished {
+			s.queueJob(ctx, job.ID)
+		}
+		s.sweepTrash(ctx, unfinished)
+	}()
+}
+
+// sweepTrash schedules cleanups of trashed images which have none, e.g. the cleanup job of a mutation wasn't saved.
+func (s *ImageService) sweepTrash(ctx context.Context, unfinished []*model.Job) {
+	scheduled := make(map[string]bool)
+	for _, job := range unfinished {
+		if job.Kind == model.JobKindCleanup {
+			scheduled[job.ImageID] = true
+		}
+	}
+
+	ids, err := s.repo.ListTrashed(ctx)
+	if err != nil {
+		log.Error("job runner: can't list trashed images ", err)
+		return
+	}
+	for _, id := range ids {
+		if scheduled[id] {
+			continue
+		}
+		s.scheduleCleanup(ctx, id)
+	}
+}
+
+// enqueue doesn't block the request, a job which doesn't fit the full queue stays pending
+// and is queued by the next scan of the runner.
+func (s *ImageService) enqueue(id string) {
+	if !s.trackJob(id) {
+		return
+	}
+
+	select {
+	case s.queue <- id:
+	default:
+		s.untrackJob(id)
+		s.queueMu.Lock()
+		s.overflowed = true
+		s.queueMu.Unlock()
+		log.Warn("job runner: queue is full, job is queued by the next scan ", id)
+	}
+}
+
+func (s *ImageService) queueJob(ctx context.Context, id string) {
+	if !s.trackJob(id) {
+		return
+	}
+
+	select {
+	case s.queue <- id:
+	case <-ctx.Done():
+		s.untrackJob(id)
+		log.Warn("job runner: job isn't queued, it's resumed on restart ", id)
+	}
+}
+
+// queueOverflowed doesn't queue started jobs, their retries are queued by the process running them.
+func (s *ImageService) queueOverflowed(ctx context.Context) {
+	s.queueMu.Lock()
+	overflowed := s.overflowed
+	s.overflowed = false
+	s.queueMu.Unlock()
+	if !overflowed {
+		return
+	}
+
+	unfinished, err := s.jobs.ListUnfinishedJobs(ctx)
+	if err != nil {
+		log.Error("job runner: can't list unfinished jobs ", err)
+		s.queueMu.Lock()
+		s.overflowed = true
+		s.queueMu.Unlock()
+		return
+	}
+	for _, job := range unfinished {
+		if job.Status == model.JobStatusPending && job.Attempts == 0 {
+			s.queueJob(ctx, job.ID)
+		}
+	}
+}
+
+func (s *ImageService) trackJob(id string) bool {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if s.queued[id] {
+		return false
+	}
+	s.queued[id] = true
+
+	return true
+}
+
+func (s *ImageService) untrackJob(id string) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	delete(s.queued, id)
+}
+
+func (s *ImageService) processJob(ctx context.Context, id string) {
+	retry := s.runJob(ctx, id)
+	s.untrackJob(id)
+
+	if retry > 0 {
+		time.AfterFunc(retry, func() {
+			s.queueJob(ctx, id)
+		})
+	}
+}
+
+func (s *ImageService) runJob(ctx context.Context, id string) time.Duration {
+	job, err := s.jobs.GetJob(ctx, id)
+	if err != nil {
+		log.Error("job runner: can't get job ", id, " ", err)
+		return 0
+	}
+	if job.Finished() {
+		return 0
+	}
+
+	job.Status = model.JobStatusRunning
+	job.Attempts++
+	job.UpdatedAt = time.Now()
+	if err := s.jobs.SaveJob(ctx, *job); err != nil {
+		log.Error("job runner: can't save job ", id, " ", err)
+		return 0
+	}
+
+	var image *model.Image
+	switch job.Kind {
+	case model.JobKindCleanup:
+		err = s.cleanup(ctx, job.ImageID)
+	case model.JobKindRemove:
+		err = s.removePaths(ctx, job)
+	case model.JobKindRerender:
+		image, err = s.rerender(ctx, job.ImageID, job.Sizes)
+	default:
+		image, err = s.resizeWithRetry(ctx, job.ImageID, job.Sizes)
+	}
+	if ctx.Err() != nil {
+		// the job is resumed on restart
+		return 0
+	}
+
+	job.Status = model.JobStatusDone
+	job.Error = ""
+	var retry time.Duration
+	if err != nil {
+		if err != errRecentlyWritten {
+			log.Error("job runner: job failed ", id, " ", err)
+		}
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+
+		removal := job.Kind == model.JobKindCleanup || job.Kind == model.JobKindRemove
+		switch {
+		case removal && err == errRecentlyWritten:
+			// kept objects are removed once the grace period is over
+			job.Status = model.JobStatusPending
+			retry = s.limits.CleanupGracePeriod
+		case removal:
+			// objects are kept until they're removed, so removals are retried until they succeed
+			job.Status = model.JobStatusPending
+			retry = time.Duration(job.Attempts) * cleanupRetryDelay
+			if retry > cleanupMaxRetryDelay {
+				retry = cleanupMaxRetryDelay
+			}
+		case job.Kind == model.JobKindResize:
+			image = s.failPendingSizes(ctx, job)
+		}
+	}
+	job.UpdatedAt = time.Now()
+	if err := s.jobs.SaveJob(ctx, *job); err != nil {
+		log.Error("job runner: can't save job ", id, " ", err)
+	}
+
+	if image != nil {
+		s.events.publish(image)
+	}
+
+	return retry
+}
+
+func (s *ImageService) resizeWithRetry(ctx context.Context, id string, sizes []model.SizeRequest) (*model.Image, error) {
+	var (
+		image *model.Image
+		err   error
+	)
+	for i := 0; i < saveRetries; i++ {
+		image, err = s.Resize(ctx, id, sizes)
+		if err != errors.RaceCondition {
+			break
+		}
+	}
+
+	return image, err
+}
+
+func (s *ImageService) failPendingSizes(ctx context.Context, job *model.Job) *model.Image {
+	for i := 0; i < saveRetries; i++ {
+		image, err := s.getImage(ctx, job.ImageID)
+		if err != nil {
+			log.Error("job runner: can't get image ", job.ImageID, " ", err)
+			return nil
+		}
+
+		image.FailPendingSizes(job.Sizes)
+		version := image.Version
+		image.Version++
+		err = s.repo.Save(ctx, version, *image)
+		if err == nil {
+			return image
+		}
+		if err != errors.RaceCondition {
+			log.Error("job runner: can't save image ", job.ImageID, " ", err)
+			return nil
+		}
+	}
+
+	return nil
+}
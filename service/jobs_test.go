@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	"github.com/portey/image-resizer/service/mock"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_UploadAsync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		saved *model.Image
+		jobs  = map[string]model.Job{}
+	)
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Upload(gomock.Any(), gomock.Any(), gomock.Eq(model.FormatPNG)).
+		Return("some/path/test.png", nil)
+	storage.EXPECT().
+		Read(gomock.Any(), gomock.Eq("some/path/test.png")).
+		Return(strings.NewReader("Some content"), nil)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(100), gomock.Eq(200), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "some/resized/test.png", err
+		})
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Any(), gomock.Any()).
+		Return(model.Metadata{Format: model.FormatPNG, Width: 10, Height: 10}, nil)
+	resizer.EXPECT().
+		Decode(gomock.Any(), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, _ model.SizeRequest) error {
+			_, err := out.Write([]byte("resized"))
+			return err
+		})
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Save(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, i model.Image) error {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = &i
+
+			return nil
+		}).
+		Times(2)
+	repo.EXPECT().
+		Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			copied := *saved
+
+			return &copied, nil
+		}).
+		MinTimes(1)
+	repo.EXPECT().
+		ListTrashed(gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+
+	jobRepo := mock.NewMockJobRepository(ctrl)
+	jobRepo.EXPECT().
+		SaveJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job model.Job) error {
+			mu.Lock()
+			defer mu.Unlock()
+			jobs[job.ID] = job
+
+			return nil
+		}).
+		Times(3)
+	jobRepo.EXPECT().
+		GetJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string) (*model.Job, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			job, ok := jobs[id]
+			if !ok {
+				return nil, errors.NotFound
+			}
+
+			return &job, nil
+		}).
+		AnyTimes()
+	jobRepo.EXPECT().
+		ListUnfinishedJobs(gomock.Any()).
+		Return(nil, nil)
+
+	srv := New(storage, resizer, repo, jobRepo, nil, nil, 2, Limits{}, MetadataConfig{})
+	i, err := srv.UploadAsync(ctx, model.ImageUpload{
+		Content:  strings.NewReader("Some content"),
+		Filename: "original.png",
+		Size:     123123,
+		MimeType: "image/png",
+	}, []model.SizeRequest{{
+		Width:  100,
+		Height: 200,
+	}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, i.JobID)
+	assert.Len(t, i.Sizes, 1)
+	assert.Equal(t, model.SizeStatusPending, i.Sizes[0].Status)
+	assert.Empty(t, i.Sizes[0].Path)
+
+	job, err := srv.Job(ctx, i.JobID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.JobStatusPending, job.Status)
+
+	events := srv.Subscribe(ctx, i.ID)
+
+	var wg sync.WaitGroup
+	srv.RunJobs(ctx, &wg, 1)
+
+	select {
+	case processed := <-events:
+		assert.Len(t, processed.Sizes, 1)
+		assert.Equal(t, model.SizeStatusReady, processed.Sizes[0].Status)
+		assert.Equal(t, "some/resized/test.png", processed.Sizes[0].Path)
+	case <-time.After(5 * time.Second):
+		t.Fatal("image isn't processed")
+	}
+
+	job, err = srv.Job(ctx, i.JobID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.JobStatusDone, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+
+	cancel()
+	wg.Wait()
+}
+
+type failingImages struct {
+	*repositorymemory.Repository
+	failing bool
+}
+
+func (r *failingImages) Save(ctx context.Context, version int, image model.Image) error {
+	if r.failing {
+		return errors.Internal
+	}
+
+	return r.Repository.Save(ctx, version, image)
+}
+
+func TestImageService_UploadAsyncSaveFails(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	memory := repositorymemory.New()
+	repo := &failingImages{Repository: memory}
+	jobs := &failingJobs{Repository: memory}
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, jobs, memory, memory, 1, Limits{}, MetadataConfig{})
+
+	upload := func() error {
+		_, err := srv.UploadAsync(ctx, model.ImageUpload{
+			Content:  bytes.NewReader(content),
+			Filename: "image.jpg",
+			Size:     int64(len(content)),
+			MimeType: "image/jpeg",
+		}, []model.SizeRequest{{Width: 100, Height: 100}})
+		return err
+	}
+
+	// the original isn't left in the storage if the job isn't saved
+	jobs.failing = true
+	assert.Equal(t, errors.Internal, upload())
+	jobs.failing = false
+	assert.Empty(t, storage.Keys())
+
+	// the original is removed and the saved job fails if the image isn't saved
+	repo.failing = true
+	assert.Equal(t, errors.Internal, upload())
+	repo.failing = false
+	assert.Empty(t, storage.Keys())
+
+	unfinished, err := memory.ListUnfinishedJobs(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, unfinished)
+}
+
+func TestImageService_EnqueueFullQueue(t *testing.T) {
+	ctx := context.Background()
+	jobs := repositorymemory.New()
+	srv := New(nil, nil, nil, jobs, nil, nil, 1, Limits{}, MetadataConfig{})
+	srv.queue = make(chan string, 1)
+
+	for _, job := range []model.Job{
+		{ID: "queued", Status: model.JobStatusPending, CreatedAt: time.Now()},
+		{ID: "overflowed", Status: model.JobStatusPending, CreatedAt: time.Now()},
+		{ID: "retried", Kind: model.JobKindCleanup, Status: model.JobStatusPending, Attempts: 1, CreatedAt: time.Now()},
+	} {
+		assert.NoError(t, jobs.SaveJob(ctx, job))
+	}
+
+	// the mutation isn't blocked by the full queue
+	srv.enqueue("queued")
+	srv.enqueue("overflowed")
+	assert.Equal(t, "queued", <-srv.queue)
+
+	// the scan queues the pending job, started jobs are queued by the process running them
+	srv.queueOverflowed(ctx)
+	assert.Equal(t, "overflowed", <-srv.queue)
+	assert.Empty(t, srv.queue)
+
+	// without an overflow jobs aren't listed again
+	srv.queueOverflowed(ctx)
+	assert.Empty(t, srv.queue)
+
+	// queued jobs aren't queued twice
+	srv.enqueue("overflowed")
+	assert.Empty(t, srv.queue)
+}
+
+func TestBroker(t *testing.T) {
+	b := newBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events := b.subscribe(ctx, "id", func() *model.Image { return nil })
+	b.publish(&model.Image{ID: "other"})
+	b.publish(&model.Image{ID: "id"})
+
+	event := <-events
+	assert.Equal(t, "id", event.ID)
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestImageService_SubscribeFinishedJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := repositorymemory.New()
+	srv := New(nil, nil, repo, repo, nil, nil, 1, Limits{}, MetadataConfig{})
+
+	assert.NoError(t, repo.SaveJob(ctx, model.Job{ID: "pending", ImageID: "waiting", Status: model.JobStatusPending}))
+	assert.NoError(t, repo.SaveJob(ctx, model.Job{ID: "done", ImageID: "processed", Status: model.JobStatusDone}))
+	assert.NoError(t, repo.Save(ctx, 0, model.Image{ID: "waiting", JobID: "pending", Version: 1}))
+	assert.NoError(t, repo.Save(ctx, 0, model.Image{ID: "processed", JobID: "done", Version: 1}))
+
+	// the job finished before the subscription, the image is received at once
+	select {
+	case image := <-srv.Subscribe(ctx, "processed"):
+		assert.Equal(t, "processed", image.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("processed image isn't received")
+	}
+
+	// the image of an unfinished job is received once the job finishes
+	waiting := srv.Subscribe(ctx, "waiting")
+	select {
+	case <-waiting:
+		t.Fatal("image of a pending job is received")
+	case <-time.After(50 * time.Millisecond):
+	}
+	srv.events.publish(&model.Image{ID: "waiting"})
+	assert.Equal(t, "waiting", (<-waiting).ID)
+}
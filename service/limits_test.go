@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_UploadLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Upload(gomock.Eq(ctx), gomock.Any(), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "", err
+		})
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		Return(model.Metadata{Format: model.FormatPNG, Width: 10, Height: 10}, nil)
+
+	srv := New(storage, resizer, nil, nil, nil, nil, 1, Limits{
+		MaxUploadBytes:     2000,
+		MaxOutputDimension: 1000,
+	}, MetadataConfig{})
+	upload := func(size int64, content string) model.ImageUpload {
+		return model.ImageUpload{
+			Content:  strings.NewReader(content),
+			Filename: "original.png",
+			Size:     size,
+			MimeType: "image/png",
+		}
+	}
+
+	// declared size exceeds the limit
+	_, err := srv.Upload(ctx, upload(3000, "Some content"), nil)
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	// actual content exceeds the limit
+	_, err = srv.Upload(ctx, upload(1500, strings.Repeat("a", 3000)), nil)
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	// requested size exceeds the limit
+	_, err = srv.Upload(ctx, upload(1500, "Some content"), []model.SizeRequest{{Width: 1001, Height: 100}})
+	assert.Equal(t, errors.ImageTooLarge, err)
+}
+
+func TestImageService_ResizeTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/test.png")).
+		Return(strings.NewReader("Some content"), nil).
+		Times(2)
+	// a slow upload isn't limited by the resize timeout
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(100), gomock.Eq(100), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			time.Sleep(100 * time.Millisecond)
+			_, err := ioutil.ReadAll(in)
+			return "some/resized/test.png", err
+		})
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Eq(ctx), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil).
+		Times(2)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(model.SizeRequest{Width: 200, Height: 200, Format: model.FormatPNG})).
+		DoAndReturn(func(ctx context.Context, _ image.Image, _ io.Writer, _ model.SizeRequest) error {
+			// a slow resize
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(model.SizeRequest{Width: 100, Height: 100, Format: model.FormatPNG})).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, _ model.SizeRequest) error {
+			_, err := out.Write([]byte("resized"))
+			return err
+		})
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		Return(&model.Image{ID: "id", Path: "some/path/test.png", MimeType: "image/png", Version: 1}, nil).
+		Times(2)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		Return(nil)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 1, Limits{ResizeTimeout: 50 * time.Millisecond}, MetadataConfig{})
+
+	_, err := srv.Resize(ctx, "id", []model.SizeRequest{{Width: 200, Height: 200}})
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	resized, err := srv.Resize(ctx, "id", []model.SizeRequest{{Width: 100, Height: 100}})
+	assert.NoError(t, err)
+	assert.Equal(t, "some/resized/test.png", resized.Sizes[0].Path)
+	assert.Equal(t, int64(len("resized")), resized.Sizes[0].Bytes)
+}
+
+func TestImageService_UploadSpool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Upload(gomock.Eq(ctx), gomock.Any(), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "some/path/test.png", err
+		})
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(100), gomock.Eq(100), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "some/resized/test.png", err
+		})
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		Return(model.Metadata{Format: model.FormatPNG}, nil)
+	// the original exceeding the memory limit is read from the temp file
+	resizer.EXPECT().
+		Decode(gomock.Eq(ctx), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in io.Reader) (image.Image, error) {
+			content, err := ioutil.ReadAll(in)
+			assert.Equal(t, "Some content", string(content))
+			return imaging.New(10, 10, color.White), err
+		})
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(0), gomock.Any()).
+		Return(nil)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 1, Limits{MaxMemoryBytes: 4}, MetadataConfig{})
+
+	image, err := srv.Upload(ctx, model.ImageUpload{
+		Content:  strings.NewReader("Some content"),
+		Filename: "original.png",
+		Size:     1000,
+		MimeType: "image/png",
+	}, []model.SizeRequest{{Width: 100, Height: 100}})
+	assert.NoError(t, err)
+	assert.Len(t, image.Sizes, 1)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_Memory(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, nil, 1, Limits{}, MetadataConfig{})
+
+	upload := func() *model.Image {
+		image, err := srv.Upload(ctx, model.ImageUpload{
+			Content:  bytes.NewReader(content),
+			Filename: "image.jpg",
+			Size:     int64(len(content)),
+			MimeType: "image/jpeg",
+			Dedupe:   true,
+		}, []model.SizeRequest{{Width: 100, Height: 100}})
+		assert.NoError(t, err)
+		return image
+	}
+
+	image := upload()
+	assert.Len(t, image.Sizes, 1)
+	assert.Len(t, storage.Keys(), 2)
+
+	// the same content returns the stored image
+	same := upload()
+	assert.Equal(t, image.ID, same.ID)
+	assert.Len(t, storage.Keys(), 2)
+
+	saved, err := srv.Image(ctx, image.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, same, saved)
+
+	assert.NoError(t, srv.DeleteImage(ctx, image.ID))
+	assert.NoError(t, srv.cleanup(ctx, image.ID))
+
+	_, err = repo.Get(ctx, image.ID)
+	assert.Equal(t, errors.NotFound, err)
+	assert.Empty(t, storage.Keys())
+}
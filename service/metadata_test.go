@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_UploadMetadataPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	var stored string
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Upload(gomock.Eq(ctx), gomock.Any(), gomock.Eq(model.FormatJPEG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _ model.Format) (string, error) {
+			content, err := ioutil.ReadAll(in)
+			stored = string(content)
+			return "some/path/test.jpeg", err
+		}).
+		AnyTimes()
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		Return(model.Metadata{Format: model.FormatJPEG, Orientation: 6, Exif: &model.Exif{Make: "Canon", HasGPS: true}}, nil).
+		AnyTimes()
+	resizer.EXPECT().
+		StripMetadata(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Eq(model.FormatJPEG), gomock.Any(), gomock.Eq(model.MetadataMethodRewrite)).
+		DoAndReturn(func(_ context.Context, in io.Reader, out io.Writer, _ model.Format, policy model.MetadataPolicy, _ model.MetadataMethod) error {
+			if _, err := ioutil.ReadAll(in); err != nil {
+				return err
+			}
+			_, err := io.WriteString(out, string(policy))
+			return err
+		}).
+		AnyTimes()
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(0), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 1, Limits{}, MetadataConfig{
+		Policy: model.MetadataPolicyStripGPS,
+		Method: model.MetadataMethodRewrite,
+	})
+
+	f := func(policy model.MetadataPolicy, expectedPolicy model.MetadataPolicy, expectedContent string, expectedExif *model.Exif) {
+		image, err := srv.Upload(ctx, model.ImageUpload{
+			Content:        strings.NewReader("Some content"),
+			Filename:       "original.jpeg",
+			Size:           1000,
+			MimeType:       "image/jpeg",
+			MetadataPolicy: policy,
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedContent, stored)
+		assert.Equal(t, expectedPolicy, image.MetadataPolicy)
+		assert.Equal(t, expectedExif, image.Exif)
+		assert.Equal(t, 6, image.Orientation)
+		if expectedPolicy != model.MetadataPolicyKeep {
+			assert.Equal(t, int64(len(expectedContent)), image.Size)
+		}
+	}
+
+	// the default policy of the service
+	f("", model.MetadataPolicyStripGPS, "strip-gps", &model.Exif{Make: "Canon"})
+	// the policy of the upload
+	f(model.MetadataPolicyStripAll, model.MetadataPolicyStripAll, "strip-all", nil)
+	f(model.MetadataPolicyKeep, model.MetadataPolicyKeep, "Some content", &model.Exif{Make: "Canon", HasGPS: true})
+}
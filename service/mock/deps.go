@@ -8,8 +8,10 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/portey/image-resizer/model"
+	image "image"
 	io "io"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepository is a mock of Repository interface
@@ -51,18 +53,108 @@ func (mr *MockRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 }
 
 // List mocks base method
-func (m *MockRepository) List(ctx context.Context, limit, offset int) ([]*model.Image, error) {
+func (m *MockRepository) List(ctx context.Context, filter model.ImageFilter, sort model.ImageSort, limit, offset int) ([]*model.Image, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, filter, sort, limit, offset)
 	ret0, _ := ret[0].([]*model.Image)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List
-func (mr *MockRepositoryMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) List(ctx, filter, sort, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), ctx, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), ctx, filter, sort, limit, offset)
+}
+
+// ListAfter mocks base method
+func (m *MockRepository) ListAfter(ctx context.Context, filter model.ImageFilter, after *model.ImageCursor, limit int) ([]*model.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]*model.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter
+func (mr *MockRepositoryMockRecorder) ListAfter(ctx, filter, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockRepository)(nil).ListAfter), ctx, filter, after, limit)
+}
+
+// Count mocks base method
+func (m *MockRepository) Count(ctx context.Context, filter model.ImageFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockRepositoryMockRecorder) Count(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepository)(nil).Count), ctx, filter)
+}
+
+// ListPresetImages mocks base method
+func (m *MockRepository) ListPresetImages(ctx context.Context, preset, after string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPresetImages", ctx, preset, after, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPresetImages indicates an expected call of ListPresetImages
+func (mr *MockRepositoryMockRecorder) ListPresetImages(ctx, preset, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPresetImages", reflect.TypeOf((*MockRepository)(nil).ListPresetImages), ctx, preset, after, limit)
+}
+
+// GetShared mocks base method
+func (m *MockRepository) GetShared(ctx context.Context, hash string) (*model.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShared", ctx, hash)
+	ret0, _ := ret[0].(*model.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShared indicates an expected call of GetShared
+func (mr *MockRepositoryMockRecorder) GetShared(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShared", reflect.TypeOf((*MockRepository)(nil).GetShared), ctx, hash)
+}
+
+// ListTrashed mocks base method
+func (m *MockRepository) ListTrashed(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrashed", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrashed indicates an expected call of ListTrashed
+func (mr *MockRepositoryMockRecorder) ListTrashed(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrashed", reflect.TypeOf((*MockRepository)(nil).ListTrashed), ctx)
+}
+
+// Referenced mocks base method
+func (m *MockRepository) Referenced(ctx context.Context, path string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Referenced", ctx, path)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Referenced indicates an expected call of Referenced
+func (mr *MockRepositoryMockRecorder) Referenced(ctx, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Referenced", reflect.TypeOf((*MockRepository)(nil).Referenced), ctx, path)
 }
 
 // Save mocks base method
@@ -79,6 +171,249 @@ func (mr *MockRepositoryMockRecorder) Save(ctx, version, image interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepository)(nil).Save), ctx, version, image)
 }
 
+// Delete mocks base method
+func (m *MockRepository) Delete(ctx context.Context, id string, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id, version)
+}
+
+// MockJobRepository is a mock of JobRepository interface
+type MockJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepositoryMockRecorder
+}
+
+// MockJobRepositoryMockRecorder is the mock recorder for MockJobRepository
+type MockJobRepositoryMockRecorder struct {
+	mock *MockJobRepository
+}
+
+// NewMockJobRepository creates a new mock instance
+func NewMockJobRepository(ctrl *gomock.Controller) *MockJobRepository {
+	mock := &MockJobRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockJobRepository) EXPECT() *MockJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetJob mocks base method
+func (m *MockJobRepository) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, id)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob
+func (mr *MockJobRepositoryMockRecorder) GetJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockJobRepository)(nil).GetJob), ctx, id)
+}
+
+// SaveJob mocks base method
+func (m *MockJobRepository) SaveJob(ctx context.Context, job model.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveJob indicates an expected call of SaveJob
+func (mr *MockJobRepositoryMockRecorder) SaveJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveJob", reflect.TypeOf((*MockJobRepository)(nil).SaveJob), ctx, job)
+}
+
+// ListUnfinishedJobs mocks base method
+func (m *MockJobRepository) ListUnfinishedJobs(ctx context.Context) ([]*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnfinishedJobs", ctx)
+	ret0, _ := ret[0].([]*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnfinishedJobs indicates an expected call of ListUnfinishedJobs
+func (mr *MockJobRepositoryMockRecorder) ListUnfinishedJobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnfinishedJobs", reflect.TypeOf((*MockJobRepository)(nil).ListUnfinishedJobs), ctx)
+}
+
+// MockPresetRepository is a mock of PresetRepository interface
+type MockPresetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresetRepositoryMockRecorder
+}
+
+// MockPresetRepositoryMockRecorder is the mock recorder for MockPresetRepository
+type MockPresetRepositoryMockRecorder struct {
+	mock *MockPresetRepository
+}
+
+// NewMockPresetRepository creates a new mock instance
+func NewMockPresetRepository(ctrl *gomock.Controller) *MockPresetRepository {
+	mock := &MockPresetRepository{ctrl: ctrl}
+	mock.recorder = &MockPresetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPresetRepository) EXPECT() *MockPresetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPreset mocks base method
+func (m *MockPresetRepository) GetPreset(ctx context.Context, name string) (*model.Preset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreset", ctx, name)
+	ret0, _ := ret[0].(*model.Preset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreset indicates an expected call of GetPreset
+func (mr *MockPresetRepositoryMockRecorder) GetPreset(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreset", reflect.TypeOf((*MockPresetRepository)(nil).GetPreset), ctx, name)
+}
+
+// ListPresets mocks base method
+func (m *MockPresetRepository) ListPresets(ctx context.Context) ([]*model.Preset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPresets", ctx)
+	ret0, _ := ret[0].([]*model.Preset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPresets indicates an expected call of ListPresets
+func (mr *MockPresetRepositoryMockRecorder) ListPresets(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPresets", reflect.TypeOf((*MockPresetRepository)(nil).ListPresets), ctx)
+}
+
+// SavePreset mocks base method
+func (m *MockPresetRepository) SavePreset(ctx context.Context, version int, preset model.Preset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePreset", ctx, version, preset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePreset indicates an expected call of SavePreset
+func (mr *MockPresetRepositoryMockRecorder) SavePreset(ctx, version, preset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreset", reflect.TypeOf((*MockPresetRepository)(nil).SavePreset), ctx, version, preset)
+}
+
+// DeletePreset mocks base method
+func (m *MockPresetRepository) DeletePreset(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePreset", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePreset indicates an expected call of DeletePreset
+func (mr *MockPresetRepositoryMockRecorder) DeletePreset(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreset", reflect.TypeOf((*MockPresetRepository)(nil).DeletePreset), ctx, name)
+}
+
+// MockWatermarkRepository is a mock of WatermarkRepository interface
+type MockWatermarkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatermarkRepositoryMockRecorder
+}
+
+// MockWatermarkRepositoryMockRecorder is the mock recorder for MockWatermarkRepository
+type MockWatermarkRepositoryMockRecorder struct {
+	mock *MockWatermarkRepository
+}
+
+// NewMockWatermarkRepository creates a new mock instance
+func NewMockWatermarkRepository(ctrl *gomock.Controller) *MockWatermarkRepository {
+	mock := &MockWatermarkRepository{ctrl: ctrl}
+	mock.recorder = &MockWatermarkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWatermarkRepository) EXPECT() *MockWatermarkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetWatermark mocks base method
+func (m *MockWatermarkRepository) GetWatermark(ctx context.Context, id string) (*model.Watermark, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatermark", ctx, id)
+	ret0, _ := ret[0].(*model.Watermark)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatermark indicates an expected call of GetWatermark
+func (mr *MockWatermarkRepositoryMockRecorder) GetWatermark(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatermark", reflect.TypeOf((*MockWatermarkRepository)(nil).GetWatermark), ctx, id)
+}
+
+// ListWatermarks mocks base method
+func (m *MockWatermarkRepository) ListWatermarks(ctx context.Context) ([]*model.Watermark, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWatermarks", ctx)
+	ret0, _ := ret[0].([]*model.Watermark)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWatermarks indicates an expected call of ListWatermarks
+func (mr *MockWatermarkRepositoryMockRecorder) ListWatermarks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWatermarks", reflect.TypeOf((*MockWatermarkRepository)(nil).ListWatermarks), ctx)
+}
+
+// SaveWatermark mocks base method
+func (m *MockWatermarkRepository) SaveWatermark(ctx context.Context, version int, watermark model.Watermark) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWatermark", ctx, version, watermark)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWatermark indicates an expected call of SaveWatermark
+func (mr *MockWatermarkRepositoryMockRecorder) SaveWatermark(ctx, version, watermark interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWatermark", reflect.TypeOf((*MockWatermarkRepository)(nil).SaveWatermark), ctx, version, watermark)
+}
+
+// DeleteWatermark mocks base method
+func (m *MockWatermarkRepository) DeleteWatermark(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatermark", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatermark indicates an expected call of DeleteWatermark
+func (mr *MockWatermarkRepositoryMockRecorder) DeleteWatermark(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatermark", reflect.TypeOf((*MockWatermarkRepository)(nil).DeleteWatermark), ctx, id)
+}
+
 // MockResizer is a mock of Resizer interface
 type MockResizer struct {
 	ctrl     *gomock.Controller
@@ -102,18 +437,76 @@ func (m *MockResizer) EXPECT() *MockResizerMockRecorder {
 	return m.recorder
 }
 
+// DecodeMetadata mocks base method
+func (m *MockResizer) DecodeMetadata(ctx context.Context, data io.Reader) (model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecodeMetadata", ctx, data)
+	ret0, _ := ret[0].(model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecodeMetadata indicates an expected call of DecodeMetadata
+func (mr *MockResizerMockRecorder) DecodeMetadata(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeMetadata", reflect.TypeOf((*MockResizer)(nil).DecodeMetadata), ctx, data)
+}
+
+// Decode mocks base method
+func (m *MockResizer) Decode(ctx context.Context, data io.Reader) (image.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", ctx, data)
+	ret0, _ := ret[0].(image.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decode indicates an expected call of Decode
+func (mr *MockResizerMockRecorder) Decode(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockResizer)(nil).Decode), ctx, data)
+}
+
 // Resize mocks base method
-func (m *MockResizer) Resize(ctx context.Context, data io.Reader, output io.Writer, width, height int) error {
+func (m *MockResizer) Resize(ctx context.Context, img image.Image, output io.Writer, request model.SizeRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Resize", ctx, data, output, width, height)
+	ret := m.ctrl.Call(m, "Resize", ctx, img, output, request)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Resize indicates an expected call of Resize
-func (mr *MockResizerMockRecorder) Resize(ctx, data, output, width, height interface{}) *gomock.Call {
+func (mr *MockResizerMockRecorder) Resize(ctx, img, output, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockResizer)(nil).Resize), ctx, data, output, width, height)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockResizer)(nil).Resize), ctx, img, output, request)
+}
+
+// Edit mocks base method
+func (m *MockResizer) Edit(ctx context.Context, img image.Image, output io.Writer, edit model.Edit, format model.Format) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, img, output, edit, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Edit indicates an expected call of Edit
+func (mr *MockResizerMockRecorder) Edit(ctx, img, output, edit, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockResizer)(nil).Edit), ctx, img, output, edit, format)
+}
+
+// StripMetadata mocks base method
+func (m *MockResizer) StripMetadata(ctx context.Context, data io.Reader, output io.Writer, format model.Format, policy model.MetadataPolicy, method model.MetadataMethod) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StripMetadata", ctx, data, output, format, policy, method)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StripMetadata indicates an expected call of StripMetadata
+func (mr *MockResizerMockRecorder) StripMetadata(ctx, data, output, format, policy, method interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StripMetadata", reflect.TypeOf((*MockResizer)(nil).StripMetadata), ctx, data, output, format, policy, method)
 }
 
 // MockStorage is a mock of Storage interface
@@ -155,31 +548,60 @@ func (mr *MockStorageMockRecorder) Read(ctx, path interface{}) *gomock.Call {
 }
 
 // Upload mocks base method
-func (m *MockStorage) Upload(ctx context.Context, data io.Reader) (string, error) {
+func (m *MockStorage) Upload(ctx context.Context, data io.Reader, format model.Format) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", ctx, data)
+	ret := m.ctrl.Call(m, "Upload", ctx, data, format)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Upload indicates an expected call of Upload
-func (mr *MockStorageMockRecorder) Upload(ctx, data interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Upload(ctx, data, format interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStorage)(nil).Upload), ctx, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStorage)(nil).Upload), ctx, data, format)
 }
 
 // UploadResized mocks base method
-func (m *MockStorage) UploadResized(ctx context.Context, data io.Reader, width, height int) (string, error) {
+func (m *MockStorage) UploadResized(ctx context.Context, data io.Reader, width, height int, format model.Format) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadResized", ctx, data, width, height)
+	ret := m.ctrl.Call(m, "UploadResized", ctx, data, width, height, format)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UploadResized indicates an expected call of UploadResized
-func (mr *MockStorageMockRecorder) UploadResized(ctx, data, width, height interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UploadResized(ctx, data, width, height, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadResized", reflect.TypeOf((*MockStorage)(nil).UploadResized), ctx, data, width, height, format)
+}
+
+// Modified mocks base method
+func (m *MockStorage) Modified(ctx context.Context, path string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Modified", ctx, path)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Modified indicates an expected call of Modified
+func (mr *MockStorageMockRecorder) Modified(ctx, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Modified", reflect.TypeOf((*MockStorage)(nil).Modified), ctx, path)
+}
+
+// Delete mocks base method
+func (m *MockStorage) Delete(ctx context.Context, path string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, path)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockStorageMockRecorder) Delete(ctx, path interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadResized", reflect.TypeOf((*MockStorage)(nil).UploadResized), ctx, data, width, height)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, path)
 }
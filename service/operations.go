@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/portey/image-resizer/model"
+)
+
+// operationRanges are inclusive ranges of operation values, operations without a range don't take a value.
+var operationRanges = map[model.OperationName][2]float64{
+	model.OperationBlur:       {0.1, 50},
+	model.OperationSharpen:    {0.1, 50},
+	model.OperationGamma:      {0.1, 10},
+	model.OperationContrast:   {-100, 100},
+	model.OperationBrightness: {-100, 100},
+	model.OperationSaturation: {-100, 500},
+}
+
+var valuelessOperations = map[model.OperationName]bool{
+	model.OperationResize:    true,
+	model.OperationGrayscale: true,
+	model.OperationInvert:    true,
+}
+
+func validateOperations(fl validator.FieldLevel) bool {
+	operations, ok := fl.Field().Interface().([]model.Operation)
+	if !ok {
+		return false
+	}
+
+	resizes := 0
+	for _, operation := range operations {
+		if operation.Name == model.OperationResize {
+			resizes++
+		}
+
+		if valuelessOperations[operation.Name] {
+			if operation.Value != 0 {
+				return false
+			}
+			continue
+		}
+
+		limits, ok := operationRanges[operation.Name]
+		if !ok || operation.Value < limits[0] || operation.Value > limits[1] {
+			return false
+		}
+	}
+
+	return resizes <= 1
+}
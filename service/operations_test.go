@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOperations(t *testing.T) {
+	srv := New(nil, nil, nil, nil, nil, nil, 1, Limits{}, MetadataConfig{})
+	invalid := errors.InvalidParams{{Param: "Operations", Message: "operations"}}
+
+	f := func(operations []model.Operation, expected errors.InvalidParams) {
+		actual := srv.validateParams(model.SizeRequest{Width: 100, Height: 100, Operations: operations})
+		assert.Equal(t, expected, actual, operations)
+	}
+
+	f(nil, nil)
+	f([]model.Operation{
+		{Name: model.OperationBlur, Value: 2},
+		{Name: model.OperationResize},
+		{Name: model.OperationSharpen, Value: 0.5},
+		{Name: model.OperationGamma, Value: 1.5},
+		{Name: model.OperationContrast, Value: -20},
+		{Name: model.OperationBrightness, Value: 10},
+		{Name: model.OperationSaturation, Value: 200},
+		{Name: model.OperationGrayscale},
+		{Name: model.OperationInvert},
+	}, nil)
+
+	// unknown operation
+	f([]model.Operation{{Name: "sepia"}}, invalid)
+	// out of range values
+	f([]model.Operation{{Name: model.OperationBlur}}, invalid)
+	f([]model.Operation{{Name: model.OperationContrast, Value: 101}}, invalid)
+	f([]model.Operation{{Name: model.OperationSaturation, Value: -101}}, invalid)
+	// valueless operations
+	f([]model.Operation{{Name: model.OperationGrayscale, Value: 1}}, invalid)
+	// the resize is placed twice
+	f([]model.Operation{{Name: model.OperationResize}, {Name: model.OperationResize}}, invalid)
+	// too many operations
+	f(make([]model.Operation, 11), errors.InvalidParams{{Param: "Operations", Message: "max"}})
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const rerenderPageSize = 100
+
+func (s *ImageService) Presets(ctx context.Context) ([]*model.Preset, error) {
+	return s.presets.ListPresets(ctx)
+}
+
+func (s *ImageService) ResolvePresets(ctx context.Context, names []string) ([]model.SizeRequest, error) {
+	res := make([]model.SizeRequest, 0, len(names))
+	for _, name := range names {
+		preset, err := s.presets.GetPreset(ctx, name)
+		if err == errors.NotFound {
+			return nil, errors.InvalidParams{{Param: "presets", Message: "unknown preset " + name}}
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, preset.Request())
+	}
+
+	return res, nil
+}
+
+// SavePreset creates the re-render jobs in background, so a preset used by many images is saved at once.
+func (s *ImageService) SavePreset(ctx context.Context, name string, size model.SizeRequest, rerender bool) (*model.Preset, error) {
+	preset := model.Preset{
+		Name:      name,
+		Size:      size,
+		UpdatedAt: time.Now(),
+	}
+	preset.Size.Preset = ""
+	if err := s.validateParams(preset); len(err) > 0 {
+		return nil, err
+	}
+	if err := s.validateSizes(ctx, preset.Size); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	existing, err := s.presets.GetPreset(ctx, name)
+	if err != nil && err != errors.NotFound {
+		return nil, err
+	}
+	if existing != nil {
+		version = existing.Version
+	}
+	preset.Version = version + 1
+
+	if err := s.presets.SavePreset(ctx, version, preset); err != nil {
+		return nil, err
+	}
+	// the profile is checked again after the preset is saved, a profile deleted meanwhile
+	// either sees the preset or is seen deleted here
+	if err := s.checkWatermark(ctx, preset.Size); err != nil {
+		s.revertPreset(ctx, preset, existing)
+		return nil, err
+	}
+
+	if rerender {
+		go s.scheduleRerender(s.lifetime, preset)
+	}
+
+	return &preset, nil
+}
+
+func (s *ImageService) revertPreset(ctx context.Context, saved model.Preset, existing *model.Preset) {
+	var err error
+	if existing == nil {
+		err = s.presets.DeletePreset(ctx, saved.Name)
+	} else {
+		restored := *existing
+		restored.Version = saved.Version + 1
+		err = s.presets.SavePreset(ctx, saved.Version, restored)
+	}
+	if err != nil {
+		log.Error("preset ", saved.Name, ": can't revert the preset ", err)
+	}
+}
+
+func (s *ImageService) DeletePreset(ctx context.Context, name string) error {
+	return s.presets.DeletePreset(ctx, name)
+}
+
+func (s *ImageService) scheduleRerender(ctx context.Context, preset model.Preset) {
+	scheduled := 0
+	after := ""
+	for {
+		ids, err := s.repo.ListPresetImages(ctx, preset.Name, after, rerenderPageSize)
+		if err != nil {
+			log.Error("preset ", preset.Name, ": can't list images, re-render of ", scheduled, " images scheduled ", err)
+			return
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		for _, id := range ids {
+			now := time.Now()
+			job := model.Job{
+				ID:        uuid.NewV4().String(),
+				Kind:      model.JobKindRerender,
+				ImageID:   id,
+				Sizes:     []model.SizeRequest{preset.Request()},
+				Status:    model.JobStatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := s.jobs.SaveJob(ctx, job); err != nil {
+				log.Error("preset ", preset.Name, ": can't save job, re-render of ", scheduled, " images scheduled ", err)
+				return
+			}
+
+			s.queueJob(ctx, job.ID)
+			scheduled++
+		}
+		after = ids[len(ids)-1]
+	}
+	log.Info("preset ", preset.Name, ": re-render of ", scheduled, " images scheduled")
+}
+
+func (s *ImageService) rerender(ctx context.Context, id string, requests []model.SizeRequest) (*model.Image, error) {
+	for i := 0; ; i++ {
+		image, err := s.getImage(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		reader, err := s.storage.Read(ctx, image.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		image, err = s.doResize(ctx, image, reader, requests)
+		closeReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range requests {
+			image.ReplacePresetSizes(request.WithSourceFormat(image.MimeType))
+		}
+
+		version := image.Version
+		image.Version++
+		err = s.repo.Save(ctx, version, *image)
+		if err == errors.RaceCondition && i < saveRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(image.Trash) > 0 {
+			s.scheduleCleanup(ctx, id)
+		}
+
+		return image, nil
+	}
+}
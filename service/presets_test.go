@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/golang/mock/gomock"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	"github.com/portey/image-resizer/service/mock"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_ResolvePresets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	presets := mock.NewMockPresetRepository(ctrl)
+	presets.EXPECT().
+		GetPreset(gomock.Eq(ctx), gomock.Eq("thumb")).
+		Return(&model.Preset{Name: "thumb", Size: model.SizeRequest{Width: 100, Height: 100}}, nil).
+		Times(2)
+	presets.EXPECT().
+		GetPreset(gomock.Eq(ctx), gomock.Eq("unknown")).
+		Return(nil, errors.NotFound)
+
+	srv := New(nil, nil, nil, nil, presets, nil, 1, Limits{}, MetadataConfig{})
+
+	res, err := srv.ResolvePresets(ctx, []string{"thumb"})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SizeRequest{{Width: 100, Height: 100, Preset: "thumb"}}, res)
+
+	_, err = srv.ResolvePresets(ctx, []string{"thumb", "unknown"})
+	assert.Equal(t, errors.InvalidParams{{Param: "presets", Message: "unknown preset unknown"}}, err)
+}
+
+func TestImageService_SavePreset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	size := model.SizeRequest{Width: 200, Height: 200, Format: model.FormatJPEG}
+
+	presets := mock.NewMockPresetRepository(ctrl)
+	presets.EXPECT().
+		GetPreset(gomock.Eq(ctx), gomock.Eq("card@2x")).
+		Return(&model.Preset{Name: "card@2x", Version: 1}, nil)
+	presets.EXPECT().
+		SavePreset(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, p model.Preset) error {
+			assert.Equal(t, 2, p.Version)
+			assert.Equal(t, size, p.Size)
+
+			return nil
+		})
+
+	// jobs are created in background, images are listed by pages
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		ListPresetImages(gomock.Any(), gomock.Eq("card@2x"), gomock.Eq(""), gomock.Eq(rerenderPageSize)).
+		Return([]string{"a", "b"}, nil)
+	repo.EXPECT().
+		ListPresetImages(gomock.Any(), gomock.Eq("card@2x"), gomock.Eq("b"), gomock.Eq(rerenderPageSize)).
+		Return(nil, nil)
+
+	jobs := mock.NewMockJobRepository(ctrl)
+	jobs.EXPECT().
+		SaveJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job model.Job) error {
+			assert.Equal(t, model.JobKindRerender, job.Kind)
+			assert.Equal(t, []model.SizeRequest{{Width: 200, Height: 200, Format: model.FormatJPEG, Preset: "card@2x"}}, job.Sizes)
+
+			return nil
+		}).
+		Times(2)
+
+	srv := New(nil, nil, repo, jobs, presets, nil, 1, Limits{}, MetadataConfig{})
+
+	p, err := srv.SavePreset(ctx, "card@2x", size, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, p.Version)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(srv.queue) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, srv.queue, 2)
+
+	// invalid name and size
+	_, err = srv.SavePreset(ctx, "card 2x", model.SizeRequest{Width: 1, Height: 200}, false)
+	assert.Equal(t, errors.InvalidParams{
+		{Param: "Name", Message: "identifier"},
+		{Param: "Width", Message: "min"},
+	}, err)
+}
+
+func TestImageService_Rerender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stored := &model.Image{
+		ID:       "id",
+		Path:     "some/path/test.png",
+		MimeType: "image/png",
+		Version:  1,
+	}
+	stored.AddSize("some/resized/old.png", 10, model.SizeRequest{Width: 100, Height: 100, Preset: "thumb"})
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/test.png")).
+		Return(strings.NewReader("Some content"), nil)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(200), gomock.Eq(200), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "some/resized/new.png", err
+		})
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Eq(ctx), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, _ model.SizeRequest) error {
+			_, err := out.Write([]byte("resized"))
+			return err
+		})
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		Return(stored, nil)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		Return(nil)
+
+	jobs := mock.NewMockJobRepository(ctrl)
+	jobs.EXPECT().
+		SaveJob(gomock.Eq(ctx), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job model.Job) error {
+			assert.Equal(t, model.JobKindCleanup, job.Kind)
+			return nil
+		})
+
+	srv := New(storage, resizer, repo, jobs, nil, nil, 1, Limits{}, MetadataConfig{})
+
+	i, err := srv.rerender(ctx, "id", []model.SizeRequest{{Width: 200, Height: 200, Preset: "thumb"}})
+	assert.NoError(t, err)
+	assert.Len(t, i.Sizes, 1)
+	assert.Equal(t, "some/resized/new.png", i.Sizes[0].Path)
+	assert.Equal(t, "thumb", i.Sizes[0].Preset)
+	assert.Equal(t, []string{"some/resized/old.png"}, i.Trash)
+}
+
+func TestImageService_ResizePresetLabel(t *testing.T) {
+	ctx := context.Background()
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+
+	repo := repositorymemory.New()
+	srv := New(storagememory.New(), resizer.New(0), repo, repo, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	uploaded, err := srv.Upload(ctx, model.ImageUpload{
+		Content:  bytes.NewReader(content),
+		Filename: "image.jpg",
+		Size:     int64(len(content)),
+		MimeType: "image/jpeg",
+	}, []model.SizeRequest{{Width: 100, Height: 100}})
+	assert.NoError(t, err)
+
+	// the size requested without the preset is labeled instead of being rendered again
+	resized, err := srv.Resize(ctx, uploaded.ID, []model.SizeRequest{{Width: 100, Height: 100, Preset: "thumb"}})
+	assert.NoError(t, err)
+	assert.Len(t, resized.Sizes, 1)
+	assert.Equal(t, uploaded.Sizes[0].Path, resized.Sizes[0].Path)
+	assert.Equal(t, "thumb", resized.Sizes[0].Preset)
+
+	ids, err := repo.ListPresetImages(ctx, "thumb", "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{uploaded.ID}, ids)
+}
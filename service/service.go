@@ -3,93 +3,347 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
 	"io"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/portey/image-resizer/errors"
 	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/spool"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 )
 
 type Repository interface {
 	Get(ctx context.Context, id string) (*model.Image, error)
-	List(ctx context.Context, limit, offset int) ([]*model.Image, error)
+	List(ctx context.Context, filter model.ImageFilter, sort model.ImageSort, limit, offset int) ([]*model.Image, error)
+	ListAfter(ctx context.Context, filter model.ImageFilter, after *model.ImageCursor, limit int) ([]*model.Image, error)
+	Count(ctx context.Context, filter model.ImageFilter) (int, error)
+	// ListPresetImages returns IDs greater than after ordered by IDs, so the IDs are paged by the last ID of the previous page.
+	ListPresetImages(ctx context.Context, preset, after string, limit int) ([]string, error)
+	GetShared(ctx context.Context, hash string) (*model.Image, error)
+	ListTrashed(ctx context.Context) ([]string, error)
+	// Referenced ignores deleted images.
+	Referenced(ctx context.Context, path string) (bool, error)
 	Save(ctx context.Context, version int, image model.Image) error
+	Delete(ctx context.Context, id string, version int) error
+}
+
+type JobRepository interface {
+	GetJob(ctx context.Context, id string) (*model.Job, error)
+	SaveJob(ctx context.Context, job model.Job) error
+	ListUnfinishedJobs(ctx context.Context) ([]*model.Job, error)
+}
+
+type PresetRepository interface {
+	GetPreset(ctx context.Context, name string) (*model.Preset, error)
+	ListPresets(ctx context.Context) ([]*model.Preset, error)
+	SavePreset(ctx context.Context, version int, preset model.Preset) error
+	DeletePreset(ctx context.Context, name string) error
+}
+
+type WatermarkRepository interface {
+	GetWatermark(ctx context.Context, id string) (*model.Watermark, error)
+	ListWatermarks(ctx context.Context) ([]*model.Watermark, error)
+	SaveWatermark(ctx context.Context, version int, watermark model.Watermark) error
+	DeleteWatermark(ctx context.Context, id string) error
 }
 
 type Resizer interface {
-	Resize(ctx context.Context, data io.Reader, output io.Writer, width, height int) error
+	DecodeMetadata(ctx context.Context, data io.Reader) (model.Metadata, error)
+	Decode(ctx context.Context, data io.Reader) (image.Image, error)
+	Resize(ctx context.Context, img image.Image, output io.Writer, request model.SizeRequest) error
+	Edit(ctx context.Context, img image.Image, output io.Writer, edit model.Edit, format model.Format) error
+	StripMetadata(ctx context.Context, data io.Reader, output io.Writer, format model.Format, policy model.MetadataPolicy, method model.MetadataMethod) error
 }
 
 type Storage interface {
 	Read(ctx context.Context, path string) (io.Reader, error)
-	Upload(ctx context.Context, data io.Reader) (string, error)
-	UploadResized(ctx context.Context, data io.Reader, width, height int) (string, error)
+	Upload(ctx context.Context, data io.Reader, format model.Format) (string, error)
+	UploadResized(ctx context.Context, data io.Reader, width, height int, format model.Format) (string, error)
+	// Modified is updated by an identical render which replaced a variant.
+	Modified(ctx context.Context, path string) (time.Time, error)
+	Delete(ctx context.Context, path string) error
+}
+
+type Limits struct {
+	MaxUploadBytes     int64
+	MaxOutputDimension int
+	ResizeTimeout      time.Duration
+	// the rest of an original is spooled to a temp file
+	MaxMemoryBytes int64
+	// CleanupGracePeriod keeps unreferenced objects written within the period, so a variant rendered again
+	// by an upload which isn't saved yet isn't removed.
+	CleanupGracePeriod time.Duration
+}
+
+type MetadataConfig struct {
+	Policy model.MetadataPolicy
+	Method model.MetadataMethod
 }
 
 type ImageService struct {
-	storage  Storage
-	resizer  Resizer
-	repo     Repository
-	validate *validator.Validate
+	storage    Storage
+	resizer    Resizer
+	repo       Repository
+	jobs       JobRepository
+	presets    PresetRepository
+	watermarks WatermarkRepository
+	limits     Limits
+	metadata   MetadataConfig
+	validate   *validator.Validate
+	// workers limits resizes across all requests
+	workers chan struct{}
+	queue   chan string
+	// queueMu guards queued, the jobs which are queued or running, and overflowed, set when a job didn't fit the queue
+	queueMu    sync.Mutex
+	queued     map[string]bool
+	overflowed bool
+	events     *broker
+	// lifetime is done when the service stops, background work started by requests outlives them
+	lifetime context.Context
 }
 
-func New(storage Storage, resizer Resizer, repo Repository) *ImageService {
+func New(
+	storage Storage,
+	resizer Resizer,
+	repo Repository,
+	jobs JobRepository,
+	presets PresetRepository,
+	watermarks WatermarkRepository,
+	workers int,
+	limits Limits,
+	metadata MetadataConfig,
+) *ImageService {
 	validate := validator.New()
+	if err := validate.RegisterValidation("identifier", validateIdentifier); err != nil {
+		panic(err)
+	}
+	if err := validate.RegisterValidation("operations", validateOperations); err != nil {
+		panic(err)
+	}
+	if workers < 1 {
+		workers = 1
+	}
 
 	return &ImageService{
-		validate: validate,
-		storage:  storage,
-		resizer:  resizer,
-		repo:     repo,
+		validate:   validate,
+		storage:    storage,
+		resizer:    resizer,
+		repo:       repo,
+		jobs:       jobs,
+		presets:    presets,
+		watermarks: watermarks,
+		limits:     limits,
+		metadata:   metadata,
+		workers:    make(chan struct{}, workers),
+		queue:      make(chan string, jobQueueSize),
+		queued:     make(map[string]bool),
+		events:     newBroker(),
+		lifetime:   context.Background(),
 	}
 }
 
 func (s *ImageService) Upload(ctx context.Context, upload model.ImageUpload, sizes []model.SizeRequest) (*model.Image, error) {
-	if err := s.validateParams(upload, sizes); len(err) > 0 {
+	// the original is spooled while it's stored, so it isn't read again for resizing
+	original := s.newSpool()
+	defer closeSpool(original)
+
+	image, existing, err := s.uploadOriginal(ctx, upload, sizes, original)
+	if err != nil {
 		return nil, err
 	}
-	for _, size := range sizes {
-		if err := s.validateParams(size); len(err) > 0 {
-			return nil, err
-		}
+	if existing {
+		// the same content is uploaded already, missing sizes are added to the shared image
+		return s.resizeWithRetry(ctx, image.ID, sizes)
 	}
 
-	copyContent, originalContent := copyReader(upload.Content)
-	originalPath, err := s.storage.Upload(ctx, copyContent)
+	rendered, err := s.doResize(ctx, image, original.Reader(), sizes)
 	if err != nil {
+		// the stored sizes are discarded by the resize, the original isn't referenced by a saved image
+		s.discardUpload(ctx, &model.Image{ID: image.ID, Path: image.Path})
 		return nil, err
 	}
 
-	image := &model.Image{
-		ID:         uuid.NewV4().String(),
-		Path:       originalPath,
-		ClientName: upload.Filename,
-		MimeType:   upload.MimeType,
-		Size:       upload.Size,
-		UploadAt:   time.Now(),
-		Sizes:      []model.Size{},
-		Version:    1,
+	if err := s.repo.Save(ctx, 0, *rendered); err != nil {
+		s.discardUpload(ctx, rendered)
+		if err == errors.RaceCondition && rendered.Shared {
+			// a concurrent deduplicated upload of the same content won
+			shared, err := s.sharedImage(ctx, rendered.Hash)
+			if err != nil {
+				return nil, err
+			}
+
+			return s.resizeWithRetry(ctx, shared.ID, sizes)
+		}
+
+		return nil, err
 	}
 
-	image, err = s.doResize(ctx, image, originalContent, sizes)
+	return rendered, nil
+}
+
+// uploadOriginal returns the existing shared image for deduplicated uploads of content uploaded already,
+// which is reported by the flag.
+func (s *ImageService) uploadOriginal(
+	ctx context.Context,
+	upload model.ImageUpload,
+	sizes []model.SizeRequest,
+	original *spool.Buffer,
+) (*model.Image, bool, error) {
+	if err := s.validateParams(upload); len(err) > 0 {
+		return nil, false, err
+	}
+	if err := s.validateSizes(ctx, sizes...); err != nil {
+		return nil, false, err
+	}
+	if s.limits.MaxUploadBytes > 0 {
+		if upload.Size > s.limits.MaxUploadBytes {
+			return nil, false, errors.ImageTooLarge
+		}
+		// the declared size isn't trusted, the content is limited while it's read
+		upload.Content = &limitedReader{reader: upload.Content, remaining: s.limits.MaxUploadBytes}
+	}
+
+	content, metadata, err := s.inspect(ctx, upload)
+	if err != nil {
+		return nil, false, err
+	}
+
+	policy := s.metadataPolicy(upload)
+	var stripped *io.PipeReader
+	if policy.Strips() {
+		method := s.metadata.Method.For(metadata.Format)
+		stripped = s.stripMetadata(ctx, content, metadata.Format, policy, method)
+		content = stripped
+		metadata = metadata.Strip(policy, method)
+	}
+
+	hash := sha256.New()
+	if upload.Dedupe {
+		// the content is hashed before it's stored, so a copy isn't stored
+		buf := s.newSpool()
+		defer closeSpool(buf)
+
+		_, err := io.Copy(io.MultiWriter(buf, hash), content)
+		closeStripped(stripped)
+		if err != nil {
+			return nil, false, err
+		}
+
+		shared, err := s.sharedImage(ctx, hex.EncodeToString(hash.Sum(nil)))
+		if err != errors.NotFound {
+			return shared, err == nil, err
+		}
+		content = buf.Reader()
+	} else {
+		content = io.TeeReader(content, hash)
+	}
+	if original != nil {
+		content = io.TeeReader(content, original)
+	}
+
+	// the size of the image is the number of stored bytes, the declared size can be missing or wrong
+	counter := &countingReader{reader: content}
+	originalPath, err := s.storage.Upload(ctx, counter, metadata.Format)
+	closeStripped(stripped)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &model.Image{
+		ID:             uuid.NewV4().String(),
+		Path:           originalPath,
+		ClientName:     upload.Filename,
+		MimeType:       upload.MimeType,
+		Metadata:       metadata,
+		MetadataPolicy: policy,
+		Size:           counter.count,
+		UploadAt:       time.Now(),
+		Sizes:          []model.Size{},
+		Version:        1,
+		Hash:           hex.EncodeToString(hash.Sum(nil)),
+		Shared:         upload.Dedupe,
+	}, false, nil
+}
+
+func (s *ImageService) sharedImage(ctx context.Context, hash string) (*model.Image, error) {
+	image, err := s.repo.GetShared(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
+	if image.Deleted {
+		return nil, errors.NotFound
+	}
 
-	return image, s.repo.Save(ctx, 0, *image)
+	return image, nil
 }
 
-func (s *ImageService) Resize(ctx context.Context, id string, sizes []model.SizeRequest) (*model.Image, error) {
-	for _, size := range sizes {
-		if err := s.validateParams(size); len(err) > 0 {
-			return nil, err
+func (s *ImageService) metadataPolicy(upload model.ImageUpload) model.MetadataPolicy {
+	policy := upload.MetadataPolicy
+	if policy == "" {
+		policy = s.metadata.Policy
+	}
+	if !policy.Strips() {
+		return model.MetadataPolicyKeep
+	}
+
+	return policy
+}
+
+func (s *ImageService) stripMetadata(
+	ctx context.Context,
+	content io.Reader,
+	format model.Format,
+	policy model.MetadataPolicy,
+	method model.MetadataMethod,
+) *io.PipeReader {
+	reader, writer := io.Pipe()
+	go func() {
+		err := s.resizer.StripMetadata(ctx, content, writer, format, policy, method)
+		if closeErr := writer.CloseWithError(err); closeErr != nil {
+			log.Error("can't close stripped original writer", closeErr)
 		}
+	}()
+
+	return reader
+}
+
+// closeStripped unblocks stripping if the stripped original isn't read till the end.
+func closeStripped(stripped *io.PipeReader) {
+	if stripped == nil {
+		return
+	}
+	if err := stripped.CloseWithError(io.ErrClosedPipe); err != nil {
+		log.Error("can't close stripped original reader", err)
 	}
+}
 
-	image, err := s.repo.Get(ctx, id)
+// inspect returns a reader of the whole content including the header consumed by the detection.
+func (s *ImageService) inspect(ctx context.Context, upload model.ImageUpload) (io.Reader, model.Metadata, error) {
+	var header bytes.Buffer
+	metadata, err := s.resizer.DecodeMetadata(ctx, io.TeeReader(upload.Content, &header))
+	if err != nil {
+		return nil, model.Metadata{}, err
+	}
+	if mimeType := metadata.Format.MimeType(); mimeType != upload.MimeType {
+		return nil, model.Metadata{}, errors.InvalidParams{{Param: "MimeType", Message: "content=" + mimeType}}
+	}
+
+	return io.MultiReader(&header, upload.Content), metadata, nil
+}
+
+func (s *ImageService) Resize(ctx context.Context, id string, sizes []model.SizeRequest) (*model.Image, error) {
+	if err := s.validateSizes(ctx, sizes...); err != nil {
+		return nil, err
+	}
+
+	image, err := s.getImage(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +352,10 @@ func (s *ImageService) Resize(ctx context.Context, id string, sizes []model.Size
 	if err != nil {
 		return nil, err
 	}
+	defer closeReader(reader)
 
+	// placeholders are replaced in place, so the previous sizes are copied
+	previous := append([]model.Size(nil), image.Sizes...)
 	image, err = s.doResize(ctx, image, reader, sizes)
 	if err != nil {
 		return nil, err
@@ -106,52 +363,271 @@ func (s *ImageService) Resize(ctx context.Context, id string, sizes []model.Size
 	version := image.Version
 	image.Version++
 
-	return image, s.repo.Save(ctx, version, *image)
+	if err := s.repo.Save(ctx, version, *image); err != nil {
+		// e.g. the image was changed or deleted concurrently, the new renders aren't referenced by it
+		s.discardUpload(ctx, renderedSizes(image, previous))
+		return nil, err
+	}
+
+	return image, nil
 }
 
-func (s *ImageService) List(ctx context.Context, limit, offset int) ([]*model.Image, error) {
-	return s.repo.List(ctx, limit, offset)
+func (s *ImageService) Variant(ctx context.Context, id string, request model.SizeRequest) (*model.Size, error) {
+	if err := s.validateSize(request); err != nil {
+		return nil, err
+	}
+
+	image, err := s.getImage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	request = request.WithSourceFormat(image.MimeType)
+	if size, ok := image.ResizedSize(request); ok {
+		return &size, nil
+	}
+	// existing sizes are served after their profile is deleted, new ones aren't created with it
+	if err := s.checkWatermark(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// images changed by concurrent requests are resized again
+	image, err = s.resizeWithRetry(ctx, id, []model.SizeRequest{request})
+	if err == errors.RaceCondition {
+		// the same size could be created by a concurrent request
+		image, err = s.getImage(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if size, ok := image.ResizedSize(request); ok {
+		return &size, nil
+	}
+
+	return nil, errors.NotFound
+}
+
+func (s *ImageService) Read(ctx context.Context, path string) (io.Reader, error) {
+	return s.storage.Read(ctx, path)
+}
+
+func (s *ImageService) getImage(ctx context.Context, id string) (*model.Image, error) {
+	image, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if image.Deleted {
+		return nil, errors.NotFound
+	}
+
+	return image, nil
+}
+
+func (s *ImageService) Image(ctx context.Context, id string) (*model.Image, error) {
+	return s.getImage(ctx, id)
+}
+
+func (s *ImageService) List(ctx context.Context, filter model.ImageFilter, sort model.ImageSort, limit, offset int) ([]*model.Image, error) {
+	if err := s.validateParams(sort); len(err) > 0 {
+		return nil, err
+	}
+
+	return s.repo.List(ctx, filter, sort, limit, offset)
+}
+
+func (s *ImageService) Page(ctx context.Context, filter model.ImageFilter, page model.PageRequest) (*model.ImagePage, error) {
+	if err := s.validateParams(page); len(err) > 0 {
+		return nil, err
+	}
+
+	var after *model.ImageCursor
+	if page.After != "" {
+		cursor, err := model.ParseImageCursor(page.After)
+		if err != nil {
+			return nil, errors.InvalidParams{{Param: "After", Message: "cursor"}}
+		}
+		after = &cursor
+	}
+
+	// one more image tells whether there is a next page
+	images, err := s.repo.ListAfter(ctx, filter, after, page.First+1)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.ImagePage{Images: images}
+	if len(images) > page.First {
+		res.Images = images[:page.First]
+		res.HasNextPage = true
+	}
+
+	return res, nil
+}
+
+func (s *ImageService) Count(ctx context.Context, filter model.ImageFilter) (int, error) {
+	return s.repo.Count(ctx, filter)
 }
 
 func (s *ImageService) doResize(ctx context.Context, image *model.Image, content io.Reader, sizes []model.SizeRequest) (*model.Image, error) {
-	originalContent := content
-	var contentCopy io.Reader
+	pending := make([]model.SizeRequest, 0, len(sizes))
+	requested := model.Image{}
+	var presets []model.SizeRequest
 	for _, size := range sizes {
-		select {
-		case <-ctx.Done():
-			return nil, nil
-		default:
-			if image.HasResizedSize(size.Width, size.Height) {
-				continue
+		size := size.WithSourceFormat(image.MimeType)
+		size.FocalPoint = image.FocalPoint
+		if size.Preset != "" {
+			presets = append(presets, size)
+		}
+		if image.HasResizedSize(size) || requested.HasResizedSize(size) {
+			continue
+		}
+		requested.AddSize("", 0, size)
+		pending = append(pending, size)
+	}
+	if len(pending) == 0 {
+		labelPresetSizes(image, presets)
+		return image, nil
+	}
+	if err := s.loadOverlays(ctx, pending); err != nil {
+		return nil, err
+	}
+
+	original, err := s.resizer.Decode(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	resizeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failErr  error
+		results  = make([]model.Size, len(pending))
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failErr = err
+			cancel()
+		})
+	}
+
+	for i, size := range pending {
+		wg.Add(1)
+		go func(i int, size model.SizeRequest) {
+			defer wg.Done()
+
+			// the slot is kept until the resize returns, a timed out resize returns after its running operation
+			select {
+			case s.workers <- struct{}{}:
+				defer func() { <-s.workers }()
+			case <-resizeCtx.Done():
+				fail(resizeCtx.Err())
+				return
 			}
-			contentCopy, originalContent = copyReader(originalContent)
-			reader, writer := io.Pipe()
-			go func() {
-				defer func() {
-					err := writer.Close()
-					if err != nil {
-						log.Error("can't close upload writer", err)
-					}
-				}()
-				if err := s.resizer.Resize(ctx, contentCopy, writer, size.Width, size.Height); err != nil {
-					if closeErr := writer.CloseWithError(err); closeErr != nil {
-						log.Error("can't close upload writer after resize", err)
-					}
-				}
-			}()
-
-			resizedPath, err := s.storage.UploadResized(ctx, reader, size.Width, size.Height)
+
+			path, bytes, err := s.resizeAndUpload(resizeCtx, original, size)
 			if err != nil {
-				return nil, err
+				fail(err)
+				return
 			}
+			results[i] = model.Size{Path: path, Bytes: bytes}
+		}(i, size)
+	}
+	wg.Wait()
 
-			image.AddSize(resizedPath, size.Width, size.Height)
-		}
+	if failErr != nil {
+		// the image isn't saved, so the sizes which were stored before the failure aren't referenced by it
+		s.discardUpload(ctx, &model.Image{ID: image.ID, Sizes: results})
+		return nil, failErr
 	}
 
+	for i, size := range pending {
+		image.AddSize(results[i].Path, results[i].Bytes, size)
+	}
+	labelPresetSizes(image, presets)
+
 	return image, nil
 }
 
+// labelPresetSizes labels sizes requested before without the presets, so they're re-rendered with the presets.
+func labelPresetSizes(image *model.Image, presets []model.SizeRequest) {
+	for _, size := range presets {
+		image.LabelPresetSize(size)
+	}
+}
+
+// resizeAndUpload resizes the variant before the upload starts, so the resize timeout doesn't cover the upload
+// and slow storage isn't reported as ImageTooLarge.
+func (s *ImageService) resizeAndUpload(ctx context.Context, original image.Image, size model.SizeRequest) (string, int64, error) {
+	buf := s.newSpool()
+	defer closeSpool(buf)
+
+	if err := s.resizeWithTimeout(ctx, original, buf, size); err != nil {
+		return "", 0, err
+	}
+
+	path, err := s.storage.UploadResized(ctx, buf.Reader(), size.Width, size.Height, size.OutputFormat())
+	if err != nil {
+		return "", 0, err
+	}
+
+	return path, buf.Size(), nil
+}
+
+func (s *ImageService) resizeWithTimeout(ctx context.Context, original image.Image, output io.Writer, size model.SizeRequest) error {
+	if s.limits.ResizeTimeout <= 0 {
+		return s.resizer.Resize(ctx, original, output, size)
+	}
+
+	resizeCtx, cancel := context.WithTimeout(ctx, s.limits.ResizeTimeout)
+	defer cancel()
+
+	err := s.resizer.Resize(resizeCtx, original, output, size)
+	if err != nil && ctx.Err() == nil && resizeCtx.Err() == context.DeadlineExceeded {
+		return errors.ImageTooLarge
+	}
+
+	return err
+}
+
+// validateSizes is used for new sizes only, re-renders of sizes composited with a deleted profile aren't rejected.
+func (s *ImageService) validateSizes(ctx context.Context, sizes ...model.SizeRequest) error {
+	for _, size := range sizes {
+		if err := s.validateSize(size); err != nil {
+			return err
+		}
+		if err := s.checkWatermark(ctx, size); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ImageService) validateSize(size model.SizeRequest) error {
+	if err := s.validateParams(size); len(err) > 0 {
+		return err
+	}
+	// other modes don't crop, so the smart anchor would be ignored
+	if size.Anchor == model.AnchorSmart && size.ResizeMode() != model.ResizeModeFill {
+		return errors.InvalidParams{{Param: "Anchor", Message: "mode=fill"}}
+	}
+	if max := s.limits.MaxOutputDimension; max > 0 && (size.Width > max || size.Height > max) {
+		return errors.ImageTooLarge
+	}
+
+	return nil
+}
+
+var identifierRegexp = regexp.MustCompile(`^[a-zA-Z0-9@._-]+$`)
+
+func validateIdentifier(fl validator.FieldLevel) bool {
+	return identifierRegexp.MatchString(fl.Field().String())
+}
+
 func (s *ImageService) validateParams(objs ...interface{}) errors.InvalidParams {
 	var paramErrors errors.InvalidParams
 	for _, obj := range objs {
@@ -183,9 +659,47 @@ func (s *ImageService) validateParams(objs ...interface{}) errors.InvalidParams
 	return paramErrors
 }
 
-func copyReader(in io.Reader) (io.Reader, io.Reader) {
-	var buf bytes.Buffer
-	cc := io.TeeReader(in, &buf)
+func (s *ImageService) newSpool() *spool.Buffer {
+	return spool.New(s.limits.MaxMemoryBytes)
+}
+
+func closeReader(reader io.Reader) {
+	if closer, ok := reader.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Error("can't close stored object reader ", err)
+		}
+	}
+}
+
+func closeSpool(buf *spool.Buffer) {
+	if err := buf.Close(); err != nil {
+		log.Error("can't remove spooled content ", err)
+	}
+}
+
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, errors.ImageTooLarge
+	}
+
+	return n, err
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
 
-	return cc, &buf
+	return n, err
 }
@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"image"
+	"image/color"
 	"io"
 	"io/ioutil"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/disintegration/imaging"
 	"github.com/golang/mock/gomock"
 	"github.com/portey/image-resizer/errors"
 	"github.com/portey/image-resizer/model"
@@ -24,8 +30,8 @@ func TestImageService_Upload(t *testing.T) {
 
 	storage := mock.NewMockStorage(ctrl)
 	storage.EXPECT().
-		Upload(gomock.Eq(ctx), gomock.Any()).
-		DoAndReturn(func(_ context.Context, in io.Reader) (string, error) {
+		Upload(gomock.Eq(ctx), gomock.Any(), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _ model.Format) (string, error) {
 			c, err := ioutil.ReadAll(in)
 			assert.NoError(t, err)
 			assert.Equal(t, content, string(c))
@@ -33,8 +39,8 @@ func TestImageService_Upload(t *testing.T) {
 			return "some/path/test.jpg", nil
 		})
 	storage.EXPECT().
-		UploadResized(gomock.Eq(ctx), gomock.Any(), gomock.Eq(100), gomock.Eq(200)).
-		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int) (string, error) {
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(100), gomock.Eq(200), gomock.Eq(model.FormatJPEG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
 			c, err := ioutil.ReadAll(in)
 			assert.NoError(t, err)
 			assert.Equal(t, contentResized, string(c))
@@ -42,17 +48,36 @@ func TestImageService_Upload(t *testing.T) {
 			return "some/resized/test.jpg", nil
 		})
 
+	original := imaging.New(10, 10, color.White)
 	resizer := mock.NewMockResizer(ctrl)
 	resizer.EXPECT().
-		Resize(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Eq(100), gomock.Eq(200)).
-		DoAndReturn(func(_ context.Context, in io.Reader, out io.Writer, width, height int) error {
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in io.Reader) (model.Metadata, error) {
+			// the header consumed by the detection is uploaded too
+			_, err := in.Read(make([]byte, 4))
+			assert.NoError(t, err)
+
+			return model.Metadata{Format: model.FormatPNG, Width: 10, Height: 10, ColorModel: model.ColorModelRGBA, HasAlpha: true}, nil
+		})
+	resizer.EXPECT().
+		Decode(gomock.Eq(ctx), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in io.Reader) (image.Image, error) {
 			c, err := ioutil.ReadAll(in)
 			assert.NoError(t, err)
 			assert.Equal(t, "Some content", string(c))
-			assert.Equal(t, 100, width)
-			assert.Equal(t, 200, height)
 
-			_, err = out.Write([]byte(contentResized))
+			return original, nil
+		})
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Eq(original), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, request model.SizeRequest) error {
+			assert.Equal(t, 100, request.Width)
+			assert.Equal(t, 200, request.Height)
+			assert.Equal(t, model.ResizeModeFit, request.Mode)
+			assert.Equal(t, model.FormatJPEG, request.Format)
+			assert.Equal(t, 80, request.JPEGQuality())
+
+			_, err := out.Write([]byte(contentResized))
 			assert.NoError(t, err)
 
 			return nil
@@ -65,32 +90,73 @@ func TestImageService_Upload(t *testing.T) {
 			assert.Len(t, i.Sizes, 1)
 			assert.Equal(t, "original.png", i.ClientName)
 			assert.Equal(t, "image/png", i.MimeType)
-			assert.Equal(t, int64(123123), i.Size)
+			assert.Equal(t, model.FormatPNG, i.Format)
+			assert.Equal(t, 10, i.Width)
+			assert.True(t, i.HasAlpha)
+			// the stored bytes are counted rather than the declared size
+			assert.Equal(t, int64(len(content)), i.Size)
 			assert.Equal(t, "some/path/test.jpg", i.Path)
 			assert.Equal(t, "some/resized/test.jpg", i.Sizes[0].Path)
 			assert.Equal(t, 100, i.Sizes[0].Width)
 			assert.Equal(t, 200, i.Sizes[0].Height)
+			assert.Equal(t, model.ResizeModeFit, i.Sizes[0].Mode)
+			assert.Equal(t, model.FormatJPEG, i.Sizes[0].Format)
+			assert.Equal(t, 80, i.Sizes[0].Quality)
 
 			return nil
 		})
 
-	srv := New(storage, resizer, repo)
+	srv := New(storage, resizer, repo, nil, nil, nil, 2, Limits{}, MetadataConfig{})
 	i, err := srv.Upload(ctx, model.ImageUpload{
 		Content:  strings.NewReader(content),
 		Filename: "original.png",
 		Size:     123123,
 		MimeType: "image/png",
 	}, []model.SizeRequest{{
-		Width:  100,
-		Height: 200,
+		Width:   100,
+		Height:  200,
+		Mode:    model.ResizeModeFit,
+		Format:  model.FormatJPEG,
+		Quality: 80,
 	}})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, i.ID)
 }
 
+func TestImageService_UploadMismatchedContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		Return(model.Metadata{Format: model.FormatJPEG, Width: 10, Height: 10}, nil)
+	resizer.EXPECT().
+		DecodeMetadata(gomock.Eq(ctx), gomock.Any()).
+		Return(model.Metadata{}, errors.InvalidParams{{Param: "Content", Message: "image"}})
+
+	srv := New(nil, resizer, nil, nil, nil, nil, 2, Limits{}, MetadataConfig{})
+	upload := model.ImageUpload{
+		Content:  strings.NewReader("Some content"),
+		Filename: "original.png",
+		Size:     123123,
+		MimeType: "image/png",
+	}
+
+	// a renamed jpeg
+	_, err := srv.Upload(ctx, upload, nil)
+	assert.Equal(t, errors.InvalidParams{{Param: "MimeType", Message: "content=image/jpeg"}}, err)
+
+	// not an image
+	_, err = srv.Upload(ctx, upload, nil)
+	assert.Equal(t, errors.InvalidParams{{Param: "Content", Message: "image"}}, err)
+}
+
 func Test_Validation(t *testing.T) {
 	f := func(obj interface{}, err errors.InvalidParams) {
-		srv := New(nil, nil, nil)
+		srv := New(nil, nil, nil, nil, nil, nil, 1, Limits{}, MetadataConfig{})
 		actualErr := srv.validateParams(obj)
 		assert.Equal(t, err, actualErr)
 	}
@@ -124,7 +190,47 @@ func Test_Validation(t *testing.T) {
 	}, errors.InvalidParams{
 		{
 			Param:   "MimeType",
-			Message: "eq=image/jpeg|eq=image/png",
+			Message: "eq=image/jpeg|eq=image/png|eq=image/gif",
+		},
+	})
+
+	//invalid size request
+	f(model.SizeRequest{
+		Width:      100,
+		Height:     100,
+		Mode:       "zoom",
+		Anchor:     "middle",
+		Background: "white",
+		Format:     "webp",
+		Frame:      -1,
+	}, errors.InvalidParams{
+		{
+			Param:   "Mode",
+			Message: "oneof",
+		},
+		{
+			Param:   "Anchor",
+			Message: "oneof",
+		},
+		{
+			Param:   "Background",
+			Message: "hexcolor",
+		},
+		{
+			Param:   "Format",
+			Message: "oneof",
+		},
+		{
+			Param:   "Frame",
+			Message: "min",
+		},
+	})
+
+	//invalid sort
+	f(model.ImageSort{Field: "name"}, errors.InvalidParams{
+		{
+			Param:   "Field",
+			Message: "oneof",
 		},
 	})
 
@@ -136,3 +242,379 @@ func Test_Validation(t *testing.T) {
 		MimeType: "image/png",
 	}, nil)
 }
+
+func TestImageService_Variant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stored := &model.Image{
+		ID:       "id",
+		Path:     "some/path/test.jpg",
+		MimeType: "image/jpeg",
+		Version:  1,
+	}
+	stored.AddSize("some/resized/existing.jpeg", 10, model.SizeRequest{Width: 100, Height: 100, Format: model.FormatJPEG})
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/test.jpg")).
+		Return(strings.NewReader("Some content"), nil)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(200), gomock.Eq(200), gomock.Eq(model.FormatJPEG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			assert.NoError(t, err)
+
+			return "some/resized/new.jpeg", nil
+		})
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Eq(ctx), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, _ model.SizeRequest) error {
+			_, err := out.Write([]byte("resized"))
+			return err
+		})
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			copied := *stored
+			return &copied, nil
+		}).
+		Times(3)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		Return(nil)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	// existing size in the format of the original
+	size, err := srv.Variant(ctx, "id", model.SizeRequest{Width: 100, Height: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, "some/resized/existing.jpeg", size.Path)
+
+	// a new size is created
+	size, err = srv.Variant(ctx, "id", model.SizeRequest{Width: 200, Height: 200})
+	assert.NoError(t, err)
+	assert.Equal(t, "some/resized/new.jpeg", size.Path)
+	assert.Equal(t, int64(len("resized")), size.Bytes)
+
+	// invalid request
+	_, err = srv.Variant(ctx, "id", model.SizeRequest{Width: 1, Height: 100})
+	assert.Error(t, err)
+}
+
+func TestImageService_VariantRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/test.jpg")).
+		DoAndReturn(func(context.Context, string) (io.Reader, error) {
+			return strings.NewReader("Some content"), nil
+		}).
+		Times(3)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(200), gomock.Eq(200), gomock.Eq(model.FormatJPEG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			return "some/resized/new.jpeg", err
+		}).
+		Times(3)
+	// renders of the attempts which aren't saved are discarded
+	storage.EXPECT().
+		Delete(gomock.Eq(ctx), gomock.Eq("some/resized/new.jpeg")).
+		Return(nil).
+		Times(2)
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Eq(ctx), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil).
+		Times(3)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, _ model.SizeRequest) error {
+			_, err := out.Write([]byte("resized"))
+			return err
+		}).
+		Times(3)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		DoAndReturn(func(context.Context, string) (*model.Image, error) {
+			return &model.Image{ID: "id", Path: "some/path/test.jpg", MimeType: "image/jpeg", Version: 1}, nil
+		}).
+		Times(4)
+	// the image is changed by concurrent requests which don't create the size
+	gomock.InOrder(
+		repo.EXPECT().
+			Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+			Return(errors.RaceCondition).
+			Times(2),
+		repo.EXPECT().
+			Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+			Return(nil),
+	)
+	repo.EXPECT().
+		Referenced(gomock.Eq(ctx), gomock.Eq("some/resized/new.jpeg")).
+		Return(false, nil).
+		Times(2)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 2, Limits{}, MetadataConfig{})
+
+	size, err := srv.Variant(ctx, "id", model.SizeRequest{Width: 200, Height: 200})
+	assert.NoError(t, err)
+	assert.Equal(t, "some/resized/new.jpeg", size.Path)
+}
+
+func TestImageService_ResizeConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	const workers = 2
+
+	var (
+		mu            sync.Mutex
+		running       int
+		maxRunning    int
+		uploadedPaths []string
+	)
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Eq("some/path/test.png")).
+		Return(strings.NewReader("Some content"), nil)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(model.FormatPNG)).
+		DoAndReturn(func(_ context.Context, in io.Reader, width, _ int, _ model.Format) (string, error) {
+			_, err := ioutil.ReadAll(in)
+			assert.NoError(t, err)
+
+			path := fmt.Sprintf("some/resized/%d.png", width)
+			mu.Lock()
+			uploadedPaths = append(uploadedPaths, path)
+			mu.Unlock()
+
+			return path, nil
+		}).
+		Times(6)
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Any(), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil).
+		Times(1)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, _ model.SizeRequest) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+			_, err := out.Write([]byte("resized"))
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			return err
+		}).
+		Times(6)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Eq("id")).
+		Return(&model.Image{ID: "id", Path: "some/path/test.png", MimeType: "image/png", Version: 1}, nil)
+	repo.EXPECT().
+		Save(gomock.Eq(ctx), gomock.Eq(1), gomock.Any()).
+		Return(nil)
+
+	sizes := make([]model.SizeRequest, 0, 7)
+	for i := 1; i <= 6; i++ {
+		sizes = append(sizes, model.SizeRequest{Width: i * 100, Height: 100})
+	}
+	// duplicated size is resized once
+	sizes = append(sizes, model.SizeRequest{Width: 100, Height: 100})
+
+	srv := New(storage, resizer, repo, nil, nil, nil, workers, Limits{}, MetadataConfig{})
+	i, err := srv.Resize(ctx, "id", sizes)
+	assert.NoError(t, err)
+	assert.Len(t, i.Sizes, 6)
+	assert.LessOrEqual(t, maxRunning, workers)
+	// sizes keep the requested order regardless of the completion order
+	for n, size := range i.Sizes {
+		assert.Equal(t, sizes[n].Width, size.Width)
+		assert.Equal(t, fmt.Sprintf("some/resized/%d.png", size.Width), size.Path)
+	}
+}
+
+func TestImageService_ResizeFailureCancelsOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Any()).
+		Return(strings.NewReader("Some content"), nil)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(uploadCtx context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			if _, err := ioutil.ReadAll(in); err != nil {
+				return "", err
+			}
+
+			return "some/resized/test.png", uploadCtx.Err()
+		}).
+		AnyTimes()
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Any(), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(resizeCtx context.Context, _ image.Image, _ io.Writer, request model.SizeRequest) error {
+			if request.Width == 100 {
+				return errors.Internal
+			}
+			// other sizes wait until they're cancelled
+			<-resizeCtx.Done()
+
+			return resizeCtx.Err()
+		}).
+		AnyTimes()
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Any()).
+		Return(&model.Image{ID: "id", MimeType: "image/png", Version: 1}, nil)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 4, Limits{}, MetadataConfig{})
+	_, err := srv.Resize(ctx, "id", []model.SizeRequest{
+		{Width: 200, Height: 100},
+		{Width: 100, Height: 100},
+		{Width: 300, Height: 100},
+	})
+	assert.Equal(t, errors.Internal, err)
+}
+
+func TestImageService_ResizeFailureDiscardsOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uploaded := make(chan struct{})
+
+	storage := mock.NewMockStorage(ctrl)
+	storage.EXPECT().
+		Read(gomock.Eq(ctx), gomock.Any()).
+		Return(strings.NewReader("Some content"), nil)
+	storage.EXPECT().
+		UploadResized(gomock.Any(), gomock.Any(), gomock.Eq(200), gomock.Eq(100), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in io.Reader, _, _ int, _ model.Format) (string, error) {
+			defer close(uploaded)
+			_, err := ioutil.ReadAll(in)
+
+			return "some/resized/test.png", err
+		})
+	// the stored sibling isn't referenced by the unsaved image
+	storage.EXPECT().
+		Delete(gomock.Any(), gomock.Eq("some/resized/test.png")).
+		Return(nil)
+
+	resizer := mock.NewMockResizer(ctrl)
+	resizer.EXPECT().
+		Decode(gomock.Any(), gomock.Any()).
+		Return(imaging.New(10, 10, color.White), nil)
+	resizer.EXPECT().
+		Resize(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ image.Image, out io.Writer, request model.SizeRequest) error {
+			if request.Width == 100 {
+				// the size fails after its sibling is stored
+				<-uploaded
+				return errors.Internal
+			}
+			_, err := out.Write([]byte("resized"))
+
+			return err
+		}).
+		Times(2)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		Get(gomock.Eq(ctx), gomock.Any()).
+		Return(&model.Image{ID: "id", MimeType: "image/png", Version: 1}, nil)
+	repo.EXPECT().
+		Referenced(gomock.Any(), gomock.Eq("some/resized/test.png")).
+		Return(false, nil)
+
+	srv := New(storage, resizer, repo, nil, nil, nil, 4, Limits{}, MetadataConfig{})
+	_, err := srv.Resize(ctx, "id", []model.SizeRequest{
+		{Width: 200, Height: 100},
+		{Width: 100, Height: 100},
+	})
+	assert.Equal(t, errors.Internal, err)
+}
+
+func TestImageService_Page(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	now := time.Now()
+	images := []*model.Image{
+		{ID: "a", UploadAt: now},
+		{ID: "b", UploadAt: now},
+		{ID: "c", UploadAt: now.Add(time.Second)},
+	}
+	filter := model.ImageFilter{MimeType: "image/png"}
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().
+		ListAfter(gomock.Eq(ctx), gomock.Eq(filter), gomock.Nil(), gomock.Eq(3)).
+		Return(images, nil)
+	repo.EXPECT().
+		ListAfter(gomock.Eq(ctx), gomock.Eq(filter), gomock.Any(), gomock.Eq(3)).
+		DoAndReturn(func(_ context.Context, _ model.ImageFilter, after *model.ImageCursor, _ int) ([]*model.Image, error) {
+			assert.Equal(t, "b", after.ID)
+			assert.True(t, now.Equal(after.UploadAt))
+
+			return images[2:], nil
+		})
+
+	srv := New(nil, nil, repo, nil, nil, nil, 1, Limits{}, MetadataConfig{})
+
+	page, err := srv.Page(ctx, filter, model.PageRequest{First: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Images, 2)
+	assert.True(t, page.HasNextPage)
+
+	page, err = srv.Page(ctx, filter, model.PageRequest{First: 2, After: model.CursorOf(page.Images[1]).String()})
+	assert.NoError(t, err)
+	assert.Len(t, page.Images, 1)
+	assert.False(t, page.HasNextPage)
+
+	// invalid requests
+	_, err = srv.Page(ctx, filter, model.PageRequest{First: 0})
+	assert.IsType(t, errors.InvalidParams{}, err)
+	_, err = srv.Page(ctx, filter, model.PageRequest{First: 2, After: "invalid"})
+	assert.IsType(t, errors.InvalidParams{}, err)
+}
@@ -0,0 +1,121 @@
+package servicetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type JobRepositoryFactory func(t *testing.T) (service.JobRepository, func())
+
+// TestJobRepository gets a new repository from the factory for every check.
+func TestJobRepository(t *testing.T, newRepository JobRepositoryFactory) {
+	checks := []struct {
+		name  string
+		check func(t *testing.T, repo service.JobRepository)
+	}{
+		{"GetJob", testGetJob},
+		{"SaveJob", testSaveJob},
+		{"ListUnfinishedJobs", testListUnfinishedJobs},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			repo, release := newRepository(t)
+			defer release()
+
+			c.check(t, repo)
+		})
+	}
+}
+
+func testGetJob(t *testing.T, repo service.JobRepository) {
+	ctx := context.Background()
+
+	_, err := repo.GetJob(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	job := model.Job{
+		ID:      "job",
+		ImageID: "id",
+		Sizes: []model.SizeRequest{{
+			Width:      100,
+			Height:     100,
+			Mode:       model.ResizeModeFill,
+			Operations: []model.Operation{{Name: model.OperationBlur, Value: 2}},
+			Preset:     "thumb",
+		}},
+		Status:    model.JobStatusPending,
+		CreatedAt: uploadAt,
+		UpdatedAt: uploadAt,
+	}
+	assert.NoError(t, repo.SaveJob(ctx, job))
+
+	removal := model.Job{
+		ID:        "removal",
+		Kind:      model.JobKindRemove,
+		Paths:     []string{"100_100/hash.png", "origin/id.png"},
+		Status:    model.JobStatusFailed,
+		Error:     "can't remove",
+		Attempts:  3,
+		CreatedAt: uploadAt,
+		UpdatedAt: uploadAt.Add(time.Minute),
+	}
+	assert.NoError(t, repo.SaveJob(ctx, removal))
+
+	res, err := repo.GetJob(ctx, "job")
+	assert.NoError(t, err)
+	assert.Equal(t, &job, res)
+
+	res, err = repo.GetJob(ctx, "removal")
+	assert.NoError(t, err)
+	assert.Equal(t, &removal, res)
+}
+
+func testSaveJob(t *testing.T, repo service.JobRepository) {
+	ctx := context.Background()
+
+	job := model.Job{ID: "job", Kind: model.JobKindCleanup, ImageID: "id", Status: model.JobStatusPending, CreatedAt: uploadAt, UpdatedAt: uploadAt}
+	assert.NoError(t, repo.SaveJob(ctx, job))
+
+	// saving a job replaces it
+	job.Status = model.JobStatusDone
+	job.Attempts = 1
+	job.UpdatedAt = uploadAt.Add(time.Minute)
+	assert.NoError(t, repo.SaveJob(ctx, job))
+
+	res, err := repo.GetJob(ctx, "job")
+	assert.NoError(t, err)
+	assert.Equal(t, &job, res)
+}
+
+func testListUnfinishedJobs(t *testing.T, repo service.JobRepository) {
+	ctx := context.Background()
+
+	jobs, err := repo.ListUnfinishedJobs(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	for _, job := range []model.Job{
+		{ID: "done", Status: model.JobStatusDone, CreatedAt: uploadAt},
+		{ID: "failed", Status: model.JobStatusFailed, CreatedAt: uploadAt},
+		{ID: "running", Status: model.JobStatusRunning, CreatedAt: uploadAt.Add(time.Second)},
+		{ID: "pending", Status: model.JobStatusPending, CreatedAt: uploadAt},
+	} {
+		assert.NoError(t, repo.SaveJob(ctx, job))
+	}
+
+	// the oldest jobs come first
+	jobs, err = repo.ListUnfinishedJobs(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, jobs, 2) {
+		assert.Equal(t, "pending", jobs[0].ID)
+		assert.Equal(t, "running", jobs[1].ID)
+	}
+}
@@ -0,0 +1,135 @@
+package servicetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type PresetRepositoryFactory func(t *testing.T) (service.PresetRepository, func())
+
+// TestPresetRepository gets a new repository from the factory for every check.
+func TestPresetRepository(t *testing.T, newRepository PresetRepositoryFactory) {
+	checks := []struct {
+		name  string
+		check func(t *testing.T, repo service.PresetRepository)
+	}{
+		{"GetPreset", testGetPreset},
+		{"SavePreset", testSavePreset},
+		{"ListPresets", testListPresets},
+		{"DeletePreset", testDeletePreset},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			repo, release := newRepository(t)
+			defer release()
+
+			c.check(t, repo)
+		})
+	}
+}
+
+func testGetPreset(t *testing.T, repo service.PresetRepository) {
+	ctx := context.Background()
+
+	_, err := repo.GetPreset(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	preset := model.Preset{
+		Name: "thumb",
+		Size: model.SizeRequest{
+			Width:      100,
+			Height:     100,
+			Mode:       model.ResizeModeFill,
+			Anchor:     model.AnchorSmart,
+			Format:     model.FormatJPEG,
+			Quality:    80,
+			Operations: []model.Operation{{Name: model.OperationSharpen, Value: 0.5}},
+			Watermark:  "logo",
+		},
+		Version:   1,
+		UpdatedAt: uploadAt,
+	}
+	assert.NoError(t, repo.SavePreset(ctx, 0, preset))
+
+	res, err := repo.GetPreset(ctx, "thumb")
+	assert.NoError(t, err)
+	assert.Equal(t, &preset, res)
+}
+
+func testSavePreset(t *testing.T, repo service.PresetRepository) {
+	ctx := context.Background()
+
+	preset := model.Preset{Name: "thumb", Size: model.SizeRequest{Width: 100, Height: 100}, Version: 1, UpdatedAt: uploadAt}
+	assert.NoError(t, repo.SavePreset(ctx, 0, preset))
+
+	// creating a preset which exists
+	assert.Equal(t, errors.RaceCondition, repo.SavePreset(ctx, 0, preset))
+
+	updated := preset
+	updated.Size.Width = 200
+	updated.Version = 2
+	assert.NoError(t, repo.SavePreset(ctx, 1, updated))
+
+	// saving a stale version
+	stale := preset
+	stale.Size.Width = 300
+	stale.Version = 2
+	assert.Equal(t, errors.RaceCondition, repo.SavePreset(ctx, 1, stale))
+
+	// saving a preset which doesn't exist
+	assert.Equal(t, errors.RaceCondition, repo.SavePreset(ctx, 1, model.Preset{Name: "missing", Version: 2}))
+	_, err := repo.GetPreset(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	res, err := repo.GetPreset(ctx, "thumb")
+	assert.NoError(t, err)
+	assert.Equal(t, &updated, res)
+}
+
+func testListPresets(t *testing.T, repo service.PresetRepository) {
+	ctx := context.Background()
+
+	presets, err := repo.ListPresets(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, presets)
+
+	for _, name := range []string{"thumb", "avatar", "card"} {
+		assert.NoError(t, repo.SavePreset(ctx, 0, model.Preset{Name: name, Size: model.SizeRequest{Width: 100, Height: 100}, Version: 1}))
+	}
+
+	// presets are sorted by name
+	presets, err = repo.ListPresets(ctx)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(presets))
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+	assert.Equal(t, []string{"avatar", "card", "thumb"}, names)
+}
+
+func testDeletePreset(t *testing.T, repo service.PresetRepository) {
+	ctx := context.Background()
+
+	assert.Equal(t, errors.NotFound, repo.DeletePreset(ctx, "thumb"))
+
+	assert.NoError(t, repo.SavePreset(ctx, 0, model.Preset{Name: "thumb", Version: 1}))
+	assert.NoError(t, repo.SavePreset(ctx, 0, model.Preset{Name: "avatar", Version: 1}))
+
+	assert.NoError(t, repo.DeletePreset(ctx, "thumb"))
+	_, err := repo.GetPreset(ctx, "thumb")
+	assert.Equal(t, errors.NotFound, err)
+
+	// other presets are kept
+	_, err = repo.GetPreset(ctx, "avatar")
+	assert.NoError(t, err)
+
+	// a deleted preset can be created again
+	assert.NoError(t, repo.SavePreset(ctx, 0, model.Preset{Name: "thumb", Version: 1}))
+}
@@ -0,0 +1,476 @@
+// Package servicetest checks that repository and storage backends implement the contracts of the service.
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const concurrency = 10
+
+type RepositoryFactory func(t *testing.T) (service.Repository, func())
+
+// uploadAt is the upload time of test images, it's in UTC and rounded to milliseconds, so all backends keep it as is.
+var uploadAt = time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// TestRepository gets a new repository from the factory for every check.
+func TestRepository(t *testing.T, newRepository RepositoryFactory) {
+	checks := []struct {
+		name  string
+		check func(t *testing.T, repo service.Repository)
+	}{
+		{"Get", testGet},
+		{"Save", testSave},
+		{"Delete", testDelete},
+		{"List", testList},
+		{"ListAfter", testListAfter},
+		{"ListPresetImages", testListPresetImages},
+		{"ListTrashed", testListTrashed},
+		{"Shared", testShared},
+		{"Referenced", testReferenced},
+		{"ConcurrentSave", testConcurrentSave},
+		{"ContextCanceled", testRepositoryContextCanceled},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			repo, release := newRepository(t)
+			defer release()
+
+			c.check(t, repo)
+		})
+	}
+}
+
+func newImage(id string, uploadAt time.Time) model.Image {
+	return model.Image{
+		ID:         id,
+		Path:       "origin/" + id + ".png",
+		ClientName: id + ".png",
+		MimeType:   "image/png",
+		Size:       1000,
+		UploadAt:   uploadAt,
+		Sizes:      []model.Size{},
+		Version:    1,
+	}
+}
+
+func save(t *testing.T, repo service.Repository, images ...model.Image) {
+	for _, image := range images {
+		assert.NoError(t, repo.Save(context.Background(), 0, image))
+	}
+}
+
+func ids(images []*model.Image) []string {
+	res := []string{}
+	for _, image := range images {
+		res = append(res, image.ID)
+	}
+
+	return res
+}
+
+func testGet(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	_, err := repo.Get(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	image := newImage("id", uploadAt)
+	image.Sizes = []model.Size{{
+		Path:   "100_100/hash.png",
+		Width:  100,
+		Height: 100,
+		Bytes:  10,
+		Mode:   model.ResizeModeFill,
+		Anchor: model.AnchorCenter,
+		Format: model.FormatPNG,
+		Status: model.SizeStatusReady,
+		Preset: "thumb",
+	}}
+	image.Hash = "hash"
+	image.Metadata = model.Metadata{
+		Format:      model.FormatPNG,
+		Width:       200,
+		Height:      100,
+		Orientation: 1,
+		Exif:        &model.Exif{Make: "Camera", FNumber: 1.8},
+	}
+	image.MetadataPolicy = model.MetadataPolicyStripGPS
+	image.JobID = "job"
+	save(t, repo, image)
+
+	res, err := repo.Get(ctx, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, &image, res)
+}
+
+func testSave(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	image := newImage("id", uploadAt)
+	save(t, repo, image)
+
+	// inserting an image which exists
+	assert.Equal(t, errors.RaceCondition, repo.Save(ctx, 0, image))
+
+	updated := image
+	updated.Path = "v2path"
+	updated.Version = 2
+	assert.NoError(t, repo.Save(ctx, 1, updated))
+
+	// saving a stale version
+	stale := image
+	stale.Path = "stale"
+	stale.Version = 2
+	assert.Equal(t, errors.RaceCondition, repo.Save(ctx, 1, stale))
+
+	// saving an image which doesn't exist
+	missing := newImage("missing", uploadAt)
+	assert.Equal(t, errors.RaceCondition, repo.Save(ctx, 1, missing))
+	_, err := repo.Get(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	res, err := repo.Get(ctx, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, &updated, res)
+}
+
+func testDelete(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	save(t, repo, newImage("id", uploadAt))
+
+	assert.Equal(t, errors.RaceCondition, repo.Delete(ctx, "id", 2))
+	_, err := repo.Get(ctx, "id")
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Delete(ctx, "id", 1))
+	_, err = repo.Get(ctx, "id")
+	assert.Equal(t, errors.NotFound, err)
+
+	assert.Equal(t, errors.RaceCondition, repo.Delete(ctx, "id", 1))
+}
+
+func testList(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	a := newImage("a", uploadAt)
+	a.ClientName, a.MimeType, a.Size = "cat.jpg", "image/jpeg", 3000
+	b := newImage("b", uploadAt.Add(time.Second))
+	b.ClientName, b.Size = "dog.png", 1000
+	c := newImage("c", uploadAt)
+	c.ClientName, c.Size = "cat.png", 2000
+	d := newImage("d", uploadAt)
+	d.ClientName, d.Size = "cat.gif", 4000
+	d.MarkDeleted()
+	save(t, repo, a, b, c, d)
+
+	f := func(filter model.ImageFilter, sort model.ImageSort, limit, offset int, expected ...string) {
+		images, err := repo.List(ctx, filter, sort, limit, offset)
+		assert.NoError(t, err)
+		assert.Equal(t, append([]string{}, expected...), ids(images), "%+v %+v %d %d", filter, sort, limit, offset)
+	}
+
+	f(model.ImageFilter{}, model.ImageSort{}, 10, 0, "a", "c", "b")
+	f(model.ImageFilter{}, model.ImageSort{Desc: true}, 10, 0, "b", "c", "a")
+	f(model.ImageFilter{}, model.ImageSort{Field: model.ImageSortSize}, 10, 0, "b", "c", "a")
+	f(model.ImageFilter{}, model.ImageSort{Field: model.ImageSortSize, Desc: true}, 2, 0, "a", "c")
+	f(model.ImageFilter{}, model.ImageSort{}, 2, 1, "c", "b")
+	f(model.ImageFilter{}, model.ImageSort{}, 1, 5)
+	f(model.ImageFilter{MimeType: "image/png"}, model.ImageSort{}, 10, 0, "c", "b")
+	f(model.ImageFilter{ClientNamePrefix: "cat"}, model.ImageSort{}, 10, 0, "a", "c")
+	f(model.ImageFilter{ClientNamePrefix: "cat"}, model.ImageSort{Desc: true}, 1, 1, "a")
+	f(model.ImageFilter{UploadedAfter: uploadAt.Add(time.Second)}, model.ImageSort{}, 10, 0, "b")
+	f(model.ImageFilter{UploadedBefore: uploadAt.Add(time.Second)}, model.ImageSort{}, 10, 0, "a", "c")
+	f(model.ImageFilter{UploadedBefore: uploadAt.Add(time.Second)}, model.ImageSort{Desc: true}, 10, 0, "c", "a")
+
+	count := func(filter model.ImageFilter, expected int) {
+		res, err := repo.Count(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res, "%+v", filter)
+	}
+
+	count(model.ImageFilter{}, 3)
+	count(model.ImageFilter{MimeType: "image/png"}, 2)
+	count(model.ImageFilter{UploadedAfter: uploadAt.Add(time.Second)}, 1)
+}
+
+func testListAfter(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	var all []string
+	for i := 0; i < 5; i++ {
+		// images uploaded at the same time are ordered by IDs
+		image := newImage(fmt.Sprintf("id%d", i), uploadAt.Add(time.Duration(i/2)*time.Second))
+		save(t, repo, image)
+		all = append(all, image.ID)
+	}
+	deleted := newImage("deleted", uploadAt)
+	deleted.MarkDeleted()
+	save(t, repo, deleted)
+
+	var (
+		listed []string
+		after  *model.ImageCursor
+	)
+	for i := 0; i < len(all); i++ {
+		images, err := repo.ListAfter(ctx, model.ImageFilter{}, after, 2)
+		assert.NoError(t, err)
+		if len(images) == 0 {
+			break
+		}
+		assert.True(t, len(images) <= 2)
+
+		listed = append(listed, ids(images)...)
+		cursor := model.CursorOf(images[len(images)-1])
+		after = &cursor
+	}
+	assert.Equal(t, all, listed)
+
+	// the filter applies to pages
+	cursor := model.CursorOf(&model.Image{ID: "id1", UploadAt: uploadAt})
+	images, err := repo.ListAfter(ctx, model.ImageFilter{UploadedBefore: uploadAt.Add(2 * time.Second)}, &cursor, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id2", "id3"}, ids(images))
+}
+
+func testListPresetImages(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	withPreset := func(id, preset string) model.Image {
+		image := newImage(id, uploadAt)
+		image.Sizes = []model.Size{
+			{Path: id + "/other", Width: 10, Height: 10, Preset: "other"},
+			{Path: id + "/preset", Width: 100, Height: 100, Preset: preset},
+		}
+		return image
+	}
+	deleted := withPreset("deleted", "thumb")
+	deleted.MarkDeleted()
+	save(t, repo, withPreset("a", "thumb"), withPreset("b", "card"), withPreset("c", "thumb"), deleted)
+
+	res, err := repo.ListPresetImages(ctx, "thumb", "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, res)
+
+	// pages follow the last ID of the previous page
+	res, err = repo.ListPresetImages(ctx, "thumb", "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, res)
+	res, err = repo.ListPresetImages(ctx, "thumb", "a", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, res)
+	res, err = repo.ListPresetImages(ctx, "thumb", "c", 1)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	res, err = repo.ListPresetImages(ctx, "missing", "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func testListTrashed(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	trashed := newImage("trashed", uploadAt)
+	trashed.AddSize("trashed/small", 10, model.SizeRequest{Width: 10, Height: 10})
+	trashed.RemoveSizes(10, 10)
+	deleted := newImage("deleted", uploadAt)
+	deleted.MarkDeleted()
+	save(t, repo, newImage("clean", uploadAt), trashed, deleted)
+
+	res, err := repo.ListTrashed(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"trashed", "deleted"}, res)
+
+	// the emptied trash is saved
+	trashed.EmptyTrash(trashed.Trash)
+	trashed.Version++
+	assert.NoError(t, repo.Save(ctx, 1, trashed))
+
+	stored, err := repo.Get(ctx, "trashed")
+	assert.NoError(t, err)
+	assert.Empty(t, stored.Trash)
+
+	res, err = repo.ListTrashed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"deleted"}, res)
+}
+
+func testShared(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	_, err := repo.GetShared(ctx, "hash")
+	assert.Equal(t, errors.NotFound, err)
+
+	image := newImage("id", uploadAt)
+	image.Hash = "hash"
+	image.Shared = true
+	private := newImage("private", uploadAt)
+	private.Hash = "hash"
+	save(t, repo, image, private)
+
+	res, err := repo.GetShared(ctx, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, &image, res)
+
+	// at most one image is shared per hash
+	other := newImage("other", uploadAt)
+	other.Hash = "hash"
+	other.Shared = true
+	assert.Equal(t, errors.RaceCondition, repo.Save(ctx, 0, other))
+
+	// the content of a deleted image can be shared again
+	image.MarkDeleted()
+	image.Version = 2
+	assert.NoError(t, repo.Save(ctx, 1, image))
+	_, err = repo.GetShared(ctx, "hash")
+	assert.Equal(t, errors.NotFound, err)
+
+	assert.NoError(t, repo.Save(ctx, 0, other))
+	res, err = repo.GetShared(ctx, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "other", res.ID)
+}
+
+func testReferenced(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	f := func(path string, expected bool) {
+		referenced, err := repo.Referenced(ctx, path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, referenced, path)
+	}
+
+	image := newImage("id", uploadAt)
+	image.Path = "original"
+	image.Sizes = []model.Size{{Path: "variant", Width: 100, Height: 100}}
+	save(t, repo, image)
+
+	f("original", true)
+	f("variant", true)
+	f("orig", false)
+	f("missing", false)
+
+	// replaced objects aren't referenced
+	image.RemoveSizes(100, 100)
+	image.Version = 2
+	assert.NoError(t, repo.Save(ctx, 1, image))
+	f("variant", false)
+
+	// deleted images don't reference objects
+	image.MarkDeleted()
+	image.Version = 3
+	assert.NoError(t, repo.Save(ctx, 2, image))
+	f("original", false)
+}
+
+func testConcurrentSave(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+
+	// race runs the function concurrently and returns the number of successful calls
+	race := func(save func(i int) error) int {
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				err := save(i)
+				if err != nil {
+					assert.Equal(t, errors.RaceCondition, err)
+					return
+				}
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}(i)
+		}
+		wg.Wait()
+
+		return succeeded
+	}
+
+	// concurrent inserts of the same image
+	inserted := race(func(i int) error {
+		image := newImage("id", uploadAt)
+		image.Path = fmt.Sprintf("path%d", i)
+		return repo.Save(ctx, 0, image)
+	})
+	assert.Equal(t, 1, inserted)
+
+	// concurrent updates of the same version
+	updated := race(func(i int) error {
+		image := newImage("id", uploadAt)
+		image.Path = fmt.Sprintf("updated%d", i)
+		image.Version = 2
+		return repo.Save(ctx, 1, image)
+	})
+	assert.Equal(t, 1, updated)
+
+	res, err := repo.Get(ctx, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Version)
+	assert.Contains(t, res.Path, "updated")
+
+	// concurrent inserts of shared images with the same hash
+	shared := race(func(i int) error {
+		image := newImage(fmt.Sprintf("shared%d", i), uploadAt)
+		image.Hash = "hash"
+		image.Shared = true
+		return repo.Save(ctx, 0, image)
+	})
+	assert.Equal(t, 1, shared)
+
+	// concurrent deletes of the same version
+	deleted := race(func(int) error {
+		return repo.Delete(ctx, "id", 2)
+	})
+	assert.Equal(t, 1, deleted)
+}
+
+func testRepositoryContextCanceled(t *testing.T, repo service.Repository) {
+	image := newImage("id", uploadAt)
+	save(t, repo, image)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.Get(ctx, "id")
+	assert.Error(t, err)
+	_, err = repo.List(ctx, model.ImageFilter{}, model.ImageSort{}, 10, 0)
+	assert.Error(t, err)
+	_, err = repo.ListAfter(ctx, model.ImageFilter{}, nil, 10)
+	assert.Error(t, err)
+	_, err = repo.Count(ctx, model.ImageFilter{})
+	assert.Error(t, err)
+	_, err = repo.Referenced(ctx, image.Path)
+	assert.Error(t, err)
+
+	// canceled changes aren't applied
+	updated := image
+	updated.Version = 2
+	assert.Error(t, repo.Save(ctx, 1, updated))
+	assert.Error(t, repo.Save(ctx, 0, newImage("other", uploadAt)))
+	assert.Error(t, repo.Delete(ctx, "id", 1))
+
+	res, err := repo.Get(context.Background(), "id")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Version)
+	_, err = repo.Get(context.Background(), "other")
+	assert.Equal(t, errors.NotFound, err)
+}
@@ -0,0 +1,212 @@
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type StorageFactory func(t *testing.T) (service.Storage, func())
+
+// TestStorage gets a new storage from the factory for every check.
+func TestStorage(t *testing.T, newStorage StorageFactory) {
+	checks := []struct {
+		name  string
+		check func(t *testing.T, storage service.Storage)
+	}{
+		{"Read", testRead},
+		{"Upload", testUpload},
+		{"UploadResized", testUploadResized},
+		{"UploadFailed", testUploadFailed},
+		{"Modified", testModified},
+		{"Delete", testStorageDelete},
+		{"ConcurrentUpload", testConcurrentUpload},
+		{"ContextCanceled", testStorageContextCanceled},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			storage, release := newStorage(t)
+			defer release()
+
+			c.check(t, storage)
+		})
+	}
+}
+
+func read(t *testing.T, storage service.Storage, path string) string {
+	reader, err := storage.Read(context.Background(), path)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	content, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+
+	return string(content)
+}
+
+func testRead(t *testing.T, storage service.Storage) {
+	_, err := storage.Read(context.Background(), "2020/05/01/origin/missing.png")
+	assert.Equal(t, errors.NotFound, err)
+}
+
+func testUpload(t *testing.T, storage service.Storage) {
+	ctx := context.Background()
+
+	path, err := storage.Upload(ctx, strings.NewReader("Some content"), model.FormatPNG)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\d{4}/\d{2}/\d{2}/origin/[0-9a-f-]{36}\.png$`, path)
+	assert.Equal(t, "Some content", read(t, storage, path))
+
+	// originals are never shared
+	other, err := storage.Upload(ctx, strings.NewReader("Some content"), model.FormatPNG)
+	assert.NoError(t, err)
+	assert.NotEqual(t, path, other)
+	assert.Equal(t, "Some content", read(t, storage, other))
+}
+
+func testUploadResized(t *testing.T, storage service.Storage) {
+	ctx := context.Background()
+
+	path, err := storage.UploadResized(ctx, strings.NewReader("Some content"), 100, 200, model.FormatJPEG)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^100_200/[0-9a-f]{64}\.jpeg$`, path)
+	assert.Equal(t, "Some content", read(t, storage, path))
+
+	// identical renders share the object
+	same, err := storage.UploadResized(ctx, strings.NewReader("Some content"), 100, 200, model.FormatJPEG)
+	assert.NoError(t, err)
+	assert.Equal(t, path, same)
+
+	other, err := storage.UploadResized(ctx, strings.NewReader("Other content"), 100, 200, model.FormatJPEG)
+	assert.NoError(t, err)
+	assert.NotEqual(t, path, other)
+	assert.Equal(t, "Other content", read(t, storage, other))
+	assert.Equal(t, "Some content", read(t, storage, path))
+}
+
+func testUploadFailed(t *testing.T, storage service.Storage) {
+	ctx := context.Background()
+
+	// errors of the content, e.g. exceeded limits, are passed as is
+	_, err := storage.Upload(ctx, io.MultiReader(strings.NewReader("Some"), failingReader{}), model.FormatPNG)
+	assert.Equal(t, errors.ImageTooLarge, err)
+
+	_, err = storage.UploadResized(ctx, io.MultiReader(strings.NewReader("Some"), failingReader{}), 100, 100, model.FormatPNG)
+	assert.Equal(t, errors.ImageTooLarge, err)
+}
+
+func testModified(t *testing.T, storage service.Storage) {
+	ctx := context.Background()
+
+	_, err := storage.Modified(ctx, "100_100/missing.png")
+	assert.Equal(t, errors.NotFound, err)
+
+	before := time.Now().Add(-time.Minute)
+	path, err := storage.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+	assert.NoError(t, err)
+
+	modified, err := storage.Modified(ctx, path)
+	assert.NoError(t, err)
+	assert.True(t, modified.After(before), "modified at %s", modified)
+
+	// an identical render replaces the object
+	time.Sleep(10 * time.Millisecond)
+	_, err = storage.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+	assert.NoError(t, err)
+	replaced, err := storage.Modified(ctx, path)
+	assert.NoError(t, err)
+	assert.False(t, replaced.Before(modified))
+}
+
+func testStorageDelete(t *testing.T, storage service.Storage) {
+	ctx := context.Background()
+
+	path, err := storage.Upload(ctx, strings.NewReader("Some content"), model.FormatPNG)
+	assert.NoError(t, err)
+	variant, err := storage.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Delete(ctx, path))
+	_, err = storage.Read(ctx, path)
+	assert.Equal(t, errors.NotFound, err)
+
+	// other objects are kept
+	assert.Equal(t, "Some content", read(t, storage, variant))
+
+	// removed already
+	assert.NoError(t, storage.Delete(ctx, path))
+}
+
+func testConcurrentUpload(t *testing.T, storage service.Storage) {
+	ctx := context.Background()
+
+	var (
+		wg        sync.WaitGroup
+		originals = make([]string, concurrency)
+		variants  = make([]string, concurrency)
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			originals[i], err = storage.Upload(ctx, strings.NewReader(fmt.Sprintf("Content %d", i)), model.FormatPNG)
+			assert.NoError(t, err)
+
+			// identical renders of concurrent requests
+			variants[i], err = storage.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, path := range originals {
+		assert.Equal(t, fmt.Sprintf("Content %d", i), read(t, storage, path))
+		assert.Equal(t, variants[0], variants[i])
+	}
+	assert.Equal(t, "Some content", read(t, storage, variants[0]))
+}
+
+func testStorageContextCanceled(t *testing.T, storage service.Storage) {
+	path, err := storage.Upload(context.Background(), strings.NewReader("Some content"), model.FormatPNG)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = storage.Read(ctx, path)
+	assert.Error(t, err)
+	_, err = storage.Upload(ctx, strings.NewReader("Some content"), model.FormatPNG)
+	assert.Error(t, err)
+
+	// the canceled render isn't stored
+	variant, err := storage.UploadResized(ctx, strings.NewReader("Other content"), 100, 100, model.FormatPNG)
+	assert.Error(t, err)
+	if variant != "" {
+		_, err = storage.Read(context.Background(), variant)
+		assert.Equal(t, errors.NotFound, err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.ImageTooLarge
+}
@@ -0,0 +1,139 @@
+package servicetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type WatermarkRepositoryFactory func(t *testing.T) (service.WatermarkRepository, func())
+
+// TestWatermarkRepository gets a new repository from the factory for every check.
+func TestWatermarkRepository(t *testing.T, newRepository WatermarkRepositoryFactory) {
+	checks := []struct {
+		name  string
+		check func(t *testing.T, repo service.WatermarkRepository)
+	}{
+		{"GetWatermark", testGetWatermark},
+		{"SaveWatermark", testSaveWatermark},
+		{"ListWatermarks", testListWatermarks},
+		{"DeleteWatermark", testDeleteWatermark},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			repo, release := newRepository(t)
+			defer release()
+
+			c.check(t, repo)
+		})
+	}
+}
+
+func testGetWatermark(t *testing.T, repo service.WatermarkRepository) {
+	ctx := context.Background()
+
+	_, err := repo.GetWatermark(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	watermark := model.Watermark{
+		ID:        "logo",
+		Path:      "2020/05/01/origin/logo.png",
+		Position:  model.AnchorBottomRight,
+		Opacity:   0.5,
+		Scale:     0.25,
+		Tile:      true,
+		Version:   1,
+		UpdatedAt: uploadAt,
+	}
+	assert.NoError(t, repo.SaveWatermark(ctx, 0, watermark))
+
+	res, err := repo.GetWatermark(ctx, "logo")
+	assert.NoError(t, err)
+	assert.Equal(t, &watermark, res)
+}
+
+func testSaveWatermark(t *testing.T, repo service.WatermarkRepository) {
+	ctx := context.Background()
+
+	watermark := model.Watermark{ID: "logo", Path: "logo.png", Opacity: 0.5, Scale: 0.25, Version: 1, UpdatedAt: uploadAt}
+	assert.NoError(t, repo.SaveWatermark(ctx, 0, watermark))
+
+	// creating a watermark which exists
+	assert.Equal(t, errors.RaceCondition, repo.SaveWatermark(ctx, 0, watermark))
+
+	// soft deleted profiles are saved as any other change
+	updated := watermark
+	updated.Deleted = true
+	updated.Version = 2
+	assert.NoError(t, repo.SaveWatermark(ctx, 1, updated))
+
+	// saving a stale version
+	stale := watermark
+	stale.Path = "stale.png"
+	stale.Version = 2
+	assert.Equal(t, errors.RaceCondition, repo.SaveWatermark(ctx, 1, stale))
+
+	// saving a watermark which doesn't exist
+	assert.Equal(t, errors.RaceCondition, repo.SaveWatermark(ctx, 1, model.Watermark{ID: "missing", Version: 2}))
+	_, err := repo.GetWatermark(ctx, "missing")
+	assert.Equal(t, errors.NotFound, err)
+
+	res, err := repo.GetWatermark(ctx, "logo")
+	assert.NoError(t, err)
+	assert.Equal(t, &updated, res)
+
+	// restoring the profile clears the flag
+	updated.Deleted = false
+	updated.Version = 3
+	assert.NoError(t, repo.SaveWatermark(ctx, 2, updated))
+	res, err = repo.GetWatermark(ctx, "logo")
+	assert.NoError(t, err)
+	assert.Equal(t, &updated, res)
+}
+
+func testListWatermarks(t *testing.T, repo service.WatermarkRepository) {
+	ctx := context.Background()
+
+	watermarks, err := repo.ListWatermarks(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, watermarks)
+
+	for _, id := range []string{"logo", "badge", "stamp"} {
+		assert.NoError(t, repo.SaveWatermark(ctx, 0, model.Watermark{ID: id, Path: id + ".png", Version: 1}))
+	}
+
+	// watermarks are sorted by ID
+	watermarks, err = repo.ListWatermarks(ctx)
+	assert.NoError(t, err)
+	ids := make([]string, 0, len(watermarks))
+	for _, watermark := range watermarks {
+		ids = append(ids, watermark.ID)
+	}
+	assert.Equal(t, []string{"badge", "logo", "stamp"}, ids)
+}
+
+func testDeleteWatermark(t *testing.T, repo service.WatermarkRepository) {
+	ctx := context.Background()
+
+	assert.Equal(t, errors.NotFound, repo.DeleteWatermark(ctx, "logo"))
+
+	assert.NoError(t, repo.SaveWatermark(ctx, 0, model.Watermark{ID: "logo", Version: 1}))
+	assert.NoError(t, repo.SaveWatermark(ctx, 0, model.Watermark{ID: "badge", Version: 1}))
+
+	assert.NoError(t, repo.DeleteWatermark(ctx, "logo"))
+	_, err := repo.GetWatermark(ctx, "logo")
+	assert.Equal(t, errors.NotFound, err)
+
+	// other watermarks are kept
+	_, err = repo.GetWatermark(ctx, "badge")
+	assert.NoError(t, err)
+
+	// a deleted watermark can be created again
+	assert.NoError(t, repo.SaveWatermark(ctx, 0, model.Watermark{ID: "logo", Version: 1}))
+}
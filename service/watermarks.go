@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	log "github.com/sirupsen/logrus"
+)
+
+func (s *ImageService) Watermarks(ctx context.Context) ([]*model.Watermark, error) {
+	watermarks, err := s.watermarks.ListWatermarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Watermark, 0, len(watermarks))
+	for _, watermark := range watermarks {
+		if !watermark.Deleted {
+			res = append(res, watermark)
+		}
+	}
+
+	return res, nil
+}
+
+// SaveWatermark removes a replaced overlay by a background job, so renders which loaded the profile before can read it.
+func (s *ImageService) SaveWatermark(ctx context.Context, watermark model.Watermark, overlay *model.ImageUpload) (*model.Watermark, error) {
+	watermark.Path = ""
+	watermark.UpdatedAt = time.Now()
+	if err := s.validateParams(watermark); len(err) > 0 {
+		return nil, err
+	}
+
+	version := 0
+	existing, err := s.watermarks.GetWatermark(ctx, watermark.ID)
+	if err != nil && err != errors.NotFound {
+		return nil, err
+	}
+	if existing != nil {
+		version = existing.Version
+		watermark.Path = existing.Path
+	}
+	watermark.Deleted = false
+	watermark.Version = version + 1
+
+	if overlay == nil && existing == nil {
+		return nil, errors.InvalidParams{{Param: "overlay", Message: "required"}}
+	}
+	if overlay != nil {
+		if watermark.Path, err = s.uploadOverlay(ctx, *overlay); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.watermarks.SaveWatermark(ctx, version, watermark); err != nil {
+		if overlay != nil {
+			s.removeOverlay(ctx, watermark.Path)
+		}
+		return nil, err
+	}
+	if overlay != nil && existing != nil {
+		s.scheduleRemoval(ctx, []string{existing.Path})
+	}
+
+	return &watermark, nil
+}
+
+// DeleteWatermark keeps the overlay, so sizes composited with the profile are rendered again by edits of their images.
+func (s *ImageService) DeleteWatermark(ctx context.Context, id string) error {
+	watermark, err := s.watermarks.GetWatermark(ctx, id)
+	if err != nil {
+		return err
+	}
+	if watermark.Deleted {
+		return errors.NotFound
+	}
+
+	version := watermark.Version
+	watermark.Version++
+	watermark.Deleted = true
+	watermark.UpdatedAt = time.Now()
+	if err := s.watermarks.SaveWatermark(ctx, version, *watermark); err != nil {
+		return err
+	}
+
+	// presets are checked after the profile is hidden, a preset saved meanwhile is either listed here
+	// or sees the profile deleted when it checks the profile again
+	used, err := s.watermarkPreset(ctx, id)
+	if err == nil && used == "" {
+		return nil
+	}
+	if err == nil {
+		err = errors.InvalidParams{{Param: "id", Message: "watermark is used by preset " + used}}
+	}
+
+	version = watermark.Version
+	watermark.Version++
+	watermark.Deleted = false
+	watermark.UpdatedAt = time.Now()
+	if restoreErr := s.watermarks.SaveWatermark(ctx, version, *watermark); restoreErr != nil {
+		log.Error("can't restore watermark ", id, " ", restoreErr)
+	}
+
+	return err
+}
+
+func (s *ImageService) watermarkPreset(ctx context.Context, id string) (string, error) {
+	presets, err := s.presets.ListPresets(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, preset := range presets {
+		if preset.Size.Watermark == id {
+			return preset.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s *ImageService) checkWatermark(ctx context.Context, size model.SizeRequest) error {
+	if size.Watermark == "" {
+		return nil
+	}
+
+	watermark, err := s.watermarks.GetWatermark(ctx, size.Watermark)
+	if err == nil && watermark.Deleted {
+		err = errors.NotFound
+	}
+	if err == errors.NotFound {
+		return errors.InvalidParams{{Param: "watermark", Message: "unknown watermark " + size.Watermark}}
+	}
+
+	return err
+}
+
+func (s *ImageService) uploadOverlay(ctx context.Context, upload model.ImageUpload) (string, error) {
+	if err := s.validateParams(upload); len(err) > 0 {
+		return "", err
+	}
+	if s.limits.MaxUploadBytes > 0 {
+		if upload.Size > s.limits.MaxUploadBytes {
+			return "", errors.ImageTooLarge
+		}
+		upload.Content = &limitedReader{reader: upload.Content, remaining: s.limits.MaxUploadBytes}
+	}
+
+	content, metadata, err := s.inspect(ctx, upload)
+	if err != nil {
+		return "", err
+	}
+
+	return s.storage.Upload(ctx, content, metadata.Format)
+}
+
+func (s *ImageService) removeOverlay(ctx context.Context, path string) {
+	if err := s.storage.Delete(ctx, path); err != nil {
+		log.Error("can't remove watermark overlay ", path, " ", err)
+	}
+}
+
+// loadOverlays loads overlays of deleted profiles too, so existing sizes which refer to them are rendered.
+func (s *ImageService) loadOverlays(ctx context.Context, sizes []model.SizeRequest) error {
+	overlays := make(map[string]*model.Overlay)
+	for i, size := range sizes {
+		if size.Watermark == "" {
+			continue
+		}
+
+		overlay, ok := overlays[size.Watermark]
+		if !ok {
+			var err error
+			if overlay, err = s.loadOverlay(ctx, size.Watermark); err != nil {
+				return err
+			}
+			overlays[size.Watermark] = overlay
+		}
+		sizes[i].Overlay = overlay
+	}
+
+	return nil
+}
+
+func (s *ImageService) loadOverlay(ctx context.Context, id string) (*model.Overlay, error) {
+	watermark, err := s.watermarks.GetWatermark(ctx, id)
+	if err == errors.NotFound {
+		return nil, errors.InvalidParams{{Param: "watermark", Message: "unknown watermark " + id}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := s.storage.Read(ctx, watermark.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeReader(reader)
+
+	img, err := s.resizer.Decode(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return watermark.Overlay(img), nil
+}
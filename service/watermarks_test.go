@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	repositorymemory "github.com/portey/image-resizer/repository/memory"
+	"github.com/portey/image-resizer/resizer"
+	storagememory "github.com/portey/image-resizer/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageService_Watermarks(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+	source, err := imaging.Decode(bytes.NewReader(content))
+	assert.NoError(t, err)
+
+	overlay := func() *model.ImageUpload {
+		var buf bytes.Buffer
+		assert.NoError(t, imaging.Encode(&buf, imaging.Resize(source, 64, 0, imaging.Box), imaging.PNG))
+		return &model.ImageUpload{Content: &buf, Filename: "logo.png", Size: int64(buf.Len()), MimeType: "image/png"}
+	}
+	logo := model.Watermark{ID: "logo", Position: model.AnchorBottomRight, Opacity: 0.5, Scale: 0.25}
+
+	// new profiles need the overlay
+	_, err = srv.SaveWatermark(ctx, logo, nil)
+	assert.Equal(t, errors.InvalidParams{{Param: "overlay", Message: "required"}}, err)
+	_, err = srv.SaveWatermark(ctx, model.Watermark{ID: "logo", Opacity: 2, Scale: 0.25}, overlay())
+	assert.Equal(t, errors.InvalidParams{{Param: "Opacity", Message: "max"}}, err)
+
+	saved, err := srv.SaveWatermark(ctx, logo, overlay())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, saved.Version)
+	assert.Equal(t, []string{saved.Path}, storage.Keys())
+
+	plain := model.SizeRequest{Width: 100, Height: 100}
+	marked := model.SizeRequest{Width: 100, Height: 100, Watermark: "logo"}
+	image, err := srv.Upload(ctx, model.ImageUpload{
+		Content:  bytes.NewReader(content),
+		Filename: "image.jpg",
+		Size:     int64(len(content)),
+		MimeType: "image/jpeg",
+	}, []model.SizeRequest{plain, marked})
+	assert.NoError(t, err)
+
+	// the watermark is a part of the size identity
+	assert.Len(t, image.Sizes, 2)
+	assert.Equal(t, "logo", image.Sizes[1].Watermark)
+	assert.NotEqual(t, image.Sizes[0].Path, image.Sizes[1].Path)
+	assert.True(t, image.HasResizedSize(marked.WithSourceFormat(image.MimeType)))
+
+	_, err = srv.Resize(ctx, image.ID, []model.SizeRequest{{Width: 50, Height: 50, Watermark: "unknown"}})
+	assert.Equal(t, errors.InvalidParams{{Param: "watermark", Message: "unknown watermark unknown"}}, err)
+
+	// updates keep the overlay unless it's replaced
+	tiled := logo
+	tiled.Tile = true
+	updated, err := srv.SaveWatermark(ctx, tiled, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, saved.Path, updated.Path)
+	assert.Equal(t, 2, updated.Version)
+
+	// the replaced overlay is removed by a background job, running renders may read it
+	replaced, err := srv.SaveWatermark(ctx, tiled, overlay())
+	assert.NoError(t, err)
+	assert.NotEqual(t, saved.Path, replaced.Path)
+	assert.Contains(t, storage.Keys(), saved.Path)
+	srv.processJob(ctx, <-srv.queue)
+	assert.NotContains(t, storage.Keys(), saved.Path)
+
+	// profiles used by presets can't be deleted
+	_, err = srv.SavePreset(ctx, "card", marked, false)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.InvalidParams{{Param: "id", Message: "watermark is used by preset card"}}, srv.DeleteWatermark(ctx, "logo"))
+	assert.NoError(t, srv.DeletePreset(ctx, "card"))
+
+	assert.NoError(t, srv.DeleteWatermark(ctx, "logo"))
+	assert.Equal(t, errors.NotFound, srv.DeleteWatermark(ctx, "logo"))
+
+	watermarks, err := srv.Watermarks(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, watermarks)
+
+	// presets can't refer to deleted profiles
+	_, err = srv.SavePreset(ctx, "card", marked, false)
+	assert.Equal(t, errors.InvalidParams{{Param: "watermark", Message: "unknown watermark logo"}}, err)
+
+	// sizes composited with the deleted profile are rendered from the kept overlay
+	assert.Contains(t, storage.Keys(), replaced.Path)
+	rotated, err := srv.RotateImage(ctx, image.ID, 90)
+	assert.NoError(t, err)
+	assert.Equal(t, "logo", rotated.Sizes[1].Watermark)
+	assert.Equal(t, model.SizeStatusReady, rotated.Sizes[1].Status)
+
+	// a saved profile is restored with its overlay
+	restored, err := srv.SaveWatermark(ctx, logo, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, replaced.Path, restored.Path)
+	watermarks, err = srv.Watermarks(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, watermarks, 1)
+}
+
+type watermarkHooks struct {
+	*repositorymemory.Repository
+	watermarkSaved func(watermark model.Watermark)
+	savingPreset   func()
+}
+
+func (r *watermarkHooks) SaveWatermark(ctx context.Context, version int, watermark model.Watermark) error {
+	if err := r.Repository.SaveWatermark(ctx, version, watermark); err != nil {
+		return err
+	}
+	if r.watermarkSaved != nil {
+		r.watermarkSaved(watermark)
+	}
+
+	return nil
+}
+
+func (r *watermarkHooks) SavePreset(ctx context.Context, version int, preset model.Preset) error {
+	if r.savingPreset != nil {
+		r.savingPreset()
+	}
+
+	return r.Repository.SavePreset(ctx, version, preset)
+}
+
+func TestImageService_DeleteWatermarkConcurrently(t *testing.T) {
+	ctx := context.Background()
+	repo := &watermarkHooks{Repository: repositorymemory.New()}
+	srv := New(storagememory.New(), resizer.New(0), repo, repo, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	logo := model.Watermark{ID: "logo", Path: "logo.png", Position: model.AnchorBottomRight, Opacity: 0.5, Scale: 0.25, Version: 1}
+	assert.NoError(t, repo.Repository.SaveWatermark(ctx, 0, logo))
+	marked := model.SizeRequest{Width: 100, Height: 100, Watermark: "logo"}
+
+	// a preset which checked the profile before it was deleted is saved meanwhile, the profile is restored
+	repo.watermarkSaved = func(watermark model.Watermark) {
+		repo.watermarkSaved = nil
+		assert.True(t, watermark.Deleted)
+		assert.NoError(t, repo.Repository.SavePreset(ctx, 0, model.Preset{Name: "card", Size: marked, Version: 1}))
+	}
+	assert.Equal(t, errors.InvalidParams{{Param: "id", Message: "watermark is used by preset card"}}, srv.DeleteWatermark(ctx, "logo"))
+	watermark, err := repo.GetWatermark(ctx, "logo")
+	assert.NoError(t, err)
+	assert.False(t, watermark.Deleted)
+	assert.NoError(t, repo.DeletePreset(ctx, "card"))
+
+	// the profile is deleted after the preset checked it, the saved preset is reverted
+	repo.savingPreset = func() {
+		repo.savingPreset = nil
+		assert.NoError(t, srv.DeleteWatermark(ctx, "logo"))
+	}
+	_, err = srv.SavePreset(ctx, "card", marked, false)
+	assert.Equal(t, errors.InvalidParams{{Param: "watermark", Message: "unknown watermark logo"}}, err)
+	_, err = repo.GetPreset(ctx, "card")
+	assert.Equal(t, errors.NotFound, err)
+}
+
+func TestImageService_WatermarkOfNewSizes(t *testing.T) {
+	ctx := context.Background()
+	repo := repositorymemory.New()
+	storage := storagememory.New()
+	srv := New(storage, resizer.New(0), repo, repo, repo, repo, 1, Limits{}, MetadataConfig{})
+
+	content, err := ioutil.ReadFile("../resizer/fixtures/image.jpg")
+	assert.NoError(t, err)
+	upload := func() model.ImageUpload {
+		return model.ImageUpload{Content: bytes.NewReader(content), Filename: "image.jpg", Size: int64(len(content)), MimeType: "image/jpeg"}
+	}
+	source, err := imaging.Decode(bytes.NewReader(content))
+	assert.NoError(t, err)
+	var logo bytes.Buffer
+	assert.NoError(t, imaging.Encode(&logo, imaging.Resize(source, 64, 0, imaging.Box), imaging.PNG))
+	_, err = srv.SaveWatermark(ctx, model.Watermark{ID: "logo", Opacity: 0.5, Scale: 0.25}, &model.ImageUpload{
+		Content: &logo, Filename: "logo.png", Size: int64(logo.Len()), MimeType: "image/png",
+	})
+	assert.NoError(t, err)
+
+	marked := model.SizeRequest{Width: 100, Height: 100, Watermark: "logo"}
+	image, err := srv.Upload(ctx, upload(), []model.SizeRequest{marked})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.DeleteWatermark(ctx, "logo"))
+	keys := len(storage.Keys())
+
+	deleted := errors.InvalidParams{{Param: "watermark", Message: "unknown watermark logo"}}
+	other := model.SizeRequest{Width: 50, Height: 50, Watermark: "logo"}
+	_, err = srv.Upload(ctx, upload(), []model.SizeRequest{other})
+	assert.Equal(t, deleted, err)
+	_, err = srv.UploadAsync(ctx, upload(), []model.SizeRequest{other})
+	assert.Equal(t, deleted, err)
+	_, err = srv.Resize(ctx, image.ID, []model.SizeRequest{other})
+	assert.Equal(t, deleted, err)
+	_, err = srv.Variant(ctx, image.ID, other)
+	assert.Equal(t, deleted, err)
+
+	// the size composited before the profile was deleted is still served
+	size, err := srv.Variant(ctx, image.ID, marked)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Sizes[0].Path, size.Path)
+
+	// unknown profiles are rejected before the async upload stores the original
+	_, err = srv.UploadAsync(ctx, upload(), []model.SizeRequest{{Width: 100, Height: 100, Watermark: "unknown"}})
+	assert.Equal(t, errors.InvalidParams{{Param: "watermark", Message: "unknown watermark unknown"}}, err)
+	assert.Len(t, storage.Keys(), keys)
+}
@@ -0,0 +1,98 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+)
+
+const (
+	ExpiresParam   = "exp"
+	KeyIDParam     = "kid"
+	SignatureParam = "sig"
+)
+
+type (
+	Key struct {
+		ID     string
+		Secret string
+	}
+
+	// Signer signs URLs with the first key and accepts signatures made with any of the keys,
+	// so a new key can be added in front of the old ones before the old ones are removed.
+	Signer struct {
+		keys []Key
+	}
+)
+
+func New(keys []Key) *Signer {
+	return &Signer{
+		keys: keys,
+	}
+}
+
+func (s *Signer) Sign(path string, query url.Values, expiresAt time.Time) (url.Values, error) {
+	if len(s.keys) == 0 {
+		return nil, errors.Internal
+	}
+	key := s.keys[0]
+
+	signed := url.Values{}
+	for param, values := range query {
+		signed[param] = values
+	}
+	signed.Set(ExpiresParam, strconv.FormatInt(expiresAt.Unix(), 10))
+	signed.Set(KeyIDParam, key.ID)
+	signed.Set(SignatureParam, sign(key, path, signed))
+
+	return signed, nil
+}
+
+func (s *Signer) Verify(path string, query url.Values, now time.Time) error {
+	expiresAt, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return errors.InvalidSignature
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get(SignatureParam))
+	if err != nil {
+		return errors.InvalidSignature
+	}
+
+	for _, key := range s.keys {
+		if key.ID != query.Get(KeyIDParam) {
+			continue
+		}
+
+		expected, _ := base64.RawURLEncoding.DecodeString(sign(key, path, query))
+		if !hmac.Equal(signature, expected) {
+			return errors.InvalidSignature
+		}
+		if now.Unix() > expiresAt {
+			return errors.SignatureExpired
+		}
+
+		return nil
+	}
+
+	return errors.InvalidSignature
+}
+
+func sign(key Key, path string, query url.Values) string {
+	payload := url.Values{}
+	for param, values := range query {
+		if param != SignatureParam {
+			payload[param] = values
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(key.Secret))
+	_, _ = mac.Write([]byte(path + "?" + payload.Encode()))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
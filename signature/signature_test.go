@@ -0,0 +1,47 @@
+package signature
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_Verify(t *testing.T) {
+	now := time.Now()
+	oldKey := Key{ID: "old", Secret: "old-secret"}
+	newKey := Key{ID: "new", Secret: "new-secret"}
+
+	signed, err := New([]Key{oldKey}).Sign("/img/id/100x100.png", url.Values{"mode": {"fill"}}, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "old", signed.Get(KeyIDParam))
+
+	// the old key is still accepted after rotation
+	signer := New([]Key{newKey, oldKey})
+	assert.NoError(t, signer.Verify("/img/id/100x100.png", signed, now))
+
+	// expired
+	assert.Equal(t, errors.SignatureExpired, signer.Verify("/img/id/100x100.png", signed, now.Add(time.Hour)))
+
+	// tampered path and params
+	assert.Equal(t, errors.InvalidSignature, signer.Verify("/img/id/1000x1000.png", signed, now))
+	tampered := url.Values{}
+	for param, values := range signed {
+		tampered[param] = values
+	}
+	tampered.Set("mode", "fit")
+	assert.Equal(t, errors.InvalidSignature, signer.Verify("/img/id/100x100.png", tampered, now))
+
+	// removed key
+	assert.Equal(t, errors.InvalidSignature, New([]Key{newKey}).Verify("/img/id/100x100.png", signed, now))
+
+	// unsigned
+	assert.Equal(t, errors.InvalidSignature, signer.Verify("/img/id/100x100.png", url.Values{}, now))
+}
+
+func TestSigner_SignWithoutKeys(t *testing.T) {
+	_, err := New(nil).Sign("/img/id/100x100.png", url.Values{}, time.Now())
+	assert.Error(t, err)
+}
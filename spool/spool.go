@@ -0,0 +1,102 @@
+// Package spool buffers streams which are read more than once without keeping large streams in memory.
+package spool
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Buffer keeps data in memory up to the limit and moves it to a temp file once the limit is exceeded.
+type Buffer struct {
+	limit  int64
+	memory bytes.Buffer
+	file   *os.File
+	size   int64
+}
+
+// New returns a buffer which keeps all data in memory if the limit isn't positive.
+func New(limit int64) *Buffer {
+	return &Buffer{limit: limit}
+}
+
+func (b *Buffer) Write(p []byte) (int, error) {
+	if b.file == nil && b.limit > 0 && b.size+int64(len(p)) > b.limit {
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+
+	var (
+		n   int
+		err error
+	)
+	if b.file != nil {
+		n, err = b.file.Write(p)
+	} else {
+		n, err = b.memory.Write(p)
+	}
+	b.size += int64(n)
+
+	return n, err
+}
+
+func (b *Buffer) spill() error {
+	file, err := ioutil.TempFile("", "spool-")
+	if err != nil {
+		return err
+	}
+	if _, err := b.memory.WriteTo(file); err != nil {
+		closeFile(file)
+		return err
+	}
+
+	b.file = file
+	b.memory = bytes.Buffer{}
+
+	return nil
+}
+
+func (b *Buffer) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(writerOnly{b}, r)
+}
+
+func (b *Buffer) Size() int64 {
+	return b.size
+}
+
+// Reader reads the data from the start, readers are independent of each other.
+func (b *Buffer) Reader() io.ReadSeeker {
+	if b.file != nil {
+		return io.NewSectionReader(b.file, 0, b.size)
+	}
+
+	return bytes.NewReader(b.memory.Bytes())
+}
+
+func (b *Buffer) Close() error {
+	if b.file == nil {
+		return nil
+	}
+
+	file := b.file
+	b.file = nil
+	b.memory = bytes.Buffer{}
+
+	return closeFile(file)
+}
+
+func closeFile(file *os.File) error {
+	closeErr := file.Close()
+	if err := os.Remove(file.Name()); err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// writerOnly hides ReadFrom of the buffer from io.Copy.
+type writerOnly struct {
+	io.Writer
+}
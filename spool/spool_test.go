@@ -0,0 +1,48 @@
+package spool
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	f := func(limit int64, content string, spilled bool) {
+		b := New(limit)
+
+		n, err := b.ReadFrom(strings.NewReader(content))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), n)
+		assert.Equal(t, int64(len(content)), b.Size())
+		assert.Equal(t, spilled, b.file != nil)
+
+		// the data can be read more than once
+		for i := 0; i < 2; i++ {
+			res, err := ioutil.ReadAll(b.Reader())
+			assert.NoError(t, err)
+			assert.Equal(t, content, string(res))
+		}
+
+		var name string
+		if b.file != nil {
+			name = b.file.Name()
+		}
+		assert.NoError(t, b.Close())
+		if name != "" {
+			_, err := os.Stat(name)
+			assert.True(t, os.IsNotExist(err))
+		}
+	}
+
+	f(100, "Some content", false)
+	f(12, "Some content", false)
+	f(5, "Some content", true)
+	// no limit
+	f(0, "Some content", false)
+	f(-1, strings.Repeat("a", 100000), false)
+	f(0, "", false)
+	f(1000, strings.Repeat("a", 100000), true)
+}
@@ -0,0 +1,156 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// tempDir keeps files which are being written, it's inside the root so files are renamed within one filesystem.
+const tempDir = ".tmp"
+
+type Config struct {
+	RootPath string
+}
+
+// Storage renames written temp files, so readers never see partially written objects.
+type Storage struct {
+	rootPath string
+}
+
+func New(config Config) (*Storage, error) {
+	if err := os.MkdirAll(filepath.Join(config.RootPath, tempDir), 0755); err != nil {
+		return nil, err
+	}
+
+	return &Storage{rootPath: config.RootPath}, nil
+}
+
+func (s *Storage) Read(ctx context.Context, path string) (io.Reader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(s.absolutePath(path))
+	if err != nil {
+		return nil, toServiceError(err)
+	}
+
+	return file, nil
+}
+
+func (s *Storage) Upload(ctx context.Context, data io.Reader, format model.Format) (string, error) {
+	name := storage.OriginalKey(time.Now(), format)
+	return name, s.write(ctx, data, func() string { return name })
+}
+
+func (s *Storage) UploadResized(ctx context.Context, data io.Reader, width, height int, format model.Format) (string, error) {
+	hash := sha256.New()
+	var name string
+	err := s.write(ctx, io.TeeReader(data, hash), func() string {
+		name = storage.VariantKey(hash.Sum(nil), width, height, format)
+		return name
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// Modified reports the last write, a replaced file has the time it was written.
+func (s *Storage) Modified(ctx context.Context, path string) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	info, err := os.Stat(s.absolutePath(path))
+	if err != nil {
+		return time.Time{}, toServiceError(err)
+	}
+
+	return info.ModTime(), nil
+}
+
+func (s *Storage) Delete(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := os.Remove(s.absolutePath(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return toServiceError(err)
+}
+
+func (s *Storage) write(ctx context.Context, data io.Reader, key func() string) error {
+	file, err := ioutil.TempFile(filepath.Join(s.rootPath, tempDir), "upload-")
+	if err != nil {
+		return toServiceError(err)
+	}
+	defer func() {
+		// the temp file is left only if the rename failed
+		if err := os.Remove(file.Name()); err != nil && !os.IsNotExist(err) {
+			log.Error("can't remove temp file ", err)
+		}
+	}()
+
+	_, err = io.Copy(file, data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return toServiceError(err)
+	}
+
+	target := s.absolutePath(key())
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return toServiceError(err)
+	}
+
+	return toServiceError(os.Rename(file.Name(), target))
+}
+
+// absolutePath doesn't let keys point outside of the root.
+func (s *Storage) absolutePath(key string) string {
+	return filepath.Join(s.rootPath, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func toServiceError(err error) error {
+	if err == nil {
+		return err
+	}
+
+	// errors of the uploaded content, e.g. exceeded limits, are passed as is
+	if serviceErr, ok := err.(errors.ServiceError); ok {
+		return serviceErr
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	if os.IsNotExist(err) {
+		return errors.NotFound
+	}
+
+	log.Error(err)
+
+	return errors.Internal
+}
@@ -0,0 +1,64 @@
+package filesystem
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/portey/image-resizer/service/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStorage(t *testing.T) (*Storage, func()) {
+	root, err := ioutil.TempDir("", "storage-")
+	assert.NoError(t, err)
+
+	s, err := New(Config{RootPath: root})
+	assert.NoError(t, err)
+
+	return s, func() {
+		assert.NoError(t, os.RemoveAll(root))
+	}
+}
+
+func TestStorage_TempFiles(t *testing.T) {
+	s, cleanup := newStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	_, err := s.Upload(ctx, strings.NewReader("Some content"), model.FormatPNG)
+	assert.NoError(t, err)
+	_, err = s.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+	assert.NoError(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = s.UploadResized(canceled, strings.NewReader("Other content"), 200, 200, model.FormatPNG)
+	assert.Equal(t, context.Canceled, err)
+
+	// temp files are renamed or removed
+	temp, err := ioutil.ReadDir(filepath.Join(s.rootPath, tempDir))
+	assert.NoError(t, err)
+	assert.Empty(t, temp)
+	_, err = os.Stat(filepath.Join(s.rootPath, "200_200"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStorage_AbsolutePath(t *testing.T) {
+	s := &Storage{rootPath: "/data"}
+
+	assert.Equal(t, "/data/2020/05/01/origin/a.png", s.absolutePath("2020/05/01/origin/a.png"))
+	assert.Equal(t, "/data/etc/passwd", s.absolutePath("../../etc/passwd"))
+}
+
+func TestStorage_Conformance(t *testing.T) {
+	servicetest.TestStorage(t, func(t *testing.T) (service.Storage, func()) {
+		return newStorage(t)
+	})
+}
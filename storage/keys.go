@@ -0,0 +1,21 @@
+// Package storage defines keys of stored objects shared by storage backends.
+package storage
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/portey/image-resizer/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+func OriginalKey(now time.Time, format model.Format) string {
+	return path.Join(now.Format("2006/01/02"), "origin", uuid.NewV4().String()+"."+format.Extension())
+}
+
+// VariantKey is content-addressed, so identical renders share the stored object.
+func VariantKey(hash []byte, width, height int, format model.Format) string {
+	return path.Join(fmt.Sprintf("%d_%d", width, height), hex.EncodeToString(hash)+"."+format.Extension())
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/portey/image-resizer/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginalKey(t *testing.T) {
+	now := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	key := OriginalKey(now, model.FormatJPEG)
+	assert.Regexp(t, regexp.MustCompile(`^2020/05/01/origin/[0-9a-f-]{36}\.jpeg$`), key)
+	assert.NotEqual(t, key, OriginalKey(now, model.FormatJPEG))
+}
+
+func TestVariantKey(t *testing.T) {
+	hash := sha256.Sum256([]byte("Some content"))
+
+	key := VariantKey(hash[:], 100, 50, model.FormatPNG)
+	assert.Equal(t, "100_50/"+"9c6609fc5111405ea3f5bb3d1f6b5a5efd19a0cec53d85893fd96d265439cd5b.png", key)
+	assert.Equal(t, key, VariantKey(hash[:], 100, 50, model.FormatPNG))
+}
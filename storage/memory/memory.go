@@ -0,0 +1,150 @@
+// Package memory keeps objects in memory with the same keys as the MinIO storage.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/portey/image-resizer/errors"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+type Storage struct {
+	mu      sync.RWMutex
+	objects map[string]object
+}
+
+type object struct {
+	content  []byte
+	modified time.Time
+}
+
+func New() *Storage {
+	return &Storage{objects: make(map[string]object)}
+}
+
+func (s *Storage) Read(ctx context.Context, path string) (io.Reader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[path]
+	if !ok {
+		return nil, errors.NotFound
+	}
+
+	// stored objects are never modified, so readers can share them
+	return bytes.NewReader(object.content), nil
+}
+
+func (s *Storage) Modified(ctx context.Context, path string) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[path]
+	if !ok {
+		return time.Time{}, errors.NotFound
+	}
+
+	return object.modified, nil
+}
+
+func (s *Storage) Upload(ctx context.Context, data io.Reader, format model.Format) (string, error) {
+	content, err := readAll(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	name := storage.OriginalKey(time.Now(), format)
+	s.put(name, content)
+
+	return name, nil
+}
+
+func (s *Storage) UploadResized(ctx context.Context, data io.Reader, width, height int, format model.Format) (string, error) {
+	content, err := readAll(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(content)
+	name := storage.VariantKey(hash[:], width, height, format)
+	s.put(name, content)
+
+	return name, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, path)
+
+	return nil
+}
+
+func (s *Storage) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (s *Storage) put(name string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[name] = object{content: content, modified: time.Now()}
+}
+
+func readAll(ctx context.Context, data io.Reader) ([]byte, error) {
+	content, err := ioutil.ReadAll(data)
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return content, toServiceError(err)
+}
+
+func toServiceError(err error) error {
+	if err == nil {
+		return err
+	}
+
+	// errors of the uploaded content, e.g. exceeded limits, are passed as is
+	if serviceErr, ok := err.(errors.ServiceError); ok {
+		return serviceErr
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+
+	log.Error(err)
+
+	return errors.Internal
+}
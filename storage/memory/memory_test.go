@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/portey/image-resizer/service/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_Keys(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	_, err := s.Upload(ctx, strings.NewReader("Some content"), model.FormatPNG)
+	assert.NoError(t, err)
+	_, err = s.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+	assert.NoError(t, err)
+	_, err = s.UploadResized(ctx, strings.NewReader("Some content"), 100, 100, model.FormatPNG)
+	assert.NoError(t, err)
+	assert.Len(t, s.Keys(), 2)
+
+	// failed uploads leave nothing
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = s.UploadResized(canceled, strings.NewReader("Other content"), 100, 100, model.FormatPNG)
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, s.Keys(), 2)
+}
+
+func TestStorage_Conformance(t *testing.T) {
+	servicetest.TestStorage(t, func(t *testing.T) (service.Storage, func()) {
+		return New(), func() {}
+	})
+}
@@ -1,19 +1,22 @@
 package minio
 
 import (
-	"bytes"
 	"context"
-	"fmt"
+	"crypto/sha256"
 	"io"
 	"path"
 	"time"
 
 	"github.com/minio/minio-go/v6"
 	"github.com/portey/image-resizer/errors"
-	uuid "github.com/satori/go.uuid"
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/spool"
+	"github.com/portey/image-resizer/storage"
 	log "github.com/sirupsen/logrus"
 )
 
+const minPartSize = 5 << 20
+
 type Config struct {
 	Endpoint        string
 	AccessKeyID     string
@@ -22,12 +25,15 @@ type Config struct {
 	BucketName      string
 	Location        string
 	RootPath        string
+	// PartSize bounds memory used by an upload, smaller sizes are raised to 5 MiB.
+	PartSize uint64
 }
 
 type Storage struct {
 	client     *minio.Client
 	bucketName string
 	rootPath   string
+	partSize   uint64
 }
 
 func New(config Config) (*Storage, error) {
@@ -47,43 +53,78 @@ func New(config Config) (*Storage, error) {
 		}
 	}
 
+	partSize := config.PartSize
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+
 	return &Storage{
 		client:     client,
 		bucketName: config.BucketName,
 		rootPath:   config.RootPath,
+		partSize:   partSize,
 	}, nil
 }
 
 func (s *Storage) Read(ctx context.Context, path string) (io.Reader, error) {
 	res, err := s.client.GetObjectWithContext(ctx, s.bucketName, s.absolutePath(path), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, toServiceError(err)
+	}
 
-	return res, toServiceError(err)
+	// the object is requested lazily, stat reports a missing object before it's read
+	if _, err := res.Stat(); err != nil {
+		res.Close()
+		return nil, toServiceError(err)
+	}
+
+	return res, nil
 }
 
-func (s *Storage) Upload(ctx context.Context, data io.Reader) (string, error) {
-	name := s.generatePath("origin")
-	return name, s.doUpload(ctx, name, data)
+func (s *Storage) Upload(ctx context.Context, data io.Reader, format model.Format) (string, error) {
+	name := storage.OriginalKey(time.Now(), format)
+	return name, s.put(ctx, name, format.MimeType(), data, -1)
 }
 
-func (s *Storage) UploadResized(ctx context.Context, data io.Reader, width, height int) (string, error) {
-	name := s.generatePath(fmt.Sprintf("%d_%d", width, height))
-	return name, s.doUpload(ctx, name, data)
+func (s *Storage) UploadResized(ctx context.Context, data io.Reader, width, height int, format model.Format) (string, error) {
+	buf := spool.New(int64(s.partSize))
+	defer func() {
+		if err := buf.Close(); err != nil {
+			log.Error("can't remove spooled variant ", err)
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(buf, hash), data); err != nil {
+		return "", toServiceError(err)
+	}
+
+	name := storage.VariantKey(hash.Sum(nil), width, height, format)
+	return name, s.put(ctx, name, format.MimeType(), buf.Reader(), buf.Size())
 }
 
-func (s *Storage) doUpload(ctx context.Context, path string, content io.Reader) error {
-	buf := &bytes.Buffer{}
-	n, err := io.Copy(buf, content)
+// Modified reports the last upload, an overwritten object has the time of the last upload.
+func (s *Storage) Modified(ctx context.Context, path string) (time.Time, error) {
+	info, err := s.client.StatObjectWithContext(ctx, s.bucketName, s.absolutePath(path), minio.StatObjectOptions{})
 	if err != nil {
-		return toServiceError(err)
+		return time.Time{}, toServiceError(err)
 	}
 
-	_, err = s.client.PutObjectWithContext(
+	return info.LastModified, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, path string) error {
+	return toServiceError(s.client.RemoveObject(s.bucketName, s.absolutePath(path)))
+}
+
+func (s *Storage) put(ctx context.Context, path, contentType string, content io.Reader, size int64) error {
+	_, err := s.client.PutObjectWithContext(
 		ctx,
 		s.bucketName,
 		s.absolutePath(path),
-		buf,
-		n,
-		minio.PutObjectOptions{},
+		content,
+		size,
+		minio.PutObjectOptions{ContentType: contentType, PartSize: s.partSize},
 	)
 
 	return toServiceError(err)
@@ -93,15 +134,22 @@ func (s *Storage) absolutePath(relativePath string) string {
 	return path.Join(s.rootPath, relativePath)
 }
 
-func (s *Storage) generatePath(size string) string {
-	return path.Join(time.Now().Format("2006/01/02"), size, uuid.NewV4().String()+".jpeg")
-}
-
 func toServiceError(err error) error {
 	if err == nil {
 		return err
 	}
 
+	// errors of the uploaded content, e.g. exceeded limits, are passed as is
+	if serviceErr, ok := err.(errors.ServiceError); ok {
+		return serviceErr
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return errors.NotFound
+	}
+
 	log.Error(err)
 
 	return errors.Internal
@@ -7,6 +7,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/portey/image-resizer/model"
+	"github.com/portey/image-resizer/service"
+	"github.com/portey/image-resizer/service/servicetest"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,7 +32,7 @@ func TestStorage_Upload(t *testing.T) {
 	assert.NoError(t, err)
 
 	reader := strings.NewReader("Some content")
-	path, err := client.UploadResized(ctx, reader, 100, 100)
+	path, err := client.UploadResized(ctx, reader, 100, 100, model.FormatJPEG)
 	assert.NoError(t, err)
 
 	res, err := client.Read(ctx, path)
@@ -38,3 +42,25 @@ func TestStorage_Upload(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Some content", string(readResult))
 }
+
+func TestStorage_Conformance(t *testing.T) {
+	if os.Getenv("INTEGRATION_TEST") != "YES" {
+		t.Skip()
+	}
+
+	servicetest.TestStorage(t, func(t *testing.T) (service.Storage, func()) {
+		// every check gets its own root path, so checks start empty
+		client, err := New(Config{
+			Endpoint:        "127.0.0.1:9000",
+			AccessKeyID:     "minioadmin",
+			SecretAccessKey: "minioadmin",
+			SSL:             false,
+			BucketName:      "test2",
+			Location:        "us-east-1",
+			RootPath:        "conformance/" + uuid.NewV4().String(),
+		})
+		assert.NoError(t, err)
+
+		return client, func() {}
+	})
+}